@@ -0,0 +1,23 @@
+// Package api holds the contracts of the service: the OpenAPI document of the
+// HTTP routes and, under news/, the gRPC definitions.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed openapi.yaml
+var openAPI []byte
+
+// OpenAPI returns the OpenAPI document as JSON
+func OpenAPI() ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(openAPI, &doc); err != nil {
+		return nil, fmt.Errorf("parse OpenAPI document: %w", err)
+	}
+	return json.Marshal(doc)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"news_service/internal/repository/mongodb"
+)
+
+func runIndexes(ctx context.Context, env *environment, args []string) error {
+	fs := flag.NewFlagSet("indexes", flag.ContinueOnError)
+	check := fs.Bool("check", false, "only report missing and unexpected indexes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ensure := mongodb.EnsureIndexes
+	if *check {
+		ensure = mongodb.CheckIndexes
+	}
+
+	reports, err := ensure(ctx, env.client, env.cfg.Mongo.Database)
+	if err != nil {
+		return err
+	}
+
+	ok := true
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Fprintln(os.Stdout)
+		}
+		fmt.Fprintf(os.Stdout, "collection: %s\n", report.Collection)
+		fmt.Fprintf(os.Stdout, "created:    %s\n", list(report.Created))
+		fmt.Fprintf(os.Stdout, "missing:    %s\n", list(report.Missing))
+		fmt.Fprintf(os.Stdout, "unexpected: %s\n", list(report.Unexpected))
+		ok = ok && report.OK()
+	}
+
+	if *check && !ok {
+		return errors.New("indexes do not match the declared ones")
+	}
+	return nil
+}
+
+func list(names []string) string {
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, ", ")
+}
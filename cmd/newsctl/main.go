@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/config"
+	"news_service/internal/logging"
+)
+
+const usage = `Usage: newsctl [config flags] <command> [command flags]
+
+Commands:
+  export     write every article as JSON Lines or CSV
+  import     read articles from a JSON Lines or CSV file
+  indexes    create missing indexes, or report them with -check
+  migrate    apply, revert or list data migrations (up, down, status)
+
+Config flags are the same as for the server, e.g. -config, -mongodb-uri and
+-mongodb-database. Run "newsctl <command> -h" for command flags.
+`
+
+// command runs a subcommand with its own arguments
+type command func(ctx context.Context, env *environment, args []string) error
+
+var commands = map[string]command{
+	"export":  runExport,
+	"import":  runImport,
+	"indexes": runIndexes,
+	"migrate": runMigrate,
+}
+
+// environment holds what every command needs
+type environment struct {
+	cfg    *config.Config
+	client *mongo.Client
+	logger *slog.Logger
+}
+
+func main() {
+	cfg, args, err := config.LoadCommand("newsctl", os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", args[0], usage)
+		os.Exit(2)
+	}
+
+	logger, err := logging.New(os.Stderr, "text", cfg.Logging.Level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, cfg, logger, cmd, args[1:]); err != nil {
+		logger.Error(args[0]+" failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, cfg *config.Config, logger *slog.Logger, cmd command, args []string) error {
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.Mongo.ConnectTimeout)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().
+		ApplyURI(cfg.Mongo.URI).
+		SetConnectTimeout(cfg.Mongo.ConnectTimeout).
+		SetServerSelectionTimeout(cfg.Mongo.ServerSelectionTimeout))
+	if err != nil {
+		return fmt.Errorf("connect to MongoDB: %w", err)
+	}
+	defer client.Disconnect(context.Background())
+
+	if err := client.Ping(connectCtx, nil); err != nil {
+		return fmt.Errorf("ping MongoDB: %w", err)
+	}
+
+	return cmd(ctx, &environment{cfg: cfg, client: client, logger: logger}, args)
+}
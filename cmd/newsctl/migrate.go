@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"news_service/internal/migrate"
+	"news_service/internal/repository/mongodb"
+)
+
+func runMigrate(ctx context.Context, env *environment, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: newsctl migrate <up|down|status> [flags]")
+	}
+	action, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only print the migrations that would run")
+	target := fs.Int64("to", 0, "version to migrate up to, or down to (exclusive)")
+	all := fs.Bool("all", false, "with down, revert every applied migration")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	m, err := migrate.New(env.client.Database(env.cfg.Mongo.Database), mongodb.Migrations(), env.logger)
+	if err != nil {
+		return err
+	}
+
+	opts := migrate.Options{DryRun: *dryRun, Target: *target}
+	var migrations []migrate.Migration
+	switch action {
+	case "status":
+		return printStatus(ctx, m)
+	case "up":
+		migrations, err = m.Up(ctx, opts)
+	case "down":
+		if *all {
+			opts.Target = -1
+		}
+		migrations, err = m.Down(ctx, opts)
+	default:
+		return fmt.Errorf("unknown migrate action %q", action)
+	}
+
+	verb := map[string]string{"up": "applied", "down": "reverted"}[action]
+	if *dryRun {
+		verb = "would be " + verb
+	}
+	for _, migration := range migrations {
+		fmt.Fprintf(os.Stdout, "%s: %d %s\n", verb, migration.Version, migration.Name)
+	}
+	if len(migrations) == 0 && err == nil {
+		fmt.Fprintln(os.Stdout, "nothing to do")
+	}
+	return err
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, status := range statuses {
+		applied := "pending"
+		if status.Applied {
+			applied = status.AppliedAt.Local().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, applied)
+	}
+	return w.Flush()
+}
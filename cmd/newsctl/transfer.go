@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"news_service/internal/domain"
+	"news_service/internal/repository/mongodb"
+	"news_service/internal/service"
+	"news_service/internal/transfer"
+)
+
+// discardPublisher drops events, nothing in this process listens for them
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(...domain.Event) {}
+
+func runExport(ctx context.Context, env *environment, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "-", "output file, - for stdout")
+	formatName := fs.String("format", "", "jsonl or csv, guessed from the output file by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	format, err := fileFormat(*formatName, *output)
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "-" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	repo := mongodb.NewNewsRepository(env.client, env.cfg.Mongo.Database)
+	count, err := transfer.Export(ctx, repo, w, format)
+	if err != nil {
+		return err
+	}
+
+	env.logger.Info("news exported", "format", format, "count", count)
+	return nil
+}
+
+func runImport(ctx context.Context, env *environment, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	formatName := fs.String("format", "", "jsonl or csv, guessed from the input file by default")
+	modeName := fs.String("mode", "skip", "skip or upsert articles that already exist")
+	preserve := fs.Bool("preserve-ids", false, "keep the IDs and timestamps of the records")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: newsctl import [flags] <file|->")
+	}
+	input := fs.Arg(0)
+
+	format, err := fileFormat(*formatName, input)
+	if err != nil {
+		return err
+	}
+	mode, err := transfer.ParseMode(*modeName)
+	if err != nil {
+		return err
+	}
+
+	r := io.Reader(os.Stdin)
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	// Through the service, so the server dispatches events for the imported
+	// articles from the outbox
+	transactor, _, err := mongodb.NewTransactor(ctx, env.client)
+	if err != nil {
+		return err
+	}
+	newsService := service.NewNewsService(
+		mongodb.NewNewsRepository(env.client, env.cfg.Mongo.Database),
+		mongodb.NewOutbox(env.client, env.cfg.Mongo.Database),
+		transactor,
+		discardPublisher{},
+	)
+	report, err := transfer.Import(ctx, newsService, r, transfer.ImportOptions{
+		Format:      format,
+		Mode:        mode,
+		PreserveIDs: *preserve,
+	})
+
+	for _, lineErr := range report.Errors {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n", input, lineErr.Line, lineErr.Error)
+	}
+	fmt.Fprintf(os.Stdout, "created: %d\nupdated: %d\nskipped: %d\nfailed:  %d\n",
+		report.Created, report.Updated, report.Skipped, report.Failed)
+
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d records failed", report.Failed)
+	}
+	return nil
+}
+
+// fileFormat parses name, or guesses the format from path when name is empty
+func fileFormat(name, path string) (transfer.Format, error) {
+	if name == "" {
+		return transfer.FormatFromPath(path), nil
+	}
+	return transfer.ParseFormat(name)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+
+	"news_service/internal/broadcast"
+	"news_service/internal/domain"
+	"news_service/internal/rpc"
+)
+
+// grpcServer serves the gRPC API on its own port next to the HTTP server
+type grpcServer struct {
+	server   *grpc.Server
+	health   *grpchealth.Server
+	news     *rpc.NewsServer
+	listener net.Listener
+}
+
+func listenGRPC(port int, adminToken string, service domain.NewsService, broadcaster *broadcast.Broadcaster, logger *slog.Logger) (*grpcServer, error) {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return nil, fmt.Errorf("listen for gRPC: %w", err)
+	}
+	news := rpc.NewNewsServer(service, broadcaster)
+	server, health := rpc.NewServer(news, adminToken, logger)
+	return &grpcServer{server: server, health: health, news: news, listener: listener}, nil
+}
+
+func (s *grpcServer) serve() error {
+	return s.server.Serve(s.listener)
+}
+
+// stop reports not serving, ends open watches and drains in-flight calls,
+// cutting them off when ctx is done
+func (s *grpcServer) stop(ctx context.Context) {
+	s.health.Shutdown()
+	s.news.Close()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
@@ -2,68 +2,244 @@ package main
 
 import (
 	"context"
-	"html/template"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"news_service/api"
+	"news_service/internal/broadcast"
+	"news_service/internal/config"
+	"news_service/internal/events"
+	"news_service/internal/graphql"
 	"news_service/internal/handler"
-	"news_service/internal/repository/mongodb"
+	"news_service/internal/health"
+	"news_service/internal/i18n"
+	"news_service/internal/logging"
+	"news_service/internal/metrics"
+	"news_service/internal/middleware"
+	"news_service/internal/repository/instrument"
 	"news_service/internal/service"
+	"news_service/internal/tracing"
+	"news_service/internal/webhook"
+	"news_service/internal/worker"
 )
 
 func main() {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	mongoURI := os.Getenv("MONGODB_URI")
-	if mongoURI == "" {
-		mongoURI = "mongodb://localhost:27017"
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(2)
 	}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
+	logger, err := logging.New(os.Stdout, cfg.Logging.Format, cfg.Logging.Level)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("invalid logging configuration", "error", err)
+		os.Exit(2)
 	}
-	defer client.Disconnect(ctx)
+	slog.SetDefault(logger)
+
+	if err := run(cfg, logger); err != nil {
+		logger.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
 
-	if err := client.Ping(ctx, nil); err != nil {
-		log.Fatal(err)
+func run(cfg *config.Config, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTimeout := cfg.Server.ShutdownTimeout
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("set up tracing: %w", err)
 	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("failed to flush traces", "error", err)
+		}
+	}()
 
-	database := os.Getenv("MONGODB_DATABASE")
-	if database == "" {
-		database = "news_service"
+	appMetrics := metrics.New()
+
+	store, err := openStorage(ctx, cfg, appMetrics, logger)
+	if err != nil {
+		return err
 	}
+	defer func() {
+		// The startup context may be long gone, close with a fresh deadline
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := store.close(ctx); err != nil {
+			logger.Error("failed to close storage", "backend", cfg.Storage.Backend, "error", err)
+		}
+	}()
 
-	newsRepo := mongodb.NewNewsRepository(client, database)
-	newsService := service.NewNewsService(newsRepo)
+	newsRepo := store.news
+	newsRepo = instrument.WithMetrics(newsRepo, appMetrics)
+	newsRepo = instrument.WithLogging(newsRepo, logger)
+	newsRepo = instrument.WithTracing(newsRepo)
+	broadcaster := broadcast.New(broadcast.DefaultBuffer)
+	newsService := service.NewNewsService(newsRepo, store.outbox, store.transactor, broadcaster)
 	newsHandler := handler.NewNewsHandler(newsService)
+	streamHandler := handler.NewStreamHandler(broadcaster)
+	transferHandler := handler.NewTransferHandler(newsService, newsRepo, cfg.Admin.Token)
+
+	webhookHandler := handler.NewWebhookHandler(webhook.NewService(store.webhooks), cfg.Admin.Token)
+	webhookSender := webhook.NewSender(store.webhooks, cfg.Webhooks.Options(), logger)
+
+	workers := worker.NewGroup()
+
+	// Subscribers register on the dispatcher before it starts
+	dispatcher := events.NewDispatcher(store.outbox, cfg.Events.Options(), logger)
+	webhookSender.Subscribe(dispatcher)
+	if cfg.Features.Events {
+		workers.Go("events", dispatcher.Run)
+		workers.Go("webhooks", webhookSender.Run)
+	}
+
+	serving := health.NewFlag("server not started")
+	healthRegistry := health.NewRegistry(2 * time.Second)
+	for name, check := range store.checks {
+		healthRegistry.Register(name, check)
+	}
+	healthRegistry.Register("workers", workers.Check)
+	healthRegistry.Register("server", serving.Check)
+	healthHandler := handler.NewHealthHandler(healthRegistry)
+
+	spec, err := api.OpenAPI()
+	if err != nil {
+		return err
+	}
 
-	router := gin.Default()
+	rateLimitConfig, err := cfg.RateLimit.Middleware(cfg.Features.RateLimit)
+	if err != nil {
+		return fmt.Errorf("invalid rate limit configuration: %w", err)
+	}
+	if cfg.Admin.Token != "" {
+		rateLimitConfig.Tokens = append(rateLimitConfig.Tokens, cfg.Admin.Token)
+	}
 
-	funcMap := template.FuncMap{
-		"subtract": func(a, b int) int { return a - b },
-		"add":      func(a, b int) int { return a + b },
-		"multiply": func(a, b int) int { return a * b },
+	gin.SetMode(gin.ReleaseMode)
+	if cfg.Development() {
+		gin.SetMode(gin.DebugMode)
+	}
+	router := gin.New()
+	// Without trusted proxies X-Forwarded-For is ignored, so clients cannot
+	// pick their own IP for rate limiting
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.Use(
+		middleware.RequestID(),
+		middleware.Tracing(),
+		middleware.Logger(logger),
+		middleware.Metrics(appMetrics),
+		gin.Recovery(),
+	)
+	securityConfig := cfg.Security.Middleware(cfg.Development())
+	securityConfig.CSPReportURI = handler.CSPReportPath
+	router.Use(middleware.SecurityHeaders(securityConfig))
+	router.Use(middleware.RateLimit(rateLimitConfig, middleware.NewMemoryStore()))
+	if cfg.Features.CSRF {
+		router.Use(middleware.CSRF())
 	}
-	router.SetFuncMap(funcMap)
-	router.LoadHTMLGlob("web/templates/**/*")
 
-	router.Static("/static", "./web/static")
+	if err := setupWeb(router, cfg.Paths); err != nil {
+		return err
+	}
 
+	if cfg.Features.Metrics {
+		router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+	}
+	healthHandler.RegisterRoutes(router)
 	newsHandler.RegisterRoutes(router)
+	streamHandler.RegisterRoutes(router)
+	transferHandler.RegisterRoutes(router)
+	webhookHandler.RegisterRoutes(router)
+	handler.NewDocsHandler(spec).RegisterRoutes(router)
+	handler.NewCSPReportHandler(logger).RegisterRoutes(router)
+	if cfg.Features.GraphQL {
+		executor, err := graphql.New(newsService, cfg.GraphQL.Limits())
+		if err != nil {
+			return fmt.Errorf("build GraphQL schema: %w", err)
+		}
+		handler.NewGraphQLHandler(executor, cfg.Development()).RegisterRoutes(router)
+	}
+
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           i18n.Handler(router),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	server.RegisterOnShutdown(streamHandler.Close)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	var rpcServer *grpcServer
+	if cfg.Features.GRPC {
+		rpcServer, err = listenGRPC(cfg.GRPC.Port, cfg.Admin.Token, newsService, broadcaster, logger)
+		if err != nil {
+			return err
+		}
 	}
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal(err)
+	serverErr := make(chan error, 2)
+	go func() {
+		logger.Info("starting server", "addr", server.Addr, "environment", cfg.Environment)
+		serverErr <- server.ListenAndServe()
+	}()
+	if rpcServer != nil {
+		go func() {
+			logger.Info("starting gRPC server", "addr", rpcServer.listener.Addr().String())
+			if err := rpcServer.serve(); err != nil {
+				serverErr <- fmt.Errorf("gRPC: %w", err)
+			}
+		}()
 	}
+	serving.Set()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server stopped: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down", "timeout", shutdownTimeout)
+	serving.Clear("shutting down")
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelShutdown()
+
+	if rpcServer != nil {
+		rpcServer.stop(shutdownCtx)
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to drain connections", "error", err)
+	}
+	if err := workers.Stop(shutdownCtx); err != nil {
+		logger.Error("failed to stop background workers", "error", err)
+	}
+
+	logger.Info("server stopped")
+	return nil
 }
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/config"
+	"news_service/internal/domain"
+	"news_service/internal/events"
+	"news_service/internal/health"
+	"news_service/internal/metrics"
+	"news_service/internal/migrate"
+	"news_service/internal/repository/mongodb"
+	"news_service/internal/repository/postgres"
+	"news_service/internal/repository/sqlite"
+	"news_service/internal/tracing"
+	"news_service/internal/webhook"
+)
+
+// outbox is written by the service and read by the dispatcher
+type outbox interface {
+	domain.Outbox
+	events.Store
+}
+
+// storage is everything the service keeps in the configured backend
+type storage struct {
+	news       domain.NewsRepository
+	outbox     outbox
+	transactor domain.Transactor
+	webhooks   webhook.Store
+	// checks are registered with the health registry by name
+	checks map[string]health.CheckFunc
+	close  func(ctx context.Context) error
+}
+
+// openStorage connects to the backend selected by storage.backend and
+// prepares its schema
+func openStorage(ctx context.Context, cfg *config.Config, appMetrics *metrics.Metrics, logger *slog.Logger) (*storage, error) {
+	switch cfg.Storage.Backend {
+	case "postgres":
+		return openPostgres(ctx, cfg.Postgres, logger)
+	case "sqlite":
+		return openSQLite(ctx, cfg.SQLite, logger)
+	default:
+		return openMongo(ctx, cfg.Mongo, appMetrics, logger)
+	}
+}
+
+func openMongo(ctx context.Context, cfg config.MongoConfig, appMetrics *metrics.Metrics, logger *slog.Logger) (_ *storage, err error) {
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	clientOpts := options.Client().
+		ApplyURI(cfg.URI).
+		SetMaxPoolSize(cfg.MaxPoolSize).
+		SetMinPoolSize(cfg.MinPoolSize).
+		SetMaxConnIdleTime(cfg.MaxConnIdleTime).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetServerSelectionTimeout(cfg.ServerSelectionTimeout).
+		SetPoolMonitor(appMetrics.PoolMonitor()).
+		SetMonitor(tracing.CommandMonitor())
+	client, err := mongo.Connect(connectCtx, clientOpts)
+	if err != nil {
+		return nil, fmt.Errorf("connect to MongoDB: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			client.Disconnect(context.Background())
+		}
+	}()
+
+	if err := client.Ping(connectCtx, nil); err != nil {
+		return nil, fmt.Errorf("ping MongoDB: %w", err)
+	}
+
+	if cfg.Migrate {
+		migrator, err := migrate.New(client.Database(cfg.Database), mongodb.Migrations(), logger)
+		if err != nil {
+			return nil, err
+		}
+		// Migrations may take longer than connecting, only stop on shutdown
+		applied, err := migrator.Up(ctx, migrate.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+		logger.Info("migrations applied", "count", len(applied))
+	}
+
+	if cfg.EnsureIndexes {
+		reports, err := mongodb.EnsureIndexes(connectCtx, client, cfg.Database)
+		if err != nil {
+			return nil, fmt.Errorf("ensure indexes: %w", err)
+		}
+		for _, report := range reports {
+			logger.Info("indexes ensured",
+				"collection", report.Collection,
+				"created", report.Created,
+				"unexpected", report.Unexpected,
+			)
+		}
+	}
+
+	transactor, transactions, err := mongodb.NewTransactor(connectCtx, client)
+	if err != nil {
+		return nil, err
+	}
+	if !transactions {
+		logger.Warn("MongoDB does not support transactions, events are not written atomically with articles; run a replica set")
+	}
+
+	return &storage{
+		news:       mongodb.NewNewsRepository(client, cfg.Database),
+		outbox:     mongodb.NewOutbox(client, cfg.Database),
+		transactor: transactor,
+		webhooks:   mongodb.NewWebhookStore(client, cfg.Database),
+		checks: map[string]health.CheckFunc{
+			"mongodb": health.Ping(client),
+			"indexes": func(ctx context.Context) error {
+				reports, err := mongodb.CheckIndexes(ctx, client, cfg.Database)
+				if err != nil {
+					return err
+				}
+				for _, report := range reports {
+					if len(report.Missing) > 0 {
+						return fmt.Errorf("missing indexes on %s: %v", report.Collection, report.Missing)
+					}
+				}
+				return nil
+			},
+		},
+		close: client.Disconnect,
+	}, nil
+}
+
+func openPostgres(ctx context.Context, cfg config.PostgresConfig, logger *slog.Logger) (_ *storage, err error) {
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	pool, err := postgres.Connect(connectCtx, cfg.URL, cfg.Options())
+	if err != nil {
+		return nil, fmt.Errorf("connect to PostgreSQL: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			pool.Close()
+		}
+	}()
+
+	if cfg.Migrate {
+		// Migrations may take longer than connecting, only stop on shutdown
+		applied, err := postgres.Migrate(ctx, pool)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+		logger.Info("migrations applied", "count", len(applied))
+	}
+
+	return &storage{
+		news:       postgres.NewNewsRepository(pool),
+		outbox:     postgres.NewOutbox(pool),
+		transactor: postgres.NewTransactor(pool),
+		webhooks:   postgres.NewWebhookStore(pool),
+		checks: map[string]health.CheckFunc{
+			"postgres": pool.Ping,
+		},
+		close: func(context.Context) error {
+			pool.Close()
+			return nil
+		},
+	}, nil
+}
+
+func openSQLite(ctx context.Context, cfg config.SQLiteConfig, logger *slog.Logger) (_ *storage, err error) {
+	db, err := sqlite.Open(ctx, cfg.Path, cfg.BusyTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("open SQLite database %s: %w", cfg.Path, err)
+	}
+	defer func() {
+		if err != nil {
+			db.Close()
+		}
+	}()
+
+	if cfg.Migrate {
+		applied, err := sqlite.Migrate(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+		logger.Info("migrations applied", "count", len(applied))
+	}
+
+	return &storage{
+		news:       sqlite.NewNewsRepository(db),
+		outbox:     sqlite.NewOutbox(db),
+		transactor: sqlite.NewTransactor(db),
+		webhooks:   sqlite.NewWebhookStore(db),
+		checks: map[string]health.CheckFunc{
+			"sqlite": db.PingContext,
+		},
+		close: func(context.Context) error {
+			return db.Close()
+		},
+	}, nil
+}
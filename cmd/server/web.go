@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"news_service/internal/assets"
+	"news_service/internal/config"
+	"news_service/internal/middleware"
+	"news_service/web"
+)
+
+// setupWeb loads the templates and serves the static assets, embedded unless
+// paths points at directories to use instead. It fails when a template
+// refers to a static file that is not there.
+func setupWeb(router *gin.Engine, paths config.PathsConfig) error {
+	templatesFS, staticFS := web.Templates(), web.Static()
+	if paths.Templates != "" {
+		templatesFS = os.DirFS(paths.Templates)
+	}
+	if paths.Static != "" {
+		staticFS = os.DirFS(paths.Static)
+	}
+	if err := web.CheckAssets(templatesFS, staticFS); err != nil {
+		return err
+	}
+
+	static, err := staticAssets(paths.Static)
+	if err != nil {
+		return err
+	}
+
+	funcMap := template.FuncMap{
+		"subtract": func(a, b int) int { return a - b },
+		"add":      func(a, b int) int { return a + b },
+		"multiply": func(a, b int) int { return a * b },
+		"asset":    static.URL,
+		"cspNonce": middleware.NoncePlaceholder,
+	}
+	router.SetFuncMap(funcMap)
+
+	if paths.Templates == "" {
+		templates, err := template.New("").Funcs(funcMap).ParseFS(web.Templates(), web.TemplatePatterns...)
+		if err != nil {
+			return fmt.Errorf("parse templates: %w", err)
+		}
+		router.SetHTMLTemplate(templates)
+	} else {
+		var files []string
+		for _, pattern := range web.TemplatePatterns {
+			matches, err := filepath.Glob(filepath.Join(paths.Templates, pattern))
+			if err != nil {
+				return fmt.Errorf("find templates: %w", err)
+			}
+			files = append(files, matches...)
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no templates in %s", paths.Templates)
+		}
+		// Reloaded on every render in development mode
+		router.LoadHTMLFiles(files...)
+	}
+
+	serve := gin.WrapH(http.StripPrefix("/static", static))
+	router.GET("/static/*filepath", serve)
+	router.HEAD("/static/*filepath", serve)
+	return nil
+}
+
+func staticAssets(dir string) (*assets.Assets, error) {
+	if dir != "" {
+		return assets.Live(os.DirFS(dir), "/static"), nil
+	}
+	return assets.New(web.Static(), "/static")
+}
@@ -0,0 +1,111 @@
+// Package assets serves static files under content-hashed URLs, so they can
+// be cached for good and still change with every release
+package assets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const (
+	hashLength = 10
+
+	cacheImmutable  = "public, max-age=31536000, immutable"
+	cacheRevalidate = "no-cache"
+)
+
+// Assets maps the static files in a directory to their URLs
+type Assets struct {
+	prefix string
+	fsys   fs.FS
+	// hashes maps file names to content hashes and versions maps hashed
+	// names back to file names, both are empty when serving live files
+	hashes   map[string]string
+	versions map[string]string
+}
+
+// New hashes every file in fsys, to be served under prefix
+func New(fsys fs.FS, prefix string) (*Assets, error) {
+	a := Live(fsys, prefix)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])[:hashLength]
+		a.hashes[name] = hash
+		a.versions[withHash(name, hash)] = name
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("hash static assets: %w", err)
+	}
+	return a, nil
+}
+
+// Live serves the files in fsys under prefix without hashing them, for
+// development with a directory whose files change while the server runs
+func Live(fsys fs.FS, prefix string) *Assets {
+	return &Assets{
+		prefix:   strings.TrimSuffix(prefix, "/"),
+		fsys:     fsys,
+		hashes:   make(map[string]string),
+		versions: make(map[string]string),
+	}
+}
+
+// withHash inserts hash before the extension of name: css/main.css becomes
+// css/main.<hash>.css
+func withHash(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// URL returns the URL of the named file, hashed when the file is known
+func (a *Assets) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if hash, ok := a.hashes[name]; ok {
+		name = withHash(name, hash)
+	}
+	return a.prefix + "/" + name
+}
+
+// ServeHTTP serves hashed URLs with a long-lived cache and anything else
+// with revalidation. The request path must have the prefix stripped.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	cache := cacheRevalidate
+	if original, ok := a.versions[name]; ok {
+		name = original
+		cache = cacheImmutable
+	}
+
+	f, err := a.fsys.Open(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	content, seekable := f.(io.ReadSeeker)
+	if err != nil || info.IsDir() || !seekable {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", cache)
+	if hash, ok := a.hashes[name]; ok {
+		w.Header().Set("ETag", `"`+hash+`"`)
+	}
+	http.ServeContent(w, r, name, info.ModTime(), content)
+}
@@ -0,0 +1,80 @@
+package assets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var files = fstest.MapFS{
+	"css/main.css":     {Data: []byte("body { color: red; }")},
+	"vendor/htmx.js":   {Data: []byte("htmx")},
+	"vendor/empty.txt": {Data: nil},
+}
+
+func serve(t *testing.T, a *Assets, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", path, nil)
+	for name := range header {
+		req.Header.Set(name, header.Get(name))
+	}
+	a.ServeHTTP(w, req)
+	return w
+}
+
+func TestAssets_URL(t *testing.T) {
+	a, err := New(files, "/static/")
+	require.NoError(t, err)
+
+	url := a.URL("css/main.css")
+	assert.Regexp(t, `^/static/css/main\.[0-9a-f]{10}\.css$`, url)
+	assert.Equal(t, url, a.URL("/css/main.css"))
+	assert.NotEqual(t, a.URL("vendor/htmx.js"), "/static/vendor/htmx.js")
+	assert.Equal(t, "/static/missing.js", a.URL("missing.js"))
+
+	changed := fstest.MapFS{"css/main.css": {Data: []byte("body { color: blue; }")}}
+	b, err := New(changed, "/static")
+	require.NoError(t, err)
+	assert.NotEqual(t, url, b.URL("css/main.css"), "the URL changes with the content")
+}
+
+func TestAssets_ServeHTTP(t *testing.T) {
+	a, err := New(files, "/static")
+	require.NoError(t, err)
+	hashed := a.URL("css/main.css")[len("/static"):]
+
+	w := serve(t, a, hashed, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "body { color: red; }", w.Body.String())
+	assert.Equal(t, cacheImmutable, w.Header().Get("Cache-Control"))
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/css")
+
+	w = serve(t, a, "/css/main.css", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, cacheRevalidate, w.Header().Get("Cache-Control"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	w = serve(t, a, "/css/main.css", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	for _, path := range []string{"/missing.css", "/css", "/", "/css/main.0123456789.css"} {
+		assert.Equal(t, http.StatusNotFound, serve(t, a, path, nil).Code, path)
+	}
+}
+
+func TestAssets_Live(t *testing.T) {
+	live := fstest.MapFS{"app.js": {Data: []byte("v1")}}
+	a := Live(live, "/static")
+	assert.Equal(t, "/static/app.js", a.URL("app.js"))
+
+	live["app.js"] = &fstest.MapFile{Data: []byte("v2")}
+	w := serve(t, a, "/app.js", nil)
+	assert.Equal(t, "v2", w.Body.String())
+	assert.Equal(t, cacheRevalidate, w.Header().Get("Cache-Control"))
+}
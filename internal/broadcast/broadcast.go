@@ -0,0 +1,85 @@
+// Package broadcast fans article events out to in-process listeners, such as
+// the live update streams of connected browsers
+package broadcast
+
+import (
+	"sync"
+
+	"news_service/internal/domain"
+)
+
+// DefaultBuffer is how many events a subscriber may fall behind
+const DefaultBuffer = 32
+
+// Broadcaster hands every published event to all current subscribers. A
+// subscriber that falls more than its buffer behind is dropped, its channel
+// closed, so one slow client cannot hold up the others.
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan domain.Event]struct{}
+	buffer      int
+}
+
+var _ domain.Publisher = (*Broadcaster)(nil)
+
+// New creates a broadcaster giving each subscriber buffer pending events
+func New(buffer int) *Broadcaster {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Broadcaster{
+		subscribers: make(map[chan domain.Event]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Subscribe returns a channel receiving events published from now on and a
+// function ending the subscription. The channel is closed when the
+// subscription ends or the subscriber is dropped.
+func (b *Broadcaster) Subscribe() (<-chan domain.Event, func()) {
+	ch := make(chan domain.Event, b.buffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(ch)
+	}
+}
+
+// Publish sends events to every subscriber without blocking
+func (b *Broadcaster) Publish(events ...domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+				b.remove(ch)
+			}
+			if _, ok := b.subscribers[ch]; !ok {
+				break
+			}
+		}
+	}
+}
+
+// Subscribers returns the number of current subscribers
+func (b *Broadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// remove closes and forgets ch; b.mu must be held
+func (b *Broadcaster) remove(ch chan domain.Event) {
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
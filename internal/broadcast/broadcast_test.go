@@ -0,0 +1,48 @@
+package broadcast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+func TestBroadcaster_Publish(t *testing.T) {
+	b := New(4)
+	first, cancelFirst := b.Subscribe()
+	second, cancelSecond := b.Subscribe()
+	defer cancelSecond()
+
+	event := domain.NewEvent(domain.EventArticleCreated, "a1", nil)
+	b.Publish(event)
+
+	assert.Equal(t, event, <-first)
+	assert.Equal(t, event, <-second)
+
+	cancelFirst()
+	_, open := <-first
+	assert.False(t, open)
+	assert.Equal(t, 1, b.Subscribers())
+
+	// Cancelling twice is harmless
+	cancelFirst()
+}
+
+func TestBroadcaster_DropsSlowSubscribers(t *testing.T) {
+	b := New(2)
+	slow, cancel := b.Subscribe()
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		b.Publish(domain.NewEvent(domain.EventArticleUpdated, "a1", nil))
+	}
+	assert.Equal(t, 0, b.Subscribers())
+
+	var received int
+	for range slow {
+		received++
+	}
+	require.Equal(t, 2, received)
+}
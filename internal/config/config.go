@@ -0,0 +1,728 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"news_service/internal/events"
+	"news_service/internal/graphql"
+	"news_service/internal/middleware"
+	"news_service/internal/repository/postgres"
+	"news_service/internal/webhook"
+)
+
+// Config is the complete service configuration. Values are resolved in order
+// of increasing precedence: defaults, config file, environment, command line.
+type Config struct {
+	// Environment is "development" or "production"
+	Environment string          `yaml:"environment"`
+	Server      ServerConfig    `yaml:"server"`
+	Storage     StorageConfig   `yaml:"storage"`
+	Mongo       MongoConfig     `yaml:"mongodb"`
+	Postgres    PostgresConfig  `yaml:"postgres"`
+	SQLite      SQLiteConfig    `yaml:"sqlite"`
+	Paths       PathsConfig     `yaml:"paths"`
+	Logging     LoggingConfig   `yaml:"logging"`
+	Tracing     TracingConfig   `yaml:"tracing"`
+	RateLimit   RateLimitConfig `yaml:"rate_limit"`
+	Security    SecurityConfig  `yaml:"security"`
+	Events      EventsConfig    `yaml:"events"`
+	Webhooks    WebhooksConfig  `yaml:"webhooks"`
+	Admin       AdminConfig     `yaml:"admin"`
+	GraphQL     GraphQLConfig   `yaml:"graphql"`
+	GRPC        GRPCConfig      `yaml:"grpc"`
+	Features    FeaturesConfig  `yaml:"features"`
+}
+
+type ServerConfig struct {
+	Port              int           `yaml:"port"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"`
+	// TrustedProxies lists the addresses or CIDR ranges whose
+	// X-Forwarded-For header is believed, none by default
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// StorageConfig selects the database articles, events and webhooks are kept in
+type StorageConfig struct {
+	// Backend is "mongodb", "postgres" or "sqlite"
+	Backend string `yaml:"backend"`
+}
+
+type MongoConfig struct {
+	URI                    string        `yaml:"uri"`
+	Database               string        `yaml:"database"`
+	MaxPoolSize            uint64        `yaml:"max_pool_size"`
+	MinPoolSize            uint64        `yaml:"min_pool_size"`
+	MaxConnIdleTime        time.Duration `yaml:"max_conn_idle_time"`
+	ConnectTimeout         time.Duration `yaml:"connect_timeout"`
+	ServerSelectionTimeout time.Duration `yaml:"server_selection_timeout"`
+	// EnsureIndexes creates missing indexes on startup
+	EnsureIndexes bool `yaml:"ensure_indexes"`
+	// Migrate applies pending data migrations on startup
+	Migrate bool `yaml:"migrate"`
+}
+
+type PostgresConfig struct {
+	URL             string        `yaml:"url"`
+	MaxConns        int           `yaml:"max_conns"`
+	MinConns        int           `yaml:"min_conns"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout"`
+	// Migrate applies pending schema migrations on startup
+	Migrate bool `yaml:"migrate"`
+}
+
+type SQLiteConfig struct {
+	// Path is the database file, created when missing
+	Path string `yaml:"path"`
+	// BusyTimeout is how long a write waits for the lock held by another
+	BusyTimeout time.Duration `yaml:"busy_timeout"`
+	// Migrate applies pending schema migrations on startup
+	Migrate bool `yaml:"migrate"`
+}
+
+// PathsConfig points at directories overriding the embedded templates and
+// static assets, for development. Changes there show without a restart,
+// for templates only in development mode.
+type PathsConfig struct {
+	Templates string `yaml:"templates"`
+	Static    string `yaml:"static"`
+}
+
+type LoggingConfig struct {
+	Format string `yaml:"format"`
+	Level  string `yaml:"level"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`
+	File        string  `yaml:"file"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// RateLimitConfig holds limits in the "<count>/<unit>" form understood by
+// middleware.ParseLimit. The token limits apply to clients presenting one of
+// Tokens or the admin token.
+type RateLimitConfig struct {
+	Tokens      []string `yaml:"tokens"`
+	Read        string   `yaml:"read"`
+	Write       string   `yaml:"write"`
+	Search      string   `yaml:"search"`
+	TokenRead   string   `yaml:"token_read"`
+	TokenWrite  string   `yaml:"token_write"`
+	TokenSearch string   `yaml:"token_search"`
+}
+
+// SecurityConfig sets the security headers of every response, see
+// middleware.SecurityConfig
+type SecurityConfig struct {
+	CSP           string `yaml:"csp"`
+	CSPReportOnly bool   `yaml:"csp_report_only"`
+	// FrameAncestors lists who may embed the pages in frames
+	FrameAncestors string `yaml:"frame_ancestors"`
+	// HSTSMaxAge is not sent in development, so local plain HTTP keeps working
+	HSTSMaxAge        time.Duration `yaml:"hsts_max_age"`
+	ReferrerPolicy    string        `yaml:"referrer_policy"`
+	PermissionsPolicy string        `yaml:"permissions_policy"`
+}
+
+// EventsConfig tunes the delivery of domain events from the outbox
+type EventsConfig struct {
+	PollInterval   time.Duration `yaml:"poll_interval"`
+	BatchSize      int           `yaml:"batch_size"`
+	HandlerTimeout time.Duration `yaml:"handler_timeout"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	// MaxAttempts is how often an event is tried before it is given up on
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+// WebhooksConfig tunes the delivery of outgoing webhooks
+type WebhooksConfig struct {
+	Timeout      time.Duration `yaml:"timeout"`
+	MaxAttempts  int           `yaml:"max_attempts"`
+	MinBackoff   time.Duration `yaml:"min_backoff"`
+	MaxBackoff   time.Duration `yaml:"max_backoff"`
+	PollInterval time.Duration `yaml:"poll_interval"`
+	// AllowPrivate permits endpoints on loopback and private networks
+	AllowPrivate bool `yaml:"allow_private"`
+}
+
+type AdminConfig struct {
+	// Token is the bearer token of the admin API, disabled when empty
+	Token string `yaml:"token"`
+}
+
+// GraphQLConfig bounds the queries accepted by /graphql, see graphql.Limits
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"max_depth"`
+	MaxComplexity int `yaml:"max_complexity"`
+}
+
+type GRPCConfig struct {
+	Port int `yaml:"port"`
+}
+
+type FeaturesConfig struct {
+	RateLimit bool `yaml:"rate_limit"`
+	Metrics   bool `yaml:"metrics"`
+	// Events runs the outbox dispatcher. Events are recorded either way.
+	Events bool `yaml:"events"`
+	// GraphQL serves /graphql, with a playground in development
+	GraphQL bool `yaml:"graphql"`
+	// GRPC serves the gRPC API on its own port
+	GRPC bool `yaml:"grpc"`
+	// CSRF checks a per-session token on form and htmx writes
+	CSRF bool `yaml:"csrf"`
+}
+
+// Default returns the configuration used when nothing else is set
+func Default() *Config {
+	security := middleware.DefaultSecurityConfig()
+	return &Config{
+		Environment: "production",
+		Server: ServerConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       120 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Storage: StorageConfig{
+			Backend: "mongodb",
+		},
+		Mongo: MongoConfig{
+			URI:                    "mongodb://localhost:27017",
+			Database:               "news_service",
+			MaxPoolSize:            100,
+			MaxConnIdleTime:        5 * time.Minute,
+			ConnectTimeout:         10 * time.Second,
+			ServerSelectionTimeout: 10 * time.Second,
+			EnsureIndexes:          true,
+			Migrate:                true,
+		},
+		Postgres: PostgresConfig{
+			URL:             "postgres://localhost:5432/news_service",
+			MaxConns:        20,
+			MaxConnIdleTime: 5 * time.Minute,
+			ConnectTimeout:  10 * time.Second,
+			Migrate:         true,
+		},
+		SQLite: SQLiteConfig{
+			Path:        "data/news.db",
+			BusyTimeout: 5 * time.Second,
+			Migrate:     true,
+		},
+		Logging: LoggingConfig{
+			Format: "json",
+			Level:  "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			File:        "traces.json",
+			ServiceName: "news_service",
+			SampleRatio: 1,
+		},
+		RateLimit: RateLimitConfig{
+			Read:        "600/m",
+			Write:       "60/m",
+			Search:      "60/m",
+			TokenRead:   "3000/m",
+			TokenWrite:  "600/m",
+			TokenSearch: "300/m",
+		},
+		Security: SecurityConfig{
+			CSP:               security.CSP,
+			FrameAncestors:    security.FrameAncestors,
+			HSTSMaxAge:        security.HSTSMaxAge,
+			ReferrerPolicy:    security.ReferrerPolicy,
+			PermissionsPolicy: security.PermissionsPolicy,
+		},
+		Events: EventsConfig{
+			PollInterval:   time.Second,
+			BatchSize:      100,
+			HandlerTimeout: 10 * time.Second,
+			MaxBackoff:     5 * time.Minute,
+			MaxAttempts:    20,
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:      10 * time.Second,
+			MaxAttempts:  10,
+			MinBackoff:   10 * time.Second,
+			MaxBackoff:   time.Hour,
+			PollInterval: time.Second,
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:      8,
+			MaxComplexity: 1000,
+		},
+		GRPC: GRPCConfig{
+			Port: 50051,
+		},
+		Features: FeaturesConfig{
+			RateLimit: true,
+			Metrics:   true,
+			Events:    true,
+			GraphQL:   true,
+			GRPC:      true,
+			CSRF:      true,
+		},
+	}
+}
+
+// Load resolves the configuration from defaults, the file given by -config or
+// CONFIG_FILE, environment variables and the command line arguments, then
+// validates it
+func Load(args []string) (*Config, error) {
+	cfg, rest, err := LoadCommand("server", args)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+	return cfg, nil
+}
+
+// LoadCommand is like Load for commands that take arguments after the flags.
+// It returns the arguments that follow the configuration flags.
+func LoadCommand(name string, args []string) (*Config, []string, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	overrides := cfg.flags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(*configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(os.LookupEnv); err != nil {
+		return nil, nil, err
+	}
+
+	// Only flags given explicitly override the file and environment
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		if apply, ok := overrides[f.Name]; ok && flagErr == nil {
+			flagErr = apply(f.Value.String())
+		}
+	})
+	if flagErr != nil {
+		return nil, nil, flagErr
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// envVars maps environment variable names to configuration fields
+func (c *Config) envVars() map[string]any {
+	return map[string]any{
+		"APP_ENV": &c.Environment,
+
+		"PORT":                       &c.Server.Port,
+		"SERVER_READ_HEADER_TIMEOUT": &c.Server.ReadHeaderTimeout,
+		"SERVER_READ_TIMEOUT":        &c.Server.ReadTimeout,
+		"SERVER_WRITE_TIMEOUT":       &c.Server.WriteTimeout,
+		"SERVER_IDLE_TIMEOUT":        &c.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":           &c.Server.ShutdownTimeout,
+		"SERVER_TRUSTED_PROXIES":     &c.Server.TrustedProxies,
+
+		"STORAGE_BACKEND": &c.Storage.Backend,
+
+		"MONGODB_URI":                      &c.Mongo.URI,
+		"MONGODB_DATABASE":                 &c.Mongo.Database,
+		"MONGODB_MAX_POOL_SIZE":            &c.Mongo.MaxPoolSize,
+		"MONGODB_MIN_POOL_SIZE":            &c.Mongo.MinPoolSize,
+		"MONGODB_MAX_CONN_IDLE_TIME":       &c.Mongo.MaxConnIdleTime,
+		"MONGODB_CONNECT_TIMEOUT":          &c.Mongo.ConnectTimeout,
+		"MONGODB_SERVER_SELECTION_TIMEOUT": &c.Mongo.ServerSelectionTimeout,
+		"MONGODB_ENSURE_INDEXES":           &c.Mongo.EnsureIndexes,
+		"MONGODB_MIGRATE":                  &c.Mongo.Migrate,
+
+		"POSTGRES_URL":                &c.Postgres.URL,
+		"POSTGRES_MAX_CONNS":          &c.Postgres.MaxConns,
+		"POSTGRES_MIN_CONNS":          &c.Postgres.MinConns,
+		"POSTGRES_MAX_CONN_IDLE_TIME": &c.Postgres.MaxConnIdleTime,
+		"POSTGRES_CONNECT_TIMEOUT":    &c.Postgres.ConnectTimeout,
+		"POSTGRES_MIGRATE":            &c.Postgres.Migrate,
+
+		"SQLITE_PATH":         &c.SQLite.Path,
+		"SQLITE_BUSY_TIMEOUT": &c.SQLite.BusyTimeout,
+		"SQLITE_MIGRATE":      &c.SQLite.Migrate,
+
+		"TEMPLATES_DIR": &c.Paths.Templates,
+		"STATIC_DIR":    &c.Paths.Static,
+
+		"LOG_FORMAT": &c.Logging.Format,
+		"LOG_LEVEL":  &c.Logging.Level,
+
+		"TRACING_EXPORTER":     &c.Tracing.Exporter,
+		"TRACING_FILE":         &c.Tracing.File,
+		"TRACING_SAMPLE_RATIO": &c.Tracing.SampleRatio,
+		"OTEL_SERVICE_NAME":    &c.Tracing.ServiceName,
+
+		"RATE_LIMIT_READ":         &c.RateLimit.Read,
+		"RATE_LIMIT_WRITE":        &c.RateLimit.Write,
+		"RATE_LIMIT_SEARCH":       &c.RateLimit.Search,
+		"RATE_LIMIT_TOKEN_READ":   &c.RateLimit.TokenRead,
+		"RATE_LIMIT_TOKEN_WRITE":  &c.RateLimit.TokenWrite,
+		"RATE_LIMIT_TOKEN_SEARCH": &c.RateLimit.TokenSearch,
+		"RATE_LIMIT_TOKENS":       &c.RateLimit.Tokens,
+
+		"CSP":                &c.Security.CSP,
+		"CSP_REPORT_ONLY":    &c.Security.CSPReportOnly,
+		"FRAME_ANCESTORS":    &c.Security.FrameAncestors,
+		"HSTS_MAX_AGE":       &c.Security.HSTSMaxAge,
+		"REFERRER_POLICY":    &c.Security.ReferrerPolicy,
+		"PERMISSIONS_POLICY": &c.Security.PermissionsPolicy,
+
+		"EVENTS_POLL_INTERVAL":   &c.Events.PollInterval,
+		"EVENTS_BATCH_SIZE":      &c.Events.BatchSize,
+		"EVENTS_HANDLER_TIMEOUT": &c.Events.HandlerTimeout,
+		"EVENTS_MAX_BACKOFF":     &c.Events.MaxBackoff,
+		"EVENTS_MAX_ATTEMPTS":    &c.Events.MaxAttempts,
+
+		"WEBHOOKS_TIMEOUT":       &c.Webhooks.Timeout,
+		"WEBHOOKS_MAX_ATTEMPTS":  &c.Webhooks.MaxAttempts,
+		"WEBHOOKS_MIN_BACKOFF":   &c.Webhooks.MinBackoff,
+		"WEBHOOKS_MAX_BACKOFF":   &c.Webhooks.MaxBackoff,
+		"WEBHOOKS_POLL_INTERVAL": &c.Webhooks.PollInterval,
+		"WEBHOOKS_ALLOW_PRIVATE": &c.Webhooks.AllowPrivate,
+
+		"ADMIN_TOKEN": &c.Admin.Token,
+
+		"GRAPHQL_MAX_DEPTH":      &c.GraphQL.MaxDepth,
+		"GRAPHQL_MAX_COMPLEXITY": &c.GraphQL.MaxComplexity,
+
+		"GRPC_PORT": &c.GRPC.Port,
+
+		"RATE_LIMIT_ENABLED": &c.Features.RateLimit,
+		"METRICS_ENABLED":    &c.Features.Metrics,
+		"EVENTS_ENABLED":     &c.Features.Events,
+		"GRAPHQL_ENABLED":    &c.Features.GraphQL,
+		"GRPC_ENABLED":       &c.Features.GRPC,
+		"CSRF_ENABLED":       &c.Features.CSRF,
+	}
+}
+
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	for name, target := range c.envVars() {
+		value, ok := lookup(name)
+		if !ok || value == "" {
+			continue
+		}
+		if err := set(target, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// flags registers the command line flags and returns how to apply each one
+func (c *Config) flags(fs *flag.FlagSet) map[string]func(string) error {
+	targets := map[string]struct {
+		target any
+		usage  string
+	}{
+		"env":              {&c.Environment, "environment: development or production"},
+		"port":             {&c.Server.Port, "HTTP port"},
+		"grpc-port":        {&c.GRPC.Port, "gRPC port"},
+		"storage":          {&c.Storage.Backend, "storage backend: mongodb, postgres or sqlite"},
+		"mongodb-uri":      {&c.Mongo.URI, "MongoDB connection URI"},
+		"mongodb-database": {&c.Mongo.Database, "MongoDB database name"},
+		"postgres-url":     {&c.Postgres.URL, "PostgreSQL connection URL"},
+		"sqlite-path":      {&c.SQLite.Path, "SQLite database file"},
+		"templates":        {&c.Paths.Templates, "templates directory overriding the embedded ones"},
+		"static":           {&c.Paths.Static, "static assets directory overriding the embedded ones"},
+		"log-format":       {&c.Logging.Format, "log format: json or text"},
+		"log-level":        {&c.Logging.Level, "log level: debug, info, warn or error"},
+	}
+
+	apply := make(map[string]func(string) error, len(targets))
+	for name, t := range targets {
+		name, target := name, t.target
+		fs.String(name, "", t.usage)
+		apply[name] = func(value string) error {
+			if err := set(target, value); err != nil {
+				return fmt.Errorf("-%s: %w", name, err)
+			}
+			return nil
+		}
+	}
+	return apply
+}
+
+// set parses value into the field pointed to by target
+func set(target any, value string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = value
+	case *[]string:
+		// Comma separated
+		*t = nil
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*t = append(*t, item)
+			}
+		}
+	case *int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		*t = v
+	case *uint64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		*t = v
+	case *float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*t = v
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*t = v
+	case *time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		*t = v
+	default:
+		return fmt.Errorf("unsupported config type %T", target)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Environment == "development" || c.Environment == "production",
+		"environment must be development or production, got %q", c.Environment)
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port must be between 1 and 65535, got %d", c.Server.Port)
+	check(c.Server.ReadHeaderTimeout > 0, "server.read_header_timeout must be positive")
+	check(c.Server.ReadTimeout >= 0, "server.read_timeout must not be negative")
+	check(c.Server.WriteTimeout >= 0, "server.write_timeout must not be negative")
+	check(c.Server.IdleTimeout >= 0, "server.idle_timeout must not be negative")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		check(err == nil || net.ParseIP(proxy) != nil,
+			"server.trusted_proxies must hold addresses or CIDR ranges, got %q", proxy)
+	}
+
+	// Only the settings of the selected backend matter
+	switch c.Storage.Backend {
+	case "mongodb":
+		check(strings.HasPrefix(c.Mongo.URI, "mongodb://") || strings.HasPrefix(c.Mongo.URI, "mongodb+srv://"),
+			"mongodb.uri must start with mongodb:// or mongodb+srv://")
+		check(c.Mongo.Database != "", "mongodb.database is required")
+		check(c.Mongo.MaxPoolSize == 0 || c.Mongo.MinPoolSize <= c.Mongo.MaxPoolSize,
+			"mongodb.min_pool_size must not exceed max_pool_size")
+		check(c.Mongo.ConnectTimeout > 0, "mongodb.connect_timeout must be positive")
+		check(c.Mongo.ServerSelectionTimeout > 0, "mongodb.server_selection_timeout must be positive")
+	case "postgres":
+		check(strings.HasPrefix(c.Postgres.URL, "postgres://") || strings.HasPrefix(c.Postgres.URL, "postgresql://"),
+			"postgres.url must start with postgres:// or postgresql://")
+		check(c.Postgres.MaxConns >= 0 && c.Postgres.MinConns >= 0, "postgres.max_conns and min_conns must not be negative")
+		check(c.Postgres.MaxConns == 0 || c.Postgres.MinConns <= c.Postgres.MaxConns,
+			"postgres.min_conns must not exceed max_conns")
+		check(c.Postgres.ConnectTimeout > 0, "postgres.connect_timeout must be positive")
+	case "sqlite":
+		check(c.SQLite.Path != "", "sqlite.path is required")
+		check(c.SQLite.BusyTimeout >= 0, "sqlite.busy_timeout must not be negative")
+	default:
+		check(false, "storage.backend must be mongodb, postgres or sqlite, got %q", c.Storage.Backend)
+	}
+
+	check(c.Logging.Format == "json" || c.Logging.Format == "text", "logging.format must be json or text, got %q", c.Logging.Format)
+	switch strings.ToLower(c.Logging.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		check(false, "logging.level must be debug, info, warn or error, got %q", c.Logging.Level)
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	case "file":
+		check(c.Tracing.File != "", "tracing.file is required for the file exporter")
+	default:
+		check(false, "tracing.exporter must be none, stdout, file or otlp, got %q", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1")
+
+	check(c.Security.HSTSMaxAge >= 0, "security.hsts_max_age must not be negative")
+	check(!strings.ContainsAny(c.Security.CSP+c.Security.FrameAncestors+c.Security.ReferrerPolicy+c.Security.PermissionsPolicy, "\r\n"),
+		"security headers must not contain line breaks")
+
+	check(c.Events.PollInterval > 0, "events.poll_interval must be positive")
+	check(c.Events.BatchSize > 0, "events.batch_size must be positive")
+	check(c.Events.HandlerTimeout > 0, "events.handler_timeout must be positive")
+	check(c.Events.MaxBackoff >= time.Second, "events.max_backoff must be at least 1s")
+	check(c.Events.MaxAttempts > 0, "events.max_attempts must be positive")
+
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	check(c.Webhooks.MinBackoff > 0, "webhooks.min_backoff must be positive")
+	check(c.Webhooks.MaxBackoff >= c.Webhooks.MinBackoff, "webhooks.max_backoff must not be less than webhooks.min_backoff")
+	check(c.Webhooks.PollInterval > 0, "webhooks.poll_interval must be positive")
+
+	check(c.GraphQL.MaxDepth >= 0, "graphql.max_depth must not be negative")
+	check(c.GraphQL.MaxComplexity >= 0, "graphql.max_complexity must not be negative")
+	if c.Features.GRPC {
+		check(c.GRPC.Port > 0 && c.GRPC.Port < 65536, "grpc.port must be between 1 and 65535, got %d", c.GRPC.Port)
+		check(c.GRPC.Port != c.Server.Port, "grpc.port must differ from server.port")
+	}
+
+	if c.Features.RateLimit {
+		if _, err := c.RateLimit.Middleware(true); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Development reports whether the service runs in development mode
+func (c *Config) Development() bool {
+	return c.Environment == "development"
+}
+
+// Limits converts the settings into query limits, zero disables a limit
+func (c GraphQLConfig) Limits() graphql.Limits {
+	return graphql.Limits{
+		MaxDepth:      c.MaxDepth,
+		MaxComplexity: c.MaxComplexity,
+	}
+}
+
+// Options converts the settings into connection pool options
+func (c PostgresConfig) Options() postgres.Options {
+	return postgres.Options{
+		MaxConns:        int32(c.MaxConns),
+		MinConns:        int32(c.MinConns),
+		MaxConnIdleTime: c.MaxConnIdleTime,
+		ConnectTimeout:  c.ConnectTimeout,
+	}
+}
+
+// Options converts the settings into dispatcher options
+func (c EventsConfig) Options() events.Options {
+	return events.Options{
+		PollInterval:   c.PollInterval,
+		BatchSize:      c.BatchSize,
+		HandlerTimeout: c.HandlerTimeout,
+		MaxBackoff:     c.MaxBackoff,
+		MaxAttempts:    c.MaxAttempts,
+	}
+}
+
+// Options converts the settings into webhook sender options
+func (c WebhooksConfig) Options() webhook.Options {
+	return webhook.Options{
+		Timeout:      c.Timeout,
+		MaxAttempts:  c.MaxAttempts,
+		MinBackoff:   c.MinBackoff,
+		MaxBackoff:   c.MaxBackoff,
+		PollInterval: c.PollInterval,
+		AllowPrivate: c.AllowPrivate,
+	}
+}
+
+// Middleware converts the settings into the security headers middleware
+// config, without HSTS in development
+func (c SecurityConfig) Middleware(development bool) middleware.SecurityConfig {
+	cfg := middleware.SecurityConfig{
+		CSP:               c.CSP,
+		CSPReportOnly:     c.CSPReportOnly,
+		FrameAncestors:    c.FrameAncestors,
+		HSTSMaxAge:        c.HSTSMaxAge,
+		ReferrerPolicy:    c.ReferrerPolicy,
+		PermissionsPolicy: c.PermissionsPolicy,
+	}
+	if development {
+		cfg.HSTSMaxAge = 0
+	}
+	return cfg
+}
+
+// Middleware converts the limits into the rate limiting middleware config
+func (c RateLimitConfig) Middleware(enabled bool) (middleware.RateLimitConfig, error) {
+	cfg := middleware.DefaultRateLimitConfig()
+	cfg.Enabled = enabled
+	cfg.Tokens = c.Tokens
+
+	limits := []struct {
+		name   string
+		value  string
+		target *middleware.Limit
+	}{
+		{"rate_limit.read", c.Read, &cfg.IP.Read},
+		{"rate_limit.write", c.Write, &cfg.IP.Write},
+		{"rate_limit.search", c.Search, &cfg.IP.Search},
+		{"rate_limit.token_read", c.TokenRead, &cfg.Token.Read},
+		{"rate_limit.token_write", c.TokenWrite, &cfg.Token.Write},
+		{"rate_limit.token_search", c.TokenSearch, &cfg.Token.Search},
+	}
+	for _, l := range limits {
+		limit, err := middleware.ParseLimit(l.value)
+		if err != nil {
+			return cfg, fmt.Errorf("%s: %w", l.name, err)
+		}
+		*l.target = limit
+	}
+
+	return cfg, nil
+}
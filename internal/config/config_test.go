@@ -0,0 +1,168 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  port: 9000
+  write_timeout: 45s
+mongodb:
+  uri: mongodb://file:27017
+  database: from_file
+logging:
+  level: debug
+`)
+	t.Setenv("MONGODB_DATABASE", "from_env")
+	t.Setenv("LOG_LEVEL", "warn")
+
+	cfg, err := Load([]string{"-config", path, "-log-level", "error"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, 45*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, "mongodb://file:27017", cfg.Mongo.URI)
+	assert.Equal(t, "from_env", cfg.Mongo.Database)
+	assert.Equal(t, "error", cfg.Logging.Level)
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadHeaderTimeout)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	path := writeConfigFile(t, "environment: development\n")
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.Development())
+}
+
+func TestLoad_UnknownField(t *testing.T) {
+	path := writeConfigFile(t, "server:\n  prot: 9000\n")
+
+	_, err := Load([]string{"-config", path})
+	assert.Error(t, err)
+}
+
+func TestLoad_InvalidEnv(t *testing.T) {
+	t.Setenv("SERVER_READ_TIMEOUT", "soon")
+
+	_, err := Load(nil)
+	assert.ErrorContains(t, err, "SERVER_READ_TIMEOUT")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.Server.Port = 0
+	cfg.Logging.Format = "xml"
+	cfg.RateLimit.Search = "lots"
+	cfg.Webhooks.MaxBackoff = time.Second
+	cfg.GraphQL.MaxDepth = -1
+	cfg.GRPC.Port = 0
+	cfg.Security.HSTSMaxAge = -time.Second
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy"}
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "server.port")
+	assert.ErrorContains(t, err, "logging.format")
+	assert.ErrorContains(t, err, "rate_limit.search")
+	assert.ErrorContains(t, err, "webhooks.max_backoff")
+	assert.ErrorContains(t, err, "graphql.max_depth")
+	assert.ErrorContains(t, err, "grpc.port")
+	assert.ErrorContains(t, err, "security.hsts_max_age")
+	assert.ErrorContains(t, err, `"proxy"`)
+
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "192.0.2.1"}
+	cfg.Features.RateLimit = false
+	cfg.Security.HSTSMaxAge = 0
+	cfg.GraphQL.MaxDepth = 0
+	cfg.GRPC.Port = 50051
+	cfg.Webhooks.MaxBackoff = time.Minute
+	cfg.Server.Port = 8080
+	cfg.Logging.Format = "text"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_Storage(t *testing.T) {
+	cfg := Default()
+	cfg.Storage.Backend = "postgres"
+	cfg.Mongo.URI = "ignored"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Postgres.URL = "localhost:5432"
+	assert.ErrorContains(t, cfg.Validate(), "postgres.url")
+
+	cfg.Storage.Backend = "sqlite"
+	assert.NoError(t, cfg.Validate())
+	cfg.SQLite.Path = ""
+	assert.ErrorContains(t, cfg.Validate(), "sqlite.path")
+
+	cfg.Storage.Backend = "redis"
+	assert.ErrorContains(t, cfg.Validate(), "storage.backend")
+}
+
+func TestRateLimitConfig_Middleware(t *testing.T) {
+	cfg, err := Default().RateLimit.Middleware(true)
+	require.NoError(t, err)
+	assert.True(t, cfg.Enabled)
+	assert.Equal(t, 60, cfg.IP.Write.Burst)
+	assert.Equal(t, float64(1), cfg.IP.Write.Rate)
+	assert.Empty(t, cfg.Tokens)
+}
+
+func TestLoad_Lists(t *testing.T) {
+	t.Setenv("RATE_LIMIT_TOKENS", "one, two,")
+	t.Setenv("SERVER_TRUSTED_PROXIES", "10.0.0.1")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, cfg.RateLimit.Tokens)
+	assert.Equal(t, []string{"10.0.0.1"}, cfg.Server.TrustedProxies)
+	assert.Empty(t, Default().Server.TrustedProxies)
+}
+
+func TestSecurityConfig_Middleware(t *testing.T) {
+	t.Setenv("CSP_REPORT_ONLY", "true")
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+
+	production := cfg.Security.Middleware(false)
+	assert.True(t, production.CSPReportOnly)
+	assert.Contains(t, production.CSP, "'nonce-{nonce}'")
+	assert.NotContains(t, production.CSP, "https:", "every script is served from this origin")
+	assert.Equal(t, 365*24*time.Hour, production.HSTSMaxAge)
+
+	development := cfg.Security.Middleware(true)
+	assert.Zero(t, development.HSTSMaxAge)
+	assert.Equal(t, production.CSP, development.CSP)
+}
+
+func TestLoadCommand(t *testing.T) {
+	cfg, rest, err := LoadCommand("newsctl", []string{"-mongodb-database", "other", "indexes", "-check"})
+	require.NoError(t, err)
+	assert.Equal(t, "other", cfg.Mongo.Database)
+	assert.Equal(t, []string{"indexes", "-check"}, rest)
+
+	_, err = Load([]string{"indexes"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxBatchSize is the most articles a single batch operation may touch
+const MaxBatchSize = 500
+
+// ErrInvalidBatch is returned for batch operations that cannot be run
+var ErrInvalidBatch = errors.New("invalid batch operation")
+
+// BatchUpdate is a change applied to many articles at once
+type BatchUpdate struct {
+	// Status replaces the status of every article when set
+	Status string
+	// Tags are added to every article, keeping the existing ones
+	Tags []string
+}
+
+// Validate checks that the update changes something and that its status
+// and tags are acceptable
+func (u BatchUpdate) Validate() error {
+	if u.Status == "" && len(u.Tags) == 0 {
+		return errors.New("batch update changes nothing")
+	}
+	switch u.Status {
+	case "", StatusDraft, StatusPublished, StatusArchived:
+	default:
+		return fmt.Errorf("unknown status %q", u.Status)
+	}
+	for _, tag := range u.Tags {
+		if tag == "" || len(tag) > 50 {
+			return fmt.Errorf("invalid tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// BatchResult is the outcome of a batch operation for one article
+type BatchResult struct {
+	ID    string `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ValidateBatchIDs checks the number of IDs in a batch
+func ValidateBatchIDs(ids []string) error {
+	if len(ids) == 0 {
+		return errors.New("no articles selected")
+	}
+	if len(ids) > MaxBatchSize {
+		return fmt.Errorf("at most %d articles can be changed at once", MaxBatchSize)
+	}
+	return nil
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty and repeated ones
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
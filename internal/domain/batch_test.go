@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUpdate_Validate(t *testing.T) {
+	assert.NoError(t, BatchUpdate{Status: StatusArchived}.Validate())
+	assert.NoError(t, BatchUpdate{Tags: []string{"go"}}.Validate())
+
+	assert.Error(t, BatchUpdate{}.Validate())
+	assert.Error(t, BatchUpdate{Status: "hidden"}.Validate())
+	assert.Error(t, BatchUpdate{Tags: []string{strings.Repeat("x", 51)}}.Validate())
+}
+
+func TestValidateBatchIDs(t *testing.T) {
+	assert.NoError(t, ValidateBatchIDs([]string{"a"}))
+	assert.Error(t, ValidateBatchIDs(nil))
+	assert.Error(t, ValidateBatchIDs(make([]string, MaxBatchSize+1)))
+}
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"go", "release notes"}, NormalizeTags([]string{" Go ", "", "release notes", "go"}))
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Types of article events
+const (
+	EventArticleCreated = "ArticleCreated"
+	EventArticleUpdated = "ArticleUpdated"
+	EventArticleDeleted = "ArticleDeleted"
+)
+
+// Event records a change to an article. IDs increase over time, so events of
+// one article sort in the order they happened.
+type Event struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	ArticleID string `json:"article_id"`
+	// Article is the state after the change, absent for deletions and batch
+	// updates
+	Article    *News     `json:"article,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewEvent creates an event of the given type for an article
+func NewEvent(eventType, articleID string, article *News) Event {
+	return Event{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		ArticleID:  articleID,
+		Article:    article,
+		OccurredAt: time.Now(),
+	}
+}
+
+// Outbox stores events for delivery once the change that caused them is
+// committed
+type Outbox interface {
+	Append(ctx context.Context, events ...Event) error
+}
+
+// Transactor runs fn so that the repository and outbox writes it makes with
+// the context it is given are committed together or not at all
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Publisher is told about events in process once the change that caused them
+// is committed. Publishing is best effort and must not block.
+type Publisher interface {
+	Publish(events ...Event)
+}
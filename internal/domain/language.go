@@ -0,0 +1,22 @@
+package domain
+
+import "errors"
+
+// DefaultLanguage is the language of articles that do not name one
+const DefaultLanguage = "en"
+
+// ErrInvalidTranslation is returned for translations that cannot be added
+var ErrInvalidTranslation = errors.New("invalid translation")
+
+// Languages lists the ISO 639-1 codes articles may be written in
+var Languages = []string{"en", "uk"}
+
+// SupportedLanguage reports whether lang is one of Languages
+func SupportedLanguage(lang string) bool {
+	for _, supported := range Languages {
+		if lang == supported {
+			return true
+		}
+	}
+	return false
+}
@@ -1,36 +1,120 @@
 package domain
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// News represents a news article in the system
+// Publication statuses of a news article
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// ErrNotFound is returned when no article has the requested ID
+var ErrNotFound = errors.New("news not found")
+
+// ErrConflict is returned when an article clashes with an existing one on
+// its ID or slug
+var ErrConflict = errors.New("news already exists")
+
+// News represents a news article in the system. TranslationOf links a
+// language variant to the article it translates and is empty for originals.
 type News struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title     string             `bson:"title" json:"title" validate:"required,min=3,max=200"`
-	Content   string             `bson:"content" json:"content" validate:"required,min=10"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Title         string              `bson:"title" json:"title" validate:"required,min=3,max=200"`
+	Content       string              `bson:"content" json:"content" validate:"required,min=10"`
+	Slug          string              `bson:"slug,omitempty" json:"slug,omitempty"`
+	Status        string              `bson:"status,omitempty" json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
+	Tags          []string            `bson:"tags,omitempty" json:"tags,omitempty" validate:"max=20,dive,required,max=50"`
+	Language      string              `bson:"language,omitempty" json:"language,omitempty" validate:"omitempty,oneof=en uk"`
+	TranslationOf *primitive.ObjectID `bson:"translation_of,omitempty" json:"translation_of,omitempty"`
+	PublishedAt   *time.Time          `bson:"published_at,omitempty" json:"published_at,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// ApplyDefaults fills in the status, language, slug and publication time of
+// a new article when they are not set
+func (n *News) ApplyDefaults() {
+	if n.Status == "" {
+		n.Status = StatusPublished
+	}
+	if n.Language == "" {
+		n.Language = DefaultLanguage
+	}
+	if n.Slug == "" {
+		n.Slug = UniqueSlug(n.Title)
+	}
+	if n.Status == StatusPublished && n.PublishedAt == nil {
+		now := time.Now()
+		n.PublishedAt = &now
+	}
+}
+
+// TranslationGroup returns the ID shared by all language variants of the
+// article: that of the original
+func (n *News) TranslationGroup() primitive.ObjectID {
+	if n.TranslationOf != nil {
+		return *n.TranslationOf
+	}
+	return n.ID
 }
 
 // NewsRepository defines the interface for news storage operations
 type NewsRepository interface {
-	Create(news *News) error
-	GetByID(id string) (*News, error)
-	GetAll(page, limit int) ([]*News, int64, error)
-	Update(news *News) error
-	Delete(id string) error
-	Search(query string, page, limit int) ([]*News, int64, error)
+	Create(ctx context.Context, news *News) error
+	GetByID(ctx context.Context, id string) (*News, error)
+	// GetAll lists the published articles in lang, or in every language when
+	// lang is empty
+	GetAll(ctx context.Context, lang string, page, limit int) ([]*News, int64, error)
+	Update(ctx context.Context, news *News) error
+	Delete(ctx context.Context, id string) error
+	// Search finds published articles in lang, or in every language when lang
+	// is empty
+	Search(ctx context.Context, lang, query string, page, limit int) ([]*News, int64, error)
+	// Insert stores news as given, keeping its ID and timestamps when set
+	Insert(ctx context.Context, news *News) error
+	// Upsert replaces the article with the same ID, or the same slug when
+	// news has no ID, and inserts it when there is none. It reports whether
+	// the article was inserted.
+	Upsert(ctx context.Context, news *News) (bool, error)
+	// Each calls fn for every article in ID order until fn returns an error
+	Each(ctx context.Context, fn func(*News) error) error
+	// DeleteMany deletes the articles with the given IDs
+	DeleteMany(ctx context.Context, ids []string) ([]BatchResult, error)
+	// UpdateMany applies update to the articles with the given IDs
+	UpdateMany(ctx context.Context, ids []string, update BatchUpdate) ([]BatchResult, error)
+	// Translations returns every language variant of the article with the
+	// given ID, the article itself included
+	Translations(ctx context.Context, id string) ([]*News, error)
 }
 
 // NewsService defines the interface for news business logic
 type NewsService interface {
-	CreateNews(news *News) error
-	GetNewsByID(id string) (*News, error)
-	GetAllNews(page, limit int) ([]*News, int64, error)
-	UpdateNews(news *News) error
-	DeleteNews(id string) error
-	SearchNews(query string, page, limit int) ([]*News, int64, error)
+	CreateNews(ctx context.Context, news *News) error
+	GetNewsByID(ctx context.Context, id string) (*News, error)
+	GetAllNews(ctx context.Context, lang string, page, limit int) ([]*News, int64, error)
+	UpdateNews(ctx context.Context, news *News) error
+	DeleteNews(ctx context.Context, id string) error
+	SearchNews(ctx context.Context, lang, query string, page, limit int) ([]*News, int64, error)
+	DeleteNewsBatch(ctx context.Context, ids []string) ([]BatchResult, error)
+	UpdateNewsBatch(ctx context.Context, ids []string, update BatchUpdate) ([]BatchResult, error)
+	// CreateTranslation adds news as a language variant of the article with
+	// the given ID
+	CreateTranslation(ctx context.Context, id string, news *News) error
+	// GetTranslations returns every language variant of an article
+	GetTranslations(ctx context.Context, id string) ([]*News, error)
+	// ImportNews stores news as given, keeping its ID and timestamps when
+	// set. With upsert it replaces the article with the same ID, or slug when
+	// news has no ID, otherwise an existing one is ErrConflict. It reports
+	// whether the article was created.
+	ImportNews(ctx context.Context, news *News, upsert bool) (bool, error)
+	// GetRelatedNews returns up to limit published articles like the one
+	// with the given ID, best match first
+	GetRelatedNews(ctx context.Context, id string, limit int) ([]*News, error)
 }
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+const maxSlugLength = 80
+
+// Slugify turns a title into a lowercase, dash separated URL segment
+func Slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimSuffix(strings.ToValidUTF8(slug[:maxSlugLength], ""), "-")
+	}
+	return slug
+}
+
+// UniqueSlug returns the slug of title with a random suffix so that articles
+// sharing a title still get distinct slugs
+func UniqueSlug(title string) string {
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	suffix := hex.EncodeToString(b)
+
+	if slug := Slugify(title); slug != "" {
+		return slug + "-" + suffix
+	}
+	return suffix
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Hello, World!":          "hello-world",
+		"  Go 1.21 released  ":   "go-1-21-released",
+		"Новини дня":             "новини-дня",
+		"---":                    "",
+		strings.Repeat("a", 100): strings.Repeat("a", maxSlugLength),
+	}
+	for title, expected := range cases {
+		assert.Equal(t, expected, Slugify(title), title)
+	}
+}
+
+func TestUniqueSlug(t *testing.T) {
+	first := UniqueSlug("Breaking News")
+	second := UniqueSlug("Breaking News")
+
+	assert.True(t, strings.HasPrefix(first, "breaking-news-"))
+	assert.NotEqual(t, first, second)
+	assert.Len(t, UniqueSlug("!!!"), 6)
+}
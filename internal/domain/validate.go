@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Report fields by their JSON names, as users know them
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.Split(field.Tag.Get("json"), ",")[0]
+	})
+	return v
+}
+
+// Validate checks news against the constraints in its validate tags
+func (n *News) Validate() error {
+	err := validate.Struct(n)
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	problems := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		problems = append(problems, fmt.Sprintf("%s: %s", fe.Field(), rule))
+	}
+	return fmt.Errorf("invalid news: %s", strings.Join(problems, ", "))
+}
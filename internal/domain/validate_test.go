@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNews_Validate(t *testing.T) {
+	valid := News{Title: "Valid title", Content: "Long enough content"}
+	assert.NoError(t, valid.Validate())
+
+	valid.Status = StatusDraft
+	assert.NoError(t, valid.Validate())
+
+	invalid := map[string]News{
+		"missing title":  {Content: "Long enough content"},
+		"short content":  {Title: "Valid title", Content: "short"},
+		"unknown status": {Title: "Valid title", Content: "Long enough content", Status: "hidden"},
+	}
+	for name, news := range invalid {
+		assert.Error(t, news.Validate(), name)
+	}
+}
+
+func TestNews_ValidateMessage(t *testing.T) {
+	news := News{Title: "ab", Content: "Long enough content", Status: "hidden"}
+	assert.EqualError(t, news.Validate(), "invalid news: title: min=3, status: oneof=draft published archived")
+}
@@ -0,0 +1,310 @@
+// Package events delivers domain events from the outbox to subscribers
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+// Record is an outbox entry waiting for delivery
+type Record struct {
+	Event domain.Event
+	// DeliveredTo lists the subscribers that already handled the event
+	DeliveredTo []string
+	Attempts    int
+	// NextAttemptAt is when a failed delivery may be retried
+	NextAttemptAt time.Time
+}
+
+// Store is the outbox as seen by the dispatcher
+type Store interface {
+	// Acquire takes or renews the dispatch lease for owner, so that only one
+	// process delivers events at a time
+	Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error)
+	// Pending returns undelivered events in the order they were appended,
+	// starting after the event with ID after, or from the first when empty
+	Pending(ctx context.Context, after string, limit int) ([]Record, error)
+	// Delivered records that subscriber handled the event
+	Delivered(ctx context.Context, id, subscriber string) error
+	// Complete marks the event as delivered to every subscriber
+	Complete(ctx context.Context, id string) error
+	// Retry records a failed attempt and when to try again
+	Retry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error
+	// Fail gives up on the event after its last attempt. It is kept as a dead
+	// letter and no longer pending.
+	Fail(ctx context.Context, id string, attempts int, lastErr string) error
+}
+
+// Handler processes an event. Returning an error makes the dispatcher
+// deliver it again later, so handlers must tolerate duplicates.
+type Handler func(ctx context.Context, event domain.Event) error
+
+type subscriber struct {
+	name   string
+	handle Handler
+}
+
+// Options tunes a dispatcher
+type Options struct {
+	// PollInterval is how often the outbox is checked for new events
+	PollInterval time.Duration
+	// BatchSize is how many events are read from the outbox at once
+	BatchSize int
+	// HandlerTimeout bounds a single delivery to a subscriber
+	HandlerTimeout time.Duration
+	// MinBackoff and MaxBackoff bound the exponential delay between attempts
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxAttempts is how often an event is tried before it is given up on
+	MaxAttempts int
+}
+
+// DefaultOptions returns the options used for zero fields
+func DefaultOptions() Options {
+	return Options{
+		PollInterval:   time.Second,
+		BatchSize:      100,
+		HandlerTimeout: 10 * time.Second,
+		MinBackoff:     time.Second,
+		MaxBackoff:     5 * time.Minute,
+		MaxAttempts:    20,
+	}
+}
+
+// Dispatcher delivers outbox events to subscribers at least once. Events of
+// the same article are delivered in order: while one fails, later events of
+// that article wait, until it is given up on after MaxAttempts.
+type Dispatcher struct {
+	store       Store
+	subscribers []subscriber
+	opts        Options
+	logger      *slog.Logger
+	owner       string
+	now         func() time.Time
+}
+
+// NewDispatcher creates a dispatcher reading from store
+func NewDispatcher(store Store, opts Options, logger *slog.Logger) *Dispatcher {
+	defaults := DefaultOptions()
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.HandlerTimeout <= 0 {
+		opts.HandlerTimeout = defaults.HandlerTimeout
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaults.MinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaults.MaxBackoff, opts.MinBackoff)
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+
+	hostname, _ := os.Hostname()
+	return &Dispatcher{
+		store:  store,
+		opts:   opts,
+		logger: logger.With("component", "events"),
+		owner:  hostname + ":" + strconv.Itoa(os.Getpid()),
+		now:    time.Now,
+	}
+}
+
+// Subscribe registers a handler under a unique name. The name is stored with
+// each event it handled, so it must stay stable across restarts.
+// Subscribers must be registered before Run.
+func (d *Dispatcher) Subscribe(name string, handle Handler) {
+	d.subscribers = append(d.subscribers, subscriber{name: name, handle: handle})
+}
+
+// Run delivers events until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			d.logger.ErrorContext(ctx, "failed to dispatch events", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch delivers the pending events once, if this process holds the lease
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	if acquired, err := d.lease(ctx); err != nil || !acquired {
+		return err
+	}
+
+	// Articles stay blocked across batches, so their later events keep waiting
+	blocked := make(map[string]bool)
+	after := ""
+	for {
+		records, err := d.store.Pending(ctx, after, d.opts.BatchSize)
+		if err != nil {
+			return fmt.Errorf("read outbox: %w", err)
+		}
+
+		if err := d.dispatchBatch(ctx, records, blocked); err != nil {
+			return err
+		}
+		if len(records) < d.opts.BatchSize {
+			return nil
+		}
+		// Waiting events are passed over, not read again
+		after = records[len(records)-1].Event.ID
+	}
+}
+
+// lease takes or renews the dispatch lease. It outlives a few polls and the
+// delivery of one event to every subscriber, and is renewed before each
+// delivery so a long backlog cannot outlast it.
+func (d *Dispatcher) lease(ctx context.Context) (bool, error) {
+	ttl := 5*d.opts.PollInterval + time.Duration(max(len(d.subscribers), 1))*d.opts.HandlerTimeout
+	acquired, err := d.store.Acquire(ctx, d.owner, ttl)
+	if err != nil {
+		return false, fmt.Errorf("acquire dispatch lease: %w", err)
+	}
+	return acquired, nil
+}
+
+// errLeaseLost stops dispatching once another process holds the lease
+var errLeaseLost = errors.New("dispatch lease lost")
+
+func (d *Dispatcher) dispatchBatch(ctx context.Context, records []Record, blocked map[string]bool) error {
+	now := d.now()
+
+	for _, record := range records {
+		event := record.Event
+		if blocked[event.ArticleID] {
+			continue
+		}
+		if record.NextAttemptAt.After(now) {
+			blocked[event.ArticleID] = true
+			continue
+		}
+
+		acquired, err := d.lease(ctx)
+		if err != nil {
+			return err
+		}
+		if !acquired {
+			return errLeaseLost
+		}
+
+		if err := d.deliver(ctx, record); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err := d.failed(ctx, record, now, err); err != nil {
+				return err
+			}
+			// A dead letter no longer holds up the article
+			blocked[event.ArticleID] = record.Attempts+1 < d.opts.MaxAttempts
+			continue
+		}
+
+		if err := d.store.Complete(ctx, event.ID); err != nil {
+			return fmt.Errorf("complete event %s: %w", event.ID, err)
+		}
+		d.logger.DebugContext(ctx, "event delivered", "event_id", event.ID, "type", event.Type, "article_id", event.ArticleID)
+	}
+	return nil
+}
+
+// failed schedules another attempt at delivering the event, or gives up on it
+// after the last one
+func (d *Dispatcher) failed(ctx context.Context, record Record, now time.Time, deliveryErr error) error {
+	event := record.Event
+	attempts := record.Attempts + 1
+	if attempts >= d.opts.MaxAttempts {
+		d.logger.ErrorContext(ctx, "event delivery abandoned",
+			"event_id", event.ID,
+			"type", event.Type,
+			"article_id", event.ArticleID,
+			"attempts", attempts,
+			"error", deliveryErr,
+		)
+		if err := d.store.Fail(ctx, event.ID, attempts, deliveryErr.Error()); err != nil {
+			return fmt.Errorf("record abandoned event %s: %w", event.ID, err)
+		}
+		return nil
+	}
+
+	next := now.Add(Backoff(attempts, d.opts.MinBackoff, d.opts.MaxBackoff))
+	d.logger.WarnContext(ctx, "event delivery failed",
+		"event_id", event.ID,
+		"type", event.Type,
+		"article_id", event.ArticleID,
+		"attempts", attempts,
+		"retry_at", next,
+		"error", deliveryErr,
+	)
+	if err := d.store.Retry(ctx, event.ID, attempts, next, deliveryErr.Error()); err != nil {
+		return fmt.Errorf("record failed delivery of %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+// deliver hands the event to every subscriber that has not handled it yet
+func (d *Dispatcher) deliver(ctx context.Context, record Record) error {
+	done := make(map[string]bool, len(record.DeliveredTo))
+	for _, name := range record.DeliveredTo {
+		done[name] = true
+	}
+
+	var errs []error
+	for _, sub := range d.subscribers {
+		if done[sub.name] {
+			continue
+		}
+
+		if err := d.handle(ctx, sub, record.Event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		if err := d.store.Delivered(ctx, record.Event.ID, sub.name); err != nil {
+			return fmt.Errorf("record delivery to %s: %w", sub.name, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Dispatcher) handle(ctx context.Context, sub subscriber, event domain.Event) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.HandlerTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handle(ctx, event)
+}
+
+// Backoff returns the delay before the next attempt after the given number of
+// failed ones, doubling from minDelay up to maxDelay
+func Backoff(attempts int, minDelay, maxDelay time.Duration) time.Duration {
+	delay := minDelay
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
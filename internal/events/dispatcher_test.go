@@ -0,0 +1,264 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+// memStore is an in-memory outbox
+type memStore struct {
+	mu        sync.Mutex
+	records   map[string]*Record
+	completed []string
+	failed    []string
+	leased    bool
+	// leases is how often Acquire succeeds before another process takes
+	// over, unlimited when zero
+	leases   int
+	acquired int
+}
+
+func newMemStore(events ...domain.Event) *memStore {
+	s := &memStore{records: make(map[string]*Record)}
+	for _, event := range events {
+		s.records[event.ID] = &Record{Event: event}
+	}
+	return s
+}
+
+func (s *memStore) Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leased || (s.leases > 0 && s.acquired >= s.leases) {
+		return false, nil
+	}
+	s.acquired++
+	return true, nil
+}
+
+func (s *memStore) Pending(ctx context.Context, after string, limit int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []Record
+	for _, record := range s.records {
+		if record.Event.ID > after {
+			records = append(records, *record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Event.ID < records[j].Event.ID })
+	if len(records) > limit {
+		records = records[:limit]
+	}
+	return records, nil
+}
+
+func (s *memStore) Delivered(ctx context.Context, id, subscriber string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[id]
+	record.DeliveredTo = append(record.DeliveredTo, subscriber)
+	return nil
+}
+
+func (s *memStore) Complete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	s.completed = append(s.completed, id)
+	return nil
+}
+
+func (s *memStore) Retry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[id]
+	record.Attempts = attempts
+	record.NextAttemptAt = next
+	return nil
+}
+
+func (s *memStore) Fail(ctx context.Context, id string, attempts int, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+	s.failed = append(s.failed, id)
+	return nil
+}
+
+func event(id, articleID string) domain.Event {
+	return domain.Event{ID: id, Type: domain.EventArticleUpdated, ArticleID: articleID}
+}
+
+func newTestDispatcher(store Store) *Dispatcher {
+	return newTestDispatcherWith(store, Options{})
+}
+
+func newTestDispatcherWith(store Store, opts Options) *Dispatcher {
+	return NewDispatcher(store, opts, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestDispatcher_DeliversInOrder(t *testing.T) {
+	store := newMemStore(event("1", "a"), event("2", "b"), event("3", "a"))
+	d := newTestDispatcher(store)
+
+	var got []string
+	d.Subscribe("recorder", func(ctx context.Context, e domain.Event) error {
+		got = append(got, e.ID)
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"1", "2", "3"}, got)
+	assert.Empty(t, store.records)
+}
+
+func TestDispatcher_FailureBlocksArticle(t *testing.T) {
+	store := newMemStore(event("1", "a"), event("2", "b"), event("3", "a"))
+	d := newTestDispatcher(store)
+
+	failing := true
+	var got []string
+	d.Subscribe("recorder", func(ctx context.Context, e domain.Event) error {
+		if e.ID == "1" && failing {
+			return errors.New("unavailable")
+		}
+		got = append(got, e.ID)
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"2"}, got, "later events of article a wait for the failed one")
+	assert.Equal(t, 1, store.records["1"].Attempts)
+
+	// Not retried before the backoff expires
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"2"}, got)
+
+	failing = false
+	d.now = func() time.Time { return time.Now().Add(time.Minute) }
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"2", "1", "3"}, got)
+}
+
+func TestDispatcher_PassesOverWaitingBatch(t *testing.T) {
+	store := newMemStore(event("1", "a"), event("2", "a"), event("3", "b"), event("4", "a"))
+	store.records["1"].Attempts = 1
+	store.records["1"].NextAttemptAt = time.Now().Add(time.Hour)
+	d := newTestDispatcherWith(store, Options{BatchSize: 2})
+
+	var got []string
+	d.Subscribe("recorder", func(ctx context.Context, e domain.Event) error {
+		got = append(got, e.ID)
+		return nil
+	})
+
+	// The first batch only holds waiting events of article a
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"3"}, got)
+	assert.Len(t, store.records, 3)
+}
+
+func TestDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	store := newMemStore(event("1", "a"), event("2", "a"))
+	d := newTestDispatcherWith(store, Options{MaxAttempts: 2})
+
+	var got []string
+	d.Subscribe("recorder", func(ctx context.Context, e domain.Event) error {
+		if e.ID == "1" {
+			return errors.New("poison")
+		}
+		got = append(got, e.ID)
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Empty(t, got)
+	assert.Equal(t, 1, store.records["1"].Attempts)
+
+	// The last attempt fails too, and the article moves on
+	d.now = func() time.Time { return time.Now().Add(time.Minute) }
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"1"}, store.failed)
+	assert.Equal(t, []string{"2"}, got)
+	assert.Empty(t, store.records)
+}
+
+func TestDispatcher_RetriesOnlyFailedSubscribers(t *testing.T) {
+	store := newMemStore(event("1", "a"))
+	d := newTestDispatcher(store)
+
+	calls := map[string]int{}
+	d.Subscribe("ok", func(ctx context.Context, e domain.Event) error {
+		calls["ok"]++
+		return nil
+	})
+	d.Subscribe("flaky", func(ctx context.Context, e domain.Event) error {
+		calls["flaky"]++
+		if calls["flaky"] == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, []string{"ok"}, store.records["1"].DeliveredTo)
+
+	d.now = func() time.Time { return time.Now().Add(time.Minute) }
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, map[string]int{"ok": 1, "flaky": 2}, calls)
+	assert.Equal(t, []string{"1"}, store.completed)
+}
+
+func TestDispatcher_WithoutLease(t *testing.T) {
+	store := newMemStore(event("1", "a"))
+	store.leased = true
+	d := newTestDispatcher(store)
+	d.Subscribe("recorder", func(ctx context.Context, e domain.Event) error {
+		t.Fatal("delivered without the lease")
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Len(t, store.records, 1)
+}
+
+func TestDispatcher_RenewsLease(t *testing.T) {
+	store := newMemStore(event("1", "a"), event("2", "b"), event("3", "c"))
+	d := newTestDispatcherWith(store, Options{BatchSize: 1})
+	var got []string
+	d.Subscribe("recorder", func(ctx context.Context, e domain.Event) error {
+		got = append(got, e.ID)
+		return nil
+	})
+
+	require.NoError(t, d.Dispatch(context.Background()))
+	assert.Equal(t, 4, store.acquired, "once per dispatch and once per event")
+
+	// The lease runs out after the first event
+	store = newMemStore(event("1", "a"), event("2", "b"), event("3", "c"))
+	store.leases = 2
+	d.store = store
+	got = nil
+	assert.ErrorIs(t, d.Dispatch(context.Background()), errLeaseLost)
+	assert.Equal(t, []string{"1"}, got)
+	assert.Len(t, store.records, 2)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(1, time.Second, 10*time.Second))
+	assert.Equal(t, 2*time.Second, Backoff(2, time.Second, 10*time.Second))
+	assert.Equal(t, 8*time.Second, Backoff(4, time.Second, 10*time.Second))
+	assert.Equal(t, 10*time.Second, Backoff(10, time.Second, 10*time.Second))
+}
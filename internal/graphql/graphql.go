@@ -0,0 +1,89 @@
+// Package graphql serves articles over GraphQL, resolving queries and
+// mutations through domain.NewsService.
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"news_service/internal/domain"
+)
+
+// Request is a GraphQL request as sent over HTTP
+type Request struct {
+	Query         string         `json:"query" form:"query"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Result is the response to a Request
+type Result = gql.Result
+
+// Executor runs requests against the article schema
+type Executor struct {
+	schema gql.Schema
+	limits Limits
+}
+
+// New creates an executor resolving through service and rejecting queries
+// beyond limits
+func New(service domain.NewsService, limits Limits) (*Executor, error) {
+	schema, err := newSchema(service)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, limits: limits}, nil
+}
+
+// Execute parses, validates and runs req. Only queries run when readOnly is
+// set, as for requests that must not change anything.
+func (e *Executor) Execute(ctx context.Context, req Request, readOnly bool) *Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	// Before validation, which takes time exponential in the nesting of
+	// fragments
+	if err := e.limits.check(doc, req.OperationName, req.Variables); err != nil {
+		return &Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	validation := gql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		return &Result{Errors: validation.Errors}
+	}
+	if readOnly && mutates(doc, req.OperationName) {
+		return &Result{Errors: gqlerrors.FormatErrors(errors.New("mutations must be sent with POST"))}
+	}
+
+	return gql.Execute(gql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+}
+
+// mutates reports whether the operation to run is a mutation
+func mutates(doc *ast.Document, operationName string) bool {
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			return op.Operation == ast.OperationTypeMutation
+		}
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// fakeService keeps articles in a map, the methods the schema does not use
+// panic through the nil embedded interface
+type fakeService struct {
+	domain.NewsService
+	news     map[string]*domain.News
+	pages    [][2]int
+	searches []string
+	related  map[string][]*domain.News
+}
+
+func newFakeService(news ...*domain.News) *fakeService {
+	s := &fakeService{news: make(map[string]*domain.News)}
+	for _, n := range news {
+		s.news[n.ID.Hex()] = n
+	}
+	return s
+}
+
+func (s *fakeService) GetNewsByID(ctx context.Context, id string) (*domain.News, error) {
+	if news, ok := s.news[id]; ok {
+		copied := *news
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *fakeService) GetAllNews(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	s.pages = append(s.pages, [2]int{page, limit})
+	var list []*domain.News
+	for _, news := range s.news {
+		list = append(list, news)
+	}
+	return list, int64(len(list)), nil
+}
+
+func (s *fakeService) SearchNews(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	s.searches = append(s.searches, query)
+	return nil, 0, nil
+}
+
+func (s *fakeService) CreateNews(ctx context.Context, news *domain.News) error {
+	news.ID = primitive.NewObjectID()
+	news.ApplyDefaults()
+	s.news[news.ID.Hex()] = news
+	return nil
+}
+
+func (s *fakeService) UpdateNews(ctx context.Context, news *domain.News) error {
+	s.news[news.ID.Hex()] = news
+	return nil
+}
+
+func (s *fakeService) DeleteNews(ctx context.Context, id string) error {
+	delete(s.news, id)
+	return nil
+}
+
+func (s *fakeService) GetTranslations(ctx context.Context, id string) ([]*domain.News, error) {
+	return []*domain.News{s.news[id]}, nil
+}
+
+func (s *fakeService) GetRelatedNews(ctx context.Context, id string, limit int) ([]*domain.News, error) {
+	related := s.related[id]
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+func execute(t *testing.T, e *Executor, query string, variables map[string]any) map[string]any {
+	t.Helper()
+	result := e.Execute(context.Background(), Request{Query: query, Variables: variables}, false)
+	require.Empty(t, result.Errors)
+
+	// Compare data as a client sees it
+	body, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(body, &data))
+	return data
+}
+
+func newExecutor(t *testing.T, service domain.NewsService, limits Limits) *Executor {
+	t.Helper()
+	e, err := New(service, limits)
+	require.NoError(t, err)
+	return e
+}
+
+func TestExecutor_Queries(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	news := &domain.News{ID: primitive.NewObjectID(), Title: "Golang News", Content: "Go 1.22 is out", Language: "en", CreatedAt: created}
+	service := newFakeService(news)
+	e := newExecutor(t, service, Limits{})
+
+	data := execute(t, e, `query($id: ID!) {
+		article(id: $id) { id title slug tags createdAt translations { language } }
+	}`, map[string]any{"id": news.ID.Hex()})
+	assert.Equal(t, map[string]any{
+		"id":           news.ID.Hex(),
+		"title":        "Golang News",
+		"slug":         nil,
+		"tags":         []any{},
+		"createdAt":    "2024-03-01T12:00:00Z",
+		"translations": []any{map[string]any{"language": "en"}},
+	}, data["article"])
+
+	// Missing and malformed IDs give null
+	data = execute(t, e, `{ missing: article(id: "650000000000000000000001") { id } bad: article(id: "x") { id } }`, nil)
+	assert.Nil(t, data["missing"])
+	assert.Nil(t, data["bad"])
+
+	data = execute(t, e, `{ articles(page: 0, limit: 1000) { total page limit pages items { title } } }`, nil)
+	assert.Equal(t, map[string]any{
+		"total": float64(1), "page": float64(1), "limit": float64(maxLimit), "pages": float64(1),
+		"items": []any{map[string]any{"title": "Golang News"}},
+	}, data["articles"])
+	assert.Equal(t, [][2]int{{1, maxLimit}}, service.pages)
+
+	data = execute(t, e, `{ search(query: "golang") { total items { id } } }`, nil)
+	assert.Equal(t, map[string]any{"total": float64(0), "items": []any{}}, data["search"])
+	assert.Equal(t, []string{"golang"}, service.searches)
+
+	other := &domain.News{ID: primitive.NewObjectID(), Title: "Go 1.22 released", Content: "Loop variables", CreatedAt: created}
+	service.related = map[string][]*domain.News{news.ID.Hex(): {other}}
+	data = execute(t, e, `query($id: ID!) {
+		article(id: $id) { related(limit: 1) { title } }
+		none: article(id: $id) { related { related { id } } }
+	}`, map[string]any{"id": news.ID.Hex()})
+	assert.Equal(t, map[string]any{"related": []any{map[string]any{"title": "Go 1.22 released"}}}, data["article"])
+	assert.Equal(t, []any{map[string]any{"related": []any{}}},
+		data["none"].(map[string]any)["related"])
+}
+
+func TestExecutor_Mutations(t *testing.T) {
+	service := newFakeService()
+	e := newExecutor(t, service, Limits{})
+	ctx := context.Background()
+
+	data := execute(t, e, `mutation {
+		createArticle(input: {title: "Created", content: "Created content", tags: ["go"]}) { id status tags }
+	}`, nil)
+	created := data["createArticle"].(map[string]any)
+	assert.Equal(t, domain.StatusPublished, created["status"])
+	assert.Equal(t, []any{"go"}, created["tags"])
+	id := created["id"].(string)
+
+	data = execute(t, e, `mutation($id: ID!) { updateArticle(id: $id, input: {title: "Updated"}) { title content } }`,
+		map[string]any{"id": id})
+	assert.Equal(t, map[string]any{"title": "Updated", "content": "Created content"}, data["updateArticle"])
+
+	// Invalid articles are rejected with the reason
+	result := e.Execute(ctx, Request{Query: `mutation { createArticle(input: {title: "No", content: "Short"}) { id } }`}, false)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, "title: min=3")
+
+	result = e.Execute(ctx, Request{Query: `mutation { deleteArticle(id: "x") }`}, false)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "invalid id", result.Errors[0].Message)
+
+	data = execute(t, e, `mutation($id: ID!) { deleteArticle(id: $id) }`, map[string]any{"id": id})
+	assert.Equal(t, true, data["deleteArticle"])
+	assert.Empty(t, service.news)
+}
+
+func TestExecutor_Rejects(t *testing.T) {
+	e := newExecutor(t, newFakeService(), Limits{MaxDepth: 3, MaxComplexity: 50})
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name, query, err string
+		readOnly         bool
+	}{
+		{name: "syntax", query: `{ articles {`, err: "Syntax Error"},
+		{name: "unknown field", query: `{ articles { views } }`, err: `Cannot query field "views"`},
+		{name: "depth", query: `{ articles { items { translations { id } } } }`, err: "query depth 4 exceeds the limit of 3"},
+		{name: "complexity", query: `{ articles(limit: 20) { items { id title } } }`, err: "query complexity 61 exceeds the limit of 50"},
+		{name: "fragment chain", query: fragmentChain(30), err: "exceeds the limit"},
+		{name: "fragment cycle", query: `{ articles { items { ...a } } } fragment a on Article { ...a }`, err: `cannot spread fragment "a" within itself`},
+		{name: "read only", query: `mutation { deleteArticle(id: "x") }`, err: "mutations must be sent with POST", readOnly: true},
+	} {
+		result := e.Execute(ctx, Request{Query: tc.query}, tc.readOnly)
+		require.NotEmpty(t, result.Errors, tc.name)
+		assert.Contains(t, result.Errors[0].Message, tc.err, tc.name)
+		assert.Nil(t, result.Data, tc.name)
+	}
+}
+
+// fragmentChain returns a query of n fragments that each spread the next
+// twice, expanding to 2^n fields
+func fragmentChain(n int) string {
+	var query strings.Builder
+	query.WriteString(`{ article(id: "1") { ...f0 } }`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&query, " fragment f%d on Article { ...f%d translations { ...f%d } }", i, i+1, i+1)
+	}
+	fmt.Fprintf(&query, " fragment f%d on Article { id }", n)
+	return query.String()
+}
@@ -0,0 +1,180 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Limits bound the work a single query may ask for. Zero disables a limit.
+type Limits struct {
+	// MaxDepth is how deeply fields may be nested
+	MaxDepth int
+	// MaxComplexity is the highest cost of a query. Every field costs one,
+	// and the fields selected under a field with a limit argument count once
+	// per item it may return.
+	MaxComplexity int
+}
+
+// maxCost caps measured costs so that nested limits cannot overflow
+const maxCost = math.MaxInt32
+
+// measure returns the depth and complexity of the operation to run. It stops
+// as soon as either exceeds limits, so the result is then only a lower bound.
+// It fails for a fragment spread within itself, which crashes validation.
+func measure(doc *ast.Document, operationName string, variables map[string]any, limits Limits) (depth, complexity int, err error) {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		return 0, 0, nil
+	}
+
+	m := &measurer{
+		fragments: fragments,
+		variables: variables,
+		limits:    limits,
+		measured:  make(map[string]size),
+		visiting:  make(map[string]bool),
+	}
+	depth, complexity = m.selections(operation.SelectionSet)
+	if m.cycle != "" {
+		return 0, 0, fmt.Errorf("cannot spread fragment %q within itself", m.cycle)
+	}
+	return depth, complexity, nil
+}
+
+type size struct {
+	depth, cost int
+}
+
+type measurer struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	limits    Limits
+	// measured holds the size of every fragment walked so far, so a fragment
+	// spread many times is walked once
+	measured map[string]size
+	visiting map[string]bool
+	// cycle names a fragment found spread within itself
+	cycle string
+}
+
+// exceeds reports whether a size is over the limits, or measuring has to
+// stop at a cycle
+func (m *measurer) exceeds(depth, cost int) bool {
+	return m.cycle != "" || (m.limits.MaxDepth > 0 && depth > m.limits.MaxDepth) ||
+		(m.limits.MaxComplexity > 0 && cost > m.limits.MaxComplexity)
+}
+
+// fragment returns the depth and cost of a named fragment. Unknown fragments
+// count nothing, validation rejects them.
+func (m *measurer) fragment(name string) (depth, cost int) {
+	if s, ok := m.measured[name]; ok {
+		return s.depth, s.cost
+	}
+	if m.visiting[name] {
+		m.cycle = name
+		return 0, maxCost
+	}
+	fragment, ok := m.fragments[name]
+	if !ok {
+		return 0, 0
+	}
+	m.visiting[name] = true
+	depth, cost = m.selections(fragment.SelectionSet)
+	delete(m.visiting, name)
+	m.measured[name] = size{depth, cost}
+	return depth, cost
+}
+
+// selections returns the depth and cost of a selection set
+func (m *measurer) selections(set *ast.SelectionSet) (depth, cost int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			// Introspection is cheap and tools nest it deeply
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			d, c = m.selections(selection.SelectionSet)
+			d, c = d+1, 1+min(c*m.multiplier(selection), maxCost)
+		case *ast.InlineFragment:
+			d, c = m.selections(selection.SelectionSet)
+		case *ast.FragmentSpread:
+			d, c = m.fragment(selection.Name.Value)
+		}
+		depth = max(depth, d)
+		cost = min(cost+c, maxCost)
+		if m.exceeds(depth, cost) {
+			break
+		}
+	}
+	return depth, cost
+}
+
+// multiplier is the number of items a field may return, from its limit
+// argument
+func (m *measurer) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "limit" {
+			continue
+		}
+		limit := defaultLimit
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			limit, _ = strconv.Atoi(value.Value)
+		case *ast.Variable:
+			switch v := m.variables[value.Name.Value].(type) {
+			case float64:
+				limit = int(v)
+			case int:
+				limit = v
+			}
+		}
+		return min(max(limit, 1), maxLimit)
+	}
+	if isPaged(field.Name.Value) {
+		return defaultLimit
+	}
+	if field.Name.Value == "related" {
+		return defaultRelatedLimit
+	}
+	return 1
+}
+
+// isPaged reports whether a query field returns a page of articles
+func isPaged(name string) bool {
+	return name == "articles" || name == "search"
+}
+
+// check returns an error for the first limit the operation exceeds
+func (l Limits) check(doc *ast.Document, operationName string, variables map[string]any) error {
+	depth, complexity, err := measure(doc, operationName, variables, l)
+	if err != nil {
+		return err
+	}
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", depth, l.MaxDepth)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package graphql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasure(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		operation  string
+		variables  map[string]any
+		depth      int
+		complexity int
+	}{
+		{name: "flat", query: `{ article(id: "1") { id title } }`, depth: 2, complexity: 3},
+		{name: "default limit", query: `{ articles { total items { id } } }`, depth: 3, complexity: 1 + 10*(1+2)},
+		{name: "literal limit", query: `{ search(query: "go", limit: 2) { items { id title } } }`, depth: 3, complexity: 1 + 2*3},
+		{
+			name:      "variable limit",
+			query:     `query($n: Int) { articles(limit: $n) { items { id } } }`,
+			variables: map[string]any{"n": float64(5)},
+			depth:     3, complexity: 1 + 5*2,
+		},
+		{
+			name: "fragments",
+			query: `{ article(id: "1") { ...fields ... on Article { translations { id } } } }
+				fragment fields on Article { id title }`,
+			depth: 3, complexity: 1 + 2 + 2,
+		},
+		{name: "related", query: `{ article(id: "1") { related { id } } }`, depth: 3, complexity: 1 + 1 + 5},
+		{
+			name:      "named operation",
+			query:     `query A { article(id: "1") { id } } query B { articles { items { id } } }`,
+			operation: "B",
+			depth:     3, complexity: 1 + 10*2,
+		},
+		{name: "introspection", query: `{ __schema { types { fields { type { ofType { name } } } } } }`},
+	}
+
+	for _, tc := range cases {
+		doc, err := parser.Parse(parser.ParseParams{Source: tc.query})
+		require.NoError(t, err, tc.name)
+		depth, complexity, err := measure(doc, tc.operation, tc.variables, Limits{})
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.depth, depth, tc.name)
+		assert.Equal(t, tc.complexity, complexity, tc.name)
+	}
+}
+
+func TestLimits_FragmentChain(t *testing.T) {
+	doc, err := parser.Parse(parser.ParseParams{Source: fragmentChain(40)})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, complexity, err := measure(doc, "", nil, Limits{})
+	require.NoError(t, err)
+	assert.Equal(t, maxCost, complexity)
+	err = Limits{MaxComplexity: 1000}.check(doc, "", nil)
+	assert.ErrorContains(t, err, "exceeds the limit of 1000")
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestLimits_FragmentCycle(t *testing.T) {
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ article(id: "1") { ...a } }
+		fragment a on Article { id ...b } fragment b on Article { title ...a }`})
+	require.NoError(t, err)
+
+	_, _, err = measure(doc, "", nil, Limits{})
+	assert.EqualError(t, err, `cannot spread fragment "a" within itself`)
+	assert.EqualError(t, Limits{}.check(doc, "", nil), `cannot spread fragment "a" within itself`)
+}
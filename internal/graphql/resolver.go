@@ -0,0 +1,150 @@
+package graphql
+
+import (
+	"errors"
+
+	gql "github.com/graphql-go/graphql"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+type resolver struct {
+	service domain.NewsService
+}
+
+func (r *resolver) article(p gql.ResolveParams) (any, error) {
+	id, _ := p.Args["id"].(string)
+	// Like the HTML pages, a malformed ID is just another missing article
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, nil
+	}
+	news, err := r.service.GetNewsByID(p.Context, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, internal(p, "failed to get news", err)
+	}
+	return news, nil
+}
+
+func (r *resolver) articles(p gql.ResolveParams) (any, error) {
+	page, limit := pagination(p.Args)
+	lang, _ := p.Args["lang"].(string)
+
+	news, total, err := r.service.GetAllNews(p.Context, lang, page, limit)
+	if err != nil {
+		return nil, internal(p, "failed to fetch news", err)
+	}
+	return newPage(news, total, page, limit), nil
+}
+
+func (r *resolver) search(p gql.ResolveParams) (any, error) {
+	page, limit := pagination(p.Args)
+	lang, _ := p.Args["lang"].(string)
+	query, _ := p.Args["query"].(string)
+
+	news, total, err := r.service.SearchNews(p.Context, lang, query, page, limit)
+	if err != nil {
+		return nil, internal(p, "failed to search news", err)
+	}
+	return newPage(news, total, page, limit), nil
+}
+
+func (r *resolver) translations(p gql.ResolveParams) (any, error) {
+	news, ok := p.Source.(*domain.News)
+	if !ok {
+		return nil, nil
+	}
+	translations, err := r.service.GetTranslations(p.Context, news.ID.Hex())
+	if err != nil {
+		return nil, internal(p, "failed to fetch translations", err)
+	}
+	return translations, nil
+}
+
+func (r *resolver) related(p gql.ResolveParams) (any, error) {
+	news, ok := p.Source.(*domain.News)
+	if !ok {
+		return nil, nil
+	}
+	limit, _ := p.Args["limit"].(int)
+	related, err := r.service.GetRelatedNews(p.Context, news.ID.Hex(), min(max(limit, 1), maxRelatedLimit))
+	if err != nil {
+		return nil, internal(p, "failed to fetch related news", err)
+	}
+	if related == nil {
+		related = []*domain.News{}
+	}
+	return related, nil
+}
+
+func (r *resolver) createArticle(p gql.ResolveParams) (any, error) {
+	input, _ := p.Args["input"].(map[string]any)
+	news := &domain.News{}
+	news.Title, _ = input["title"].(string)
+	news.Content, _ = input["content"].(string)
+	news.Slug, _ = input["slug"].(string)
+	news.Status, _ = input["status"].(string)
+	news.Language, _ = input["language"].(string)
+	if tags, ok := input["tags"].([]any); ok {
+		for _, tag := range tags {
+			if tag, ok := tag.(string); ok {
+				news.Tags = append(news.Tags, tag)
+			}
+		}
+	}
+
+	if err := news.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.service.CreateNews(p.Context, news); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, err
+		}
+		return nil, internal(p, "failed to create news", err)
+	}
+	return news, nil
+}
+
+func (r *resolver) updateArticle(p gql.ResolveParams) (any, error) {
+	id, _ := p.Args["id"].(string)
+	input, _ := p.Args["input"].(map[string]any)
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, errors.New("invalid id")
+	}
+
+	news, err := r.service.GetNewsByID(p.Context, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, internal(p, "failed to get news", err)
+	}
+	if title, ok := input["title"].(string); ok {
+		news.Title = title
+	}
+	if content, ok := input["content"].(string); ok {
+		news.Content = content
+	}
+
+	if err := news.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.service.UpdateNews(p.Context, news); err != nil {
+		return nil, internal(p, "failed to update news", err)
+	}
+	return news, nil
+}
+
+func (r *resolver) deleteArticle(p gql.ResolveParams) (any, error) {
+	id, _ := p.Args["id"].(string)
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, errors.New("invalid id")
+	}
+	if err := r.service.DeleteNews(p.Context, id); err != nil {
+		return nil, internal(p, "failed to delete news", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,226 @@
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	gql "github.com/graphql-go/graphql"
+
+	"news_service/internal/domain"
+)
+
+const (
+	defaultLimit = 10
+	// maxLimit caps the page size of lists and searches
+	maxLimit = 100
+
+	defaultRelatedLimit = 5
+	maxRelatedLimit     = 10
+)
+
+// errInternal hides failures of the service from clients, they are logged
+var errInternal = errors.New("internal error")
+
+// newSchema builds the schema, resolving every field through service
+func newSchema(service domain.NewsService) (gql.Schema, error) {
+	r := &resolver{service: service}
+
+	article := gql.NewObject(gql.ObjectConfig{
+		Name:        "Article",
+		Description: "A news article in one language",
+		Fields:      gql.Fields{},
+	})
+	article.AddFieldConfig("id", &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: field(func(n *domain.News) any { return n.ID.Hex() })})
+	article.AddFieldConfig("title", &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: field(func(n *domain.News) any { return n.Title })})
+	article.AddFieldConfig("content", &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: field(func(n *domain.News) any { return n.Content })})
+	article.AddFieldConfig("slug", &gql.Field{Type: gql.String, Resolve: field(func(n *domain.News) any { return optional(n.Slug) })})
+	article.AddFieldConfig("status", &gql.Field{Type: gql.String, Resolve: field(func(n *domain.News) any { return optional(n.Status) })})
+	article.AddFieldConfig("tags", &gql.Field{
+		Type:    gql.NewNonNull(gql.NewList(gql.NewNonNull(gql.String))),
+		Resolve: field(func(n *domain.News) any { return nonNil(n.Tags) }),
+	})
+	article.AddFieldConfig("language", &gql.Field{Type: gql.String, Resolve: field(func(n *domain.News) any { return optional(n.Language) })})
+	article.AddFieldConfig("translationOf", &gql.Field{
+		Type: gql.ID,
+		Resolve: field(func(n *domain.News) any {
+			if n.TranslationOf == nil {
+				return nil
+			}
+			return n.TranslationOf.Hex()
+		}),
+	})
+	article.AddFieldConfig("publishedAt", &gql.Field{Type: gql.DateTime, Resolve: field(func(n *domain.News) any { return n.PublishedAt })})
+	article.AddFieldConfig("createdAt", &gql.Field{Type: gql.NewNonNull(gql.DateTime), Resolve: field(func(n *domain.News) any { return n.CreatedAt })})
+	article.AddFieldConfig("updatedAt", &gql.Field{Type: gql.NewNonNull(gql.DateTime), Resolve: field(func(n *domain.News) any { return n.UpdatedAt })})
+	article.AddFieldConfig("translations", &gql.Field{
+		Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(article))),
+		Description: "Every language variant of the article, itself included",
+		Resolve:     r.translations,
+	})
+	article.AddFieldConfig("related", &gql.Field{
+		Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(article))),
+		Description: "Published articles like this one, best match first",
+		Args: gql.FieldConfigArgument{
+			"limit": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: defaultRelatedLimit, Description: fmt.Sprintf("At most %d", maxRelatedLimit)},
+		},
+		Resolve: r.related,
+	})
+
+	page := gql.NewObject(gql.ObjectConfig{
+		Name: "ArticlePage",
+		Fields: gql.Fields{
+			"items": &gql.Field{Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(article)))},
+			"total": &gql.Field{Type: gql.NewNonNull(gql.Int), Description: "Number of matching articles on all pages"},
+			"page":  &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"limit": &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"pages": &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		},
+	})
+
+	pageArgs := gql.FieldConfigArgument{
+		"page":  &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 1},
+		"limit": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: defaultLimit, Description: fmt.Sprintf("At most %d", maxLimit)},
+		"lang":  &gql.ArgumentConfig{Type: gql.String, Description: "Only articles in this language"},
+	}
+	searchArgs := gql.FieldConfigArgument{
+		"query": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.String)},
+	}
+	for name, arg := range pageArgs {
+		searchArgs[name] = arg
+	}
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"article": &gql.Field{
+				Type:        article,
+				Description: "The article with the given ID, or null when there is none",
+				Args:        gql.FieldConfigArgument{"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}},
+				Resolve:     r.article,
+			},
+			"articles": &gql.Field{
+				Type:        gql.NewNonNull(page),
+				Description: "Articles, newest first",
+				Args:        pageArgs,
+				Resolve:     r.articles,
+			},
+			"search": &gql.Field{
+				Type:        gql.NewNonNull(page),
+				Description: "Articles matching the query, most relevant first",
+				Args:        searchArgs,
+				Resolve:     r.search,
+			},
+		},
+	})
+
+	createInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name: "CreateArticleInput",
+		Fields: gql.InputObjectConfigFieldMap{
+			"title":    &gql.InputObjectFieldConfig{Type: gql.NewNonNull(gql.String)},
+			"content":  &gql.InputObjectFieldConfig{Type: gql.NewNonNull(gql.String)},
+			"slug":     &gql.InputObjectFieldConfig{Type: gql.String},
+			"status":   &gql.InputObjectFieldConfig{Type: gql.String},
+			"tags":     &gql.InputObjectFieldConfig{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"language": &gql.InputObjectFieldConfig{Type: gql.String},
+		},
+	})
+	updateInput := gql.NewInputObject(gql.InputObjectConfig{
+		Name:        "UpdateArticleInput",
+		Description: "Fields left out keep their value",
+		Fields: gql.InputObjectConfigFieldMap{
+			"title":   &gql.InputObjectFieldConfig{Type: gql.String},
+			"content": &gql.InputObjectFieldConfig{Type: gql.String},
+		},
+	})
+
+	mutation := gql.NewObject(gql.ObjectConfig{
+		Name: "Mutation",
+		Fields: gql.Fields{
+			"createArticle": &gql.Field{
+				Type:    gql.NewNonNull(article),
+				Args:    gql.FieldConfigArgument{"input": &gql.ArgumentConfig{Type: gql.NewNonNull(createInput)}},
+				Resolve: r.createArticle,
+			},
+			"updateArticle": &gql.Field{
+				Type: gql.NewNonNull(article),
+				Args: gql.FieldConfigArgument{
+					"id":    &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+					"input": &gql.ArgumentConfig{Type: gql.NewNonNull(updateInput)},
+				},
+				Resolve: r.updateArticle,
+			},
+			"deleteArticle": &gql.Field{
+				Type:        gql.NewNonNull(gql.Boolean),
+				Description: "Deletes the article, deleting a missing one succeeds",
+				Args:        gql.FieldConfigArgument{"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}},
+				Resolve:     r.deleteArticle,
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+// field resolves an Article field with get
+func field(get func(*domain.News) any) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (any, error) {
+		news, ok := p.Source.(*domain.News)
+		if !ok {
+			return nil, fmt.Errorf("unexpected article source %T", p.Source)
+		}
+		return get(news), nil
+	}
+}
+
+// optional turns empty strings into null
+func optional(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// pageResult is an ArticlePage
+type pageResult struct {
+	Items []*domain.News `json:"items"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+	Pages int64          `json:"pages"`
+}
+
+func newPage(items []*domain.News, total int64, page, limit int) *pageResult {
+	if items == nil {
+		items = []*domain.News{}
+	}
+	return &pageResult{
+		Items: items,
+		Total: total,
+		Page:  page,
+		Limit: limit,
+		Pages: (total + int64(limit) - 1) / int64(limit),
+	}
+}
+
+// pagination reads the page and limit arguments, keeping them in range
+func pagination(args map[string]any) (page, limit int) {
+	page, _ = args["page"].(int)
+	limit, _ = args["limit"].(int)
+	page = max(page, 1)
+	limit = min(max(limit, 1), maxLimit)
+	return page, limit
+}
+
+// internal logs err and returns errInternal in its place
+func internal(p gql.ResolveParams, msg string, err error) error {
+	slog.ErrorContext(p.Context, msg, "field", p.Info.FieldName, "error", err)
+	return errInternal
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"news_service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// batchRequest selects the articles of a batch operation. Forms send ids and
+// tags as repeated fields, tags may also be comma separated.
+type batchRequest struct {
+	IDs    []string `json:"ids" form:"ids"`
+	Status string   `json:"status" form:"status"`
+	Tags   []string `json:"tags" form:"tags"`
+}
+
+func (r *batchRequest) tags() []string {
+	var tags []string
+	for _, value := range r.Tags {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+	return tags
+}
+
+func (h *NewsHandler) BatchDelete(c *gin.Context) {
+	var req batchRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.batchResponse(c, "delete", nil, domain.ErrInvalidBatch)
+		return
+	}
+
+	results, err := h.service.DeleteNewsBatch(c.Request.Context(), req.IDs)
+	h.batchResponse(c, "delete", results, err)
+}
+
+func (h *NewsHandler) BatchArchive(c *gin.Context) {
+	h.batchUpdate(c, "archive", func(req *batchRequest) domain.BatchUpdate {
+		return domain.BatchUpdate{Status: domain.StatusArchived}
+	})
+}
+
+func (h *NewsHandler) BatchTag(c *gin.Context) {
+	h.batchUpdate(c, "tag", func(req *batchRequest) domain.BatchUpdate {
+		return domain.BatchUpdate{Tags: req.tags()}
+	})
+}
+
+func (h *NewsHandler) BatchStatus(c *gin.Context) {
+	h.batchUpdate(c, "status", func(req *batchRequest) domain.BatchUpdate {
+		return domain.BatchUpdate{Status: req.Status}
+	})
+}
+
+func (h *NewsHandler) batchUpdate(c *gin.Context, action string, update func(*batchRequest) domain.BatchUpdate) {
+	var req batchRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.batchResponse(c, action, nil, domain.ErrInvalidBatch)
+		return
+	}
+
+	results, err := h.service.UpdateNewsBatch(c.Request.Context(), req.IDs, update(&req))
+	h.batchResponse(c, action, results, err)
+}
+
+// batchResponse renders the per-article results as a fragment for htmx or
+// as JSON for everyone else. htmx clients also get an event to reload the list.
+func (h *NewsHandler) batchResponse(c *gin.Context, action string, results []domain.BatchResult, err error) {
+	htmx := c.GetHeader("HX-Request") == "true"
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to " + action + " news"
+		if errors.Is(err, domain.ErrInvalidBatch) {
+			status = http.StatusBadRequest
+			message = err.Error()
+		} else {
+			slog.ErrorContext(c.Request.Context(), "failed to run batch operation", "action", action, "error", err)
+		}
+
+		if htmx {
+			c.HTML(status, "error.html", gin.H{"error": message})
+		} else {
+			c.JSON(status, gin.H{"error": message})
+		}
+		return
+	}
+
+	succeeded, failed := 0, 0
+	for _, result := range results {
+		if result.OK {
+			succeeded++
+		} else {
+			failed++
+		}
+	}
+	slog.InfoContext(c.Request.Context(), "batch operation", "action", action, "succeeded", succeeded, "failed", failed)
+
+	if htmx {
+		c.Header("HX-Trigger", "newsBatchApplied")
+		c.HTML(http.StatusOK, "news/batch_result.html", gin.H{
+			"Action":    action,
+			"Results":   results,
+			"Succeeded": succeeded,
+			"Failed":    failed,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"action":    action,
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    failed,
+	})
+}
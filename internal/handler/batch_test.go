@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+func TestNewsHandler_BatchDelete(t *testing.T) {
+	mockService := new(MockNewsService)
+	router := setupTestRouter(mockService)
+
+	ids := []string{"1", "2"}
+	mockService.On("DeleteNewsBatch", ids).Return([]domain.BatchResult{
+		{ID: "1", OK: true},
+		{ID: "2", Error: "news not found"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/news/batch/delete", strings.NewReader(`{"ids":["1","2"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Results   []domain.BatchResult `json:"results"`
+		Succeeded int                  `json:"succeeded"`
+		Failed    int                  `json:"failed"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 1, body.Succeeded)
+	assert.Equal(t, 1, body.Failed)
+	assert.Equal(t, "news not found", body.Results[1].Error)
+	mockService.AssertExpectations(t)
+}
+
+func TestNewsHandler_BatchUpdates(t *testing.T) {
+	cases := []struct {
+		path   string
+		form   url.Values
+		update domain.BatchUpdate
+	}{
+		{"/news/batch/archive", url.Values{"ids": {"1"}}, domain.BatchUpdate{Status: domain.StatusArchived}},
+		{"/news/batch/status", url.Values{"ids": {"1"}, "status": {"draft"}}, domain.BatchUpdate{Status: domain.StatusDraft}},
+		{"/news/batch/tag", url.Values{"ids": {"1"}, "tags": {"go,release", "news"}}, domain.BatchUpdate{Tags: []string{"go", "release", "news"}}},
+	}
+
+	for _, tc := range cases {
+		mockService := new(MockNewsService)
+		router := setupTestRouter(mockService)
+		mockService.On("UpdateNewsBatch", []string{"1"}, tc.update).Return([]domain.BatchResult{{ID: "1", OK: true}}, nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", tc.path, strings.NewReader(tc.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, tc.path)
+		mockService.AssertExpectations(t)
+	}
+}
+
+func TestNewsHandler_BatchInvalid(t *testing.T) {
+	mockService := new(MockNewsService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("DeleteNewsBatch", []string(nil)).
+		Return(nil, fmt.Errorf("%w: no articles selected", domain.ErrInvalidBatch))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/news/batch/delete", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "no articles selected")
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CSPReportPath receives the reports of Content Security Policy violations
+const CSPReportPath = "/csp-report"
+
+// maxCSPReportSize bounds report bodies, real ones are a few kilobytes
+const maxCSPReportSize = 64 << 10
+
+// CSPReportHandler logs the Content Security Policy violations browsers
+// report, both in the report-uri and the Reporting API format
+type CSPReportHandler struct {
+	logger *slog.Logger
+}
+
+func NewCSPReportHandler(logger *slog.Logger) *CSPReportHandler {
+	return &CSPReportHandler{
+		logger: logger,
+	}
+}
+
+func (h *CSPReportHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST(CSPReportPath, h.Report)
+}
+
+// cspViolation is the body of a report in either format
+type cspViolation struct {
+	DocumentURL        string `json:"documentURL"`
+	DocumentURI        string `json:"document-uri"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	ViolatedDirective  string `json:"violated-directive"`
+	BlockedURL         string `json:"blockedURL"`
+	BlockedURI         string `json:"blocked-uri"`
+	SourceFile         string `json:"sourceFile"`
+	SourceFileLegacy   string `json:"source-file"`
+	LineNumber         int    `json:"lineNumber"`
+	LineNumberLegacy   int    `json:"line-number"`
+	Disposition        string `json:"disposition"`
+}
+
+// Report logs every violation in the body
+func (h *CSPReportHandler) Report(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCSPReportSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "report too large"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report"})
+		return
+	}
+
+	violations, err := parseCSPReports(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid report"})
+		return
+	}
+
+	for _, v := range violations {
+		h.logger.WarnContext(c.Request.Context(), "CSP violation",
+			"document", first(v.DocumentURL, v.DocumentURI),
+			"directive", first(v.EffectiveDirective, v.ViolatedDirective),
+			"blocked", first(v.BlockedURL, v.BlockedURI),
+			"source", first(v.SourceFile, v.SourceFileLegacy),
+			"line", max(v.LineNumber, v.LineNumberLegacy),
+			"disposition", v.Disposition,
+			"user_agent", c.Request.UserAgent(),
+		)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// parseCSPReports reads a report-uri body, {"csp-report": {...}}, or a
+// Reporting API batch, [{"type": "csp-violation", "body": {...}}, ...]
+func parseCSPReports(body []byte) ([]cspViolation, error) {
+	var batch []struct {
+		Type string       `json:"type"`
+		Body cspViolation `json:"body"`
+	}
+	if err := json.Unmarshal(body, &batch); err == nil {
+		var violations []cspViolation
+		for _, report := range batch {
+			if report.Type == "csp-violation" {
+				violations = append(violations, report.Body)
+			}
+		}
+		return violations, nil
+	}
+
+	var legacy struct {
+		Report *cspViolation `json:"csp-report"`
+	}
+	if err := json.Unmarshal(body, &legacy); err != nil {
+		return nil, err
+	}
+	if legacy.Report == nil {
+		return nil, nil
+	}
+	return []cspViolation{*legacy.Report}, nil
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSPReportHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	router := gin.New()
+	NewCSPReportHandler(slog.New(slog.NewTextHandler(&logs, nil))).RegisterRoutes(router)
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		logged      []string
+	}{
+		{
+			name:        "report-uri",
+			contentType: "application/csp-report",
+			body:        `{"csp-report": {"document-uri": "https://news.example/", "violated-directive": "script-src", "blocked-uri": "inline", "line-number": 12}}`,
+			status:      http.StatusNoContent,
+			logged:      []string{"document=https://news.example/", "directive=script-src", "blocked=inline", "line=12"},
+		},
+		{
+			name:        "reporting API",
+			contentType: "application/reports+json",
+			body: `[{"type": "csp-violation", "body": {"documentURL": "https://news.example/docs", "effectiveDirective": "style-src-elem", "blockedURL": "https://evil.example/x.css", "disposition": "report"}},
+				{"type": "deprecation", "body": {}}]`,
+			status: http.StatusNoContent,
+			logged: []string{"document=https://news.example/docs", "directive=style-src-elem", "blocked=https://evil.example/x.css", "disposition=report"},
+		},
+		{
+			name:        "invalid",
+			contentType: "application/csp-report",
+			body:        "not json",
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "too large",
+			contentType: "application/csp-report",
+			body:        `{"csp-report": {"blocked-uri": "` + strings.Repeat("x", maxCSPReportSize) + `"}}`,
+			status:      http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range cases {
+		logs.Reset()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", CSPReportPath, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, tc.name)
+		assert.Equal(t, len(tc.logged) > 0, strings.Count(logs.String(), "CSP violation") == 1, tc.name)
+		for _, field := range tc.logged {
+			assert.Contains(t, logs.String(), field, tc.name)
+		}
+	}
+}
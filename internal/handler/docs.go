@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsHandler serves the OpenAPI document and a page to browse it
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler serves spec, an OpenAPI document in JSON
+func NewDocsHandler(spec []byte) *DocsHandler {
+	return &DocsHandler{
+		spec: spec,
+	}
+}
+
+func (h *DocsHandler) RegisterRoutes(router *gin.Engine) {
+	router.GET("/openapi.json", h.OpenAPI)
+	router.GET("/docs", h.Docs)
+}
+
+func (h *DocsHandler) OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+// Docs renders Swagger UI for /openapi.json
+func (h *DocsHandler) Docs(c *gin.Context) {
+	c.HTML(http.StatusOK, "docs/index.html", gin.H{
+		"SpecURL": "/openapi.json",
+	})
+}
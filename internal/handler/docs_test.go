@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/api"
+)
+
+func setupDocsRouter(t *testing.T) (*gin.Engine, []byte) {
+	gin.SetMode(gin.TestMode)
+	spec, err := api.OpenAPI()
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("docs/index.html").Parse("{{.SpecURL}}")))
+	NewDocsHandler(spec).RegisterRoutes(router)
+	return router, spec
+}
+
+func TestDocsHandler(t *testing.T) {
+	router, spec := setupDocsRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/openapi.json", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, string(spec), w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/docs", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "/openapi.json", w.Body.String())
+}
+
+// TestOpenAPI_DocumentsEveryRoute keeps the OpenAPI document in step with the
+// routes of every handler, in both directions
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	router, spec := setupDocsRouter(t)
+	NewNewsHandler(nil).RegisterRoutes(router)
+	NewStreamHandler(nil).RegisterRoutes(router)
+	NewTransferHandler(nil, nil, "").RegisterRoutes(router)
+	NewWebhookHandler(nil, "").RegisterRoutes(router)
+	NewHealthHandler(nil).RegisterRoutes(router)
+	NewGraphQLHandler(nil, false).RegisterRoutes(router)
+	NewCSPReportHandler(nil).RegisterRoutes(router)
+	// Registered by the server itself
+	router.GET("/metrics", func(c *gin.Context) {})
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(spec, &doc))
+
+	param := regexp.MustCompile(`:(\w+)`)
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		path := param.ReplaceAllString(route.Path, "{$1}")
+		method := strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+		assert.Contains(t, doc.Paths[path], method, "%s %s is not documented", route.Method, path)
+	}
+
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			assert.True(t, registered[method+" "+path], "%s %s is documented but not registered", strings.ToUpper(method), path)
+		}
+	}
+}
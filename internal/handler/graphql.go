@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"news_service/internal/graphql"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler serves the GraphQL endpoint, and the playground in
+// development
+type GraphQLHandler struct {
+	executor   *graphql.Executor
+	playground bool
+}
+
+func NewGraphQLHandler(executor *graphql.Executor, playground bool) *GraphQLHandler {
+	return &GraphQLHandler{
+		executor:   executor,
+		playground: playground,
+	}
+}
+
+func (h *GraphQLHandler) RegisterRoutes(router *gin.Engine) {
+	router.POST("/graphql", h.Post)
+	router.GET("/graphql", h.Get)
+}
+
+// Post runs a query or mutation sent as JSON
+func (h *GraphQLHandler) Post(c *gin.Context) {
+	var req graphql.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "invalid request body"}}})
+		return
+	}
+	h.execute(c, req, false)
+}
+
+// Get runs a query given in the URL, or opens the playground when a browser
+// asks for the page
+func (h *GraphQLHandler) Get(c *gin.Context) {
+	var req graphql.Request
+	if err := c.ShouldBindQuery(&req); err != nil || req.Query == "" {
+		if h.playground && c.NegotiateFormat(gin.MIMEHTML, gin.MIMEJSON) == gin.MIMEHTML {
+			c.HTML(http.StatusOK, "graphql/playground.html", nil)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "query is required"}}})
+		return
+	}
+	if variables := c.Query("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"errors": []gin.H{{"message": "variables must be a JSON object"}}})
+			return
+		}
+	}
+	h.execute(c, req, true)
+}
+
+// execute answers with 200 whenever the body could be read, with any
+// errors listed in the result, as GraphQL clients expect
+func (h *GraphQLHandler) execute(c *gin.Context, req graphql.Request, readOnly bool) {
+	c.JSON(http.StatusOK, h.executor.Execute(c.Request.Context(), req, readOnly))
+}
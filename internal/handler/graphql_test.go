@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+	"news_service/internal/graphql"
+)
+
+func setupGraphQLRouter(t *testing.T, service domain.NewsService, playground bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	executor, err := graphql.New(service, graphql.Limits{MaxDepth: 5})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("graphql/playground.html").Parse("playground")))
+	NewGraphQLHandler(executor, playground).RegisterRoutes(router)
+	return router
+}
+
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func TestGraphQLHandler_Post(t *testing.T) {
+	mockService := new(MockNewsService)
+	news := &domain.News{ID: primitive.NewObjectID(), Title: "Test News", Content: "Test Content", CreatedAt: time.Now()}
+	mockService.On("GetNewsByID", news.ID.Hex()).Return(news, nil)
+	router := setupGraphQLRouter(t, mockService, false)
+
+	body, _ := json.Marshal(map[string]any{
+		"query":     `query($id: ID!) { article(id: $id) { title } }`,
+		"variables": map[string]any{"id": news.ID.Hex()},
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp graphQLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]any{"title": "Test News"}, resp.Data["article"])
+	mockService.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/graphql", bytes.NewBufferString("{"))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGraphQLHandler_Get(t *testing.T) {
+	mockService := new(MockNewsService)
+	mockService.On("GetAllNews", "", 1, 2).Return([]*domain.News{}, int64(0), nil)
+	router := setupGraphQLRouter(t, mockService, false)
+
+	query := url.Values{
+		"query":     {`query($n: Int) { articles(limit: $n) { total } }`},
+		"variables": {`{"n": 2}`},
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/graphql?"+query.Encode(), nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp graphQLResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, map[string]any{"total": float64(0)}, resp.Data["articles"])
+	mockService.AssertExpectations(t)
+
+	// Mutations are refused over GET
+	query = url.Values{"query": {`mutation { deleteArticle(id: "650000000000000000000001") }`}}
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/graphql?"+query.Encode(), nil)
+	router.ServeHTTP(w, req)
+
+	resp = graphQLResponse{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Errors, 1)
+	assert.Equal(t, "mutations must be sent with POST", resp.Errors[0].Message)
+	mockService.AssertNotCalled(t, "DeleteNews", "650000000000000000000001")
+}
+
+func TestGraphQLHandler_Playground(t *testing.T) {
+	for _, playground := range []bool{true, false} {
+		router := setupGraphQLRouter(t, new(MockNewsService), playground)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/graphql", nil)
+		req.Header.Set("Accept", "text/html")
+		router.ServeHTTP(w, req)
+
+		if playground {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "playground", w.Body.String())
+		} else {
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		}
+	}
+
+	// Clients asking for JSON still need a query
+	router := setupGraphQLRouter(t, new(MockNewsService), true)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/graphql", nil)
+	req.Header.Set("Accept", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
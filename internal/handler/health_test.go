@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/health"
+)
+
+func setupHealthRouter(registry *health.Registry) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewHealthHandler(registry).RegisterRoutes(router)
+	return router
+}
+
+func TestHealthHandler_Liveness(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("mongodb", func(ctx context.Context) error { return errors.New("down") })
+	router := setupHealthRouter(registry)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/healthz", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHealthHandler_Readiness(t *testing.T) {
+	registry := health.NewRegistry(time.Second)
+	registry.Register("mongodb", func(ctx context.Context) error { return nil })
+	router := setupHealthRouter(registry)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	registry.Register("workers", func(ctx context.Context) error { return errors.New("not started") })
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, health.StatusOK, report.Checks["mongodb"].Status)
+	assert.Equal(t, "not started", report.Checks["workers"].Error)
+}
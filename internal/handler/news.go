@@ -1,14 +1,23 @@
 package handler
 
 import (
+	"log/slog"
 	"net/http"
 	"strconv"
 
 	"news_service/internal/domain"
+	"news_service/internal/i18n"
 
 	"github.com/gin-gonic/gin"
 )
 
+// relatedLimit is how many related articles are shown by default, and
+// maxRelatedLimit how many can be asked for
+const (
+	relatedLimit    = 5
+	maxRelatedLimit = 10
+)
+
 type NewsHandler struct {
 	service domain.NewsService
 }
@@ -24,18 +33,27 @@ func (h *NewsHandler) RegisterRoutes(router *gin.Engine) {
 	router.GET("/news/create", h.ShowCreateForm)
 	router.POST("/news", h.CreateNews)
 	router.GET("/news/:id", h.GetNews)
+	router.GET("/news/:id/related", h.RelatedNews)
 	router.GET("/news/:id/edit", h.ShowEditForm)
+	router.GET("/news/:id/translate", h.ShowTranslateForm)
+	router.POST("/news/:id/translations", h.CreateTranslation)
 	router.PUT("/news/:id", h.UpdateNews)
 	router.DELETE("/news/:id", h.DeleteNews)
 	router.GET("/news/search", h.SearchNews)
+	router.POST("/news/batch/delete", h.BatchDelete)
+	router.POST("/news/batch/archive", h.BatchArchive)
+	router.POST("/news/batch/tag", h.BatchTag)
+	router.POST("/news/batch/status", h.BatchStatus)
 }
 
 func (h *NewsHandler) ListNews(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	news, total, err := h.service.GetAllNews(page, limit)
+	lang := i18n.Language(c.Request.Context())
+	news, total, err := h.service.GetAllNews(c.Request.Context(), lang, page, limit)
 	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to fetch news", "error", err)
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": "Failed to fetch news",
 		})
@@ -47,6 +65,7 @@ func (h *NewsHandler) ListNews(c *gin.Context) {
 		"Total": total,
 		"Page":  page,
 		"Limit": limit,
+		"Lang":  lang,
 	})
 }
 
@@ -63,7 +82,8 @@ func (h *NewsHandler) CreateNews(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateNews(&news); err != nil {
+	if err := h.service.CreateNews(c.Request.Context(), &news); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to create news", "error", err)
 		c.HTML(http.StatusInternalServerError, "news/create.html", gin.H{
 			"error": "Failed to create news",
 		})
@@ -75,23 +95,70 @@ func (h *NewsHandler) CreateNews(c *gin.Context) {
 
 func (h *NewsHandler) GetNews(c *gin.Context) {
 	id := c.Param("id")
-	news, err := h.service.GetNewsByID(id)
+	news, err := h.service.GetNewsByID(c.Request.Context(), id)
 	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to get news", "id", id, "error", err)
 		c.HTML(http.StatusNotFound, "error.html", gin.H{
 			"error": "News not found",
 		})
 		return
 	}
 
+	translations, err := h.service.GetTranslations(c.Request.Context(), id)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to get translations", "id", id, "error", err)
+	}
+
+	// A language prefix asks for the variant in that language
+	if lang := i18n.Language(c.Request.Context()); i18n.FromPrefix(c.Request.Context()) && lang != news.Language {
+		for _, variant := range translations {
+			if variant.Language == lang {
+				c.Redirect(http.StatusFound, "/"+lang+"/news/"+variant.ID.Hex())
+				return
+			}
+		}
+	}
+
+	related, err := h.service.GetRelatedNews(c.Request.Context(), id, relatedLimit)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to get related news", "id", id, "error", err)
+	}
+
 	c.HTML(http.StatusOK, "news/view.html", gin.H{
-		"News": news,
+		"News":         news,
+		"Translations": translations,
+		"Alternates":   alternates(news, translations),
+		"Missing":      missingLanguages(translations),
+		"Related":      related,
 	})
 }
 
+// RelatedNews lists the articles most like the given one as JSON
+func (h *NewsHandler) RelatedNews(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(relatedLimit)))
+	if err != nil || limit < 1 || limit > maxRelatedLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(maxRelatedLimit)})
+		return
+	}
+
+	id := c.Param("id")
+	related, err := h.service.GetRelatedNews(c.Request.Context(), id, limit)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to get related news", "id", id, "error", err)
+		c.JSON(http.StatusNotFound, gin.H{"error": "news not found"})
+		return
+	}
+	if related == nil {
+		related = []*domain.News{}
+	}
+	c.JSON(http.StatusOK, gin.H{"related": related})
+}
+
 func (h *NewsHandler) ShowEditForm(c *gin.Context) {
 	id := c.Param("id")
-	news, err := h.service.GetNewsByID(id)
+	news, err := h.service.GetNewsByID(c.Request.Context(), id)
 	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to get news", "id", id, "error", err)
 		c.HTML(http.StatusNotFound, "error.html", gin.H{
 			"error": "News not found",
 		})
@@ -113,18 +180,21 @@ func (h *NewsHandler) UpdateNews(c *gin.Context) {
 		return
 	}
 
-	existingNews, err := h.service.GetNewsByID(id)
+	existingNews, err := h.service.GetNewsByID(c.Request.Context(), id)
 	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to get news", "id", id, "error", err)
 		c.HTML(http.StatusNotFound, "error.html", gin.H{
 			"error": "News not found",
 		})
 		return
 	}
 
-	news.ID = existingNews.ID
-	news.CreatedAt = existingNews.CreatedAt
+	// The form only edits the title and content
+	existingNews.Title = news.Title
+	existingNews.Content = news.Content
 
-	if err := h.service.UpdateNews(&news); err != nil {
+	if err := h.service.UpdateNews(c.Request.Context(), existingNews); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to update news", "id", id, "error", err)
 		c.HTML(http.StatusInternalServerError, "news/edit.html", gin.H{
 			"error": "Failed to update news",
 		})
@@ -136,7 +206,8 @@ func (h *NewsHandler) UpdateNews(c *gin.Context) {
 
 func (h *NewsHandler) DeleteNews(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.service.DeleteNews(id); err != nil {
+	if err := h.service.DeleteNews(c.Request.Context(), id); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to delete news", "id", id, "error", err)
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": "Failed to delete news",
 		})
@@ -151,8 +222,10 @@ func (h *NewsHandler) SearchNews(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	news, total, err := h.service.SearchNews(query, page, limit)
+	lang := i18n.Language(c.Request.Context())
+	news, total, err := h.service.SearchNews(c.Request.Context(), lang, query, page, limit)
 	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to search news", "query", query, "error", err)
 		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
 			"error": "Failed to search news",
 		})
@@ -165,5 +238,6 @@ func (h *NewsHandler) SearchNews(c *gin.Context) {
 		"Page":  page,
 		"Limit": limit,
 		"Query": query,
+		"Lang":  lang,
 	})
 }
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"news_service/internal/domain"
 )
@@ -18,12 +21,12 @@ type MockNewsService struct {
 	mock.Mock
 }
 
-func (m *MockNewsService) CreateNews(news *domain.News) error {
+func (m *MockNewsService) CreateNews(ctx context.Context, news *domain.News) error {
 	args := m.Called(news)
 	return args.Error(0)
 }
 
-func (m *MockNewsService) GetNewsByID(id string) (*domain.News, error) {
+func (m *MockNewsService) GetNewsByID(ctx context.Context, id string) (*domain.News, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,29 +34,80 @@ func (m *MockNewsService) GetNewsByID(id string) (*domain.News, error) {
 	return args.Get(0).(*domain.News), args.Error(1)
 }
 
-func (m *MockNewsService) GetAllNews(page, limit int) ([]*domain.News, int64, error) {
-	args := m.Called(page, limit)
+func (m *MockNewsService) GetAllNews(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	args := m.Called(lang, page, limit)
 	return args.Get(0).([]*domain.News), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockNewsService) UpdateNews(news *domain.News) error {
+func (m *MockNewsService) UpdateNews(ctx context.Context, news *domain.News) error {
 	args := m.Called(news)
 	return args.Error(0)
 }
 
-func (m *MockNewsService) DeleteNews(id string) error {
+func (m *MockNewsService) DeleteNews(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockNewsService) SearchNews(query string, page, limit int) ([]*domain.News, int64, error) {
-	args := m.Called(query, page, limit)
+func (m *MockNewsService) SearchNews(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	args := m.Called(lang, query, page, limit)
 	return args.Get(0).([]*domain.News), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockNewsService) CreateTranslation(ctx context.Context, id string, news *domain.News) error {
+	args := m.Called(id, news)
+	return args.Error(0)
+}
+
+func (m *MockNewsService) GetTranslations(ctx context.Context, id string) ([]*domain.News, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.News), args.Error(1)
+}
+
+func (m *MockNewsService) DeleteNewsBatch(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BatchResult), args.Error(1)
+}
+
+func (m *MockNewsService) UpdateNewsBatch(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	args := m.Called(ids, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BatchResult), args.Error(1)
+}
+
+func (m *MockNewsService) GetRelatedNews(ctx context.Context, id string, limit int) ([]*domain.News, error) {
+	args := m.Called(id, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.News), args.Error(1)
+}
+
+func (m *MockNewsService) ImportNews(ctx context.Context, news *domain.News, upsert bool) (bool, error) {
+	args := m.Called(news, upsert)
+	return args.Bool(0), args.Error(1)
+}
+
+// testPages stands in for the page templates, see web.Templates
+const testPages = `{{define "error.html"}}{{.error}}{{end}}` +
+	`{{define "news/list.html"}}{{range .News}}{{.Title}}{{end}}{{end}}` +
+	`{{define "news/view.html"}}{{.News.Title}}{{end}}` +
+	`{{define "news/create.html"}}{{.error}}{{end}}` +
+	`{{define "news/edit.html"}}{{.error}}{{end}}` +
+	`{{define "news/empty.html"}}{{end}}`
+
 func setupTestRouter(service domain.NewsService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
+	router.SetHTMLTemplate(template.Must(template.New("").Parse(testPages)))
 	handler := NewNewsHandler(service)
 	handler.RegisterRoutes(router)
 	return router
@@ -68,13 +122,14 @@ func TestNewsHandler_ListNews(t *testing.T) {
 		{Title: "News 2", Content: "Content 2"},
 	}
 
-	mockService.On("GetAllNews", 1, 10).Return(expectedNews, int64(2), nil)
+	mockService.On("GetAllNews", "", 1, 10).Return(expectedNews, int64(2), nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/", nil)
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "News 1News 2", w.Body.String())
 	mockService.AssertExpectations(t)
 }
 
@@ -109,12 +164,15 @@ func TestNewsHandler_GetNews(t *testing.T) {
 	}
 
 	mockService.On("GetNewsByID", "test-id").Return(expectedNews, nil)
+	mockService.On("GetTranslations", "test-id").Return([]*domain.News{expectedNews}, nil)
+	mockService.On("GetRelatedNews", "test-id", 5).Return([]*domain.News{}, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/news/test-id", nil)
 	router.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Test News", w.Body.String())
 	mockService.AssertExpectations(t)
 }
 
@@ -122,13 +180,24 @@ func TestNewsHandler_UpdateNews(t *testing.T) {
 	mockService := new(MockNewsService)
 	router := setupTestRouter(mockService)
 
+	existing := &domain.News{
+		ID:       primitive.NewObjectID(),
+		Title:    "Original News",
+		Content:  "Original Content",
+		Status:   domain.StatusPublished,
+		Language: "uk",
+		Tags:     []string{"go"},
+	}
 	news := &domain.News{
 		Title:   "Updated News",
 		Content: "Updated Content",
 	}
 
-	mockService.On("GetNewsByID", "test-id").Return(news, nil)
-	mockService.On("UpdateNews", mock.AnythingOfType("*domain.News")).Return(nil)
+	mockService.On("GetNewsByID", "test-id").Return(existing, nil)
+	mockService.On("UpdateNews", mock.MatchedBy(func(n *domain.News) bool {
+		return n.ID == existing.ID && n.Title == "Updated News" && n.Content == "Updated Content" &&
+			n.Status == domain.StatusPublished && n.Language == "uk" && len(n.Tags) == 1
+	})).Return(nil)
 
 	w := httptest.NewRecorder()
 	body, _ := json.Marshal(news)
@@ -162,7 +231,7 @@ func TestNewsHandler_SearchNews(t *testing.T) {
 		{Title: "Golang News", Content: "Go programming language"},
 	}
 
-	mockService.On("SearchNews", "golang", 1, 10).Return(expectedNews, int64(1), nil)
+	mockService.On("SearchNews", "", "golang", 1, 10).Return(expectedNews, int64(1), nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/news/search?q=golang", nil)
@@ -171,3 +240,33 @@ func TestNewsHandler_SearchNews(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestNewsHandler_RelatedNews(t *testing.T) {
+	mockService := new(MockNewsService)
+	router := setupTestRouter(mockService)
+
+	related := []*domain.News{{ID: primitive.NewObjectID(), Title: "Related News"}}
+	mockService.On("GetRelatedNews", "test-id", 5).Return(related, nil)
+	mockService.On("GetRelatedNews", "test-id", 2).Return(nil, nil)
+	mockService.On("GetRelatedNews", "missing", 5).Return(nil, domain.ErrNotFound)
+
+	cases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/news/test-id/related", http.StatusOK, `"title":"Related News"`},
+		{"/news/test-id/related?limit=2", http.StatusOK, `{"related":[]}`},
+		{"/news/test-id/related?limit=11", http.StatusBadRequest, "limit must be between 1 and 10"},
+		{"/news/missing/related", http.StatusNotFound, "news not found"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tc.path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, tc.path)
+		assert.Contains(t, w.Body.String(), tc.body, tc.path)
+	}
+	mockService.AssertExpectations(t)
+}
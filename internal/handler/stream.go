@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"news_service/internal/domain"
+	"news_service/internal/i18n"
+	"news_service/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// streamHeartbeat keeps idle streams from being closed by proxies
+const streamHeartbeat = 15 * time.Second
+
+// EventSubscriber hands out live article events, see broadcast.Broadcaster
+type EventSubscriber interface {
+	Subscribe() (<-chan domain.Event, func())
+}
+
+// StreamHandler streams article changes to browsers as Server-Sent Events
+// carrying rendered HTML for the htmx SSE extension
+type StreamHandler struct {
+	subscriber EventSubscriber
+	router     *gin.Engine
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+func NewStreamHandler(subscriber EventSubscriber) *StreamHandler {
+	return &StreamHandler{
+		subscriber: subscriber,
+		done:       make(chan struct{}),
+	}
+}
+
+// Close ends all open streams, which would otherwise hold up a graceful
+// shutdown; clients reconnect to another instance
+func (h *StreamHandler) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+}
+
+func (h *StreamHandler) RegisterRoutes(router *gin.Engine) {
+	// The templates are looked up per event so debug mode reloads them
+	h.router = router
+	router.GET("/news/stream", h.Stream)
+}
+
+// Stream sends an "ArticleCreated" event with the card of every new article
+// the list page would show and a "news-<id>" event for every changed one,
+// with its new card or, when deleted, no data, so each card can replace
+// itself. The page passes its lang, page and q parameters: new articles are
+// sent only to the first page of an unsearched list, and only when published
+// in its language.
+func (h *StreamHandler) Stream(c *gin.Context) {
+	filter := listFilter{
+		lang:   c.DefaultQuery("lang", i18n.Language(c.Request.Context())),
+		listed: c.DefaultQuery("page", "1") == "1" && c.Query("q") == "",
+	}
+
+	events, cancel := h.subscriber.Subscribe()
+	defer cancel()
+
+	// The stream outlives the server write timeout
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-h.done:
+			return false
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		case event, ok := <-events:
+			if !ok {
+				// Dropped for falling behind; the client reconnects
+				return false
+			}
+			name, data, err := h.message(event, filter)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to render live update", "event_id", event.ID, "error", err)
+				return true
+			}
+			if name != "" {
+				// Cards skip the CSRF middleware, their delete button needs the token too
+				c.SSEvent(name, string(middleware.InjectCSRF([]byte(data), middleware.CSRFToken(c))))
+			}
+			return true
+		}
+	})
+}
+
+// listFilter matches the new articles a list page shows
+type listFilter struct {
+	lang   string
+	listed bool
+}
+
+func (f listFilter) match(news *domain.News) bool {
+	return f.listed && news.Status == domain.StatusPublished && (f.lang == "" || news.Language == f.lang)
+}
+
+// message renders the SSE event for an article event, or returns an empty
+// name when there is nothing to send
+func (h *StreamHandler) message(event domain.Event, filter listFilter) (string, string, error) {
+	switch event.Type {
+	case domain.EventArticleCreated:
+		if event.Article != nil && !filter.match(event.Article) {
+			return "", "", nil
+		}
+		card, err := h.card(event.Article)
+		return domain.EventArticleCreated, card, err
+	case domain.EventArticleUpdated:
+		card, err := h.card(event.Article)
+		return "news-" + event.ArticleID, card, err
+	case domain.EventArticleDeleted:
+		return "news-" + event.ArticleID, "", nil
+	}
+	return "", "", nil
+}
+
+func (h *StreamHandler) card(news *domain.News) (string, error) {
+	if news == nil {
+		return "", errors.New("event has no article")
+	}
+	instance, ok := h.router.HTMLRender.Instance("news/card.html", news).(render.HTML)
+	if !ok {
+		return "", fmt.Errorf("unexpected HTML renderer %T", h.router.HTMLRender)
+	}
+
+	var buf bytes.Buffer
+	if err := instance.Template.ExecuteTemplate(&buf, instance.Name, instance.Data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
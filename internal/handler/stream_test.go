@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/broadcast"
+	"news_service/internal/domain"
+)
+
+func setupStreamServer(t *testing.T, b *broadcast.Broadcaster) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("news/card.html").Parse(`<div id="news-{{.ID.Hex}}">{{.Title}}</div>`)))
+	NewStreamHandler(b).RegisterRoutes(router)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func openStream(t *testing.T, ctx context.Context, url string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// readEvent reads the next SSE event, skipping comments
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	t.Helper()
+	var name, data string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && name != "":
+			return name, data
+		case strings.HasPrefix(line, "event:"):
+			name = line[len("event:"):]
+		case strings.HasPrefix(line, "data:"):
+			data += line[len("data:"):]
+		}
+	}
+}
+
+func TestStreamHandler_Stream(t *testing.T) {
+	article := &domain.News{ID: primitive.NewObjectID(), Title: "Batch tagged"}
+	b := broadcast.New(8)
+	server := setupStreamServer(t, b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resp := openStream(t, ctx, server.URL+"/news/stream?lang=en")
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Eventually(t, func() bool { return b.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	created := &domain.News{ID: primitive.NewObjectID(), Title: "Fresh", Language: "en", Status: domain.StatusPublished}
+	b.Publish(
+		domain.NewEvent(domain.EventArticleCreated, created.ID.Hex(), created),
+		domain.NewEvent(domain.EventArticleUpdated, article.ID.Hex(), article),
+		domain.NewEvent(domain.EventArticleDeleted, created.ID.Hex(), nil),
+	)
+
+	r := bufio.NewReader(resp.Body)
+	name, data := readEvent(t, r)
+	assert.Equal(t, domain.EventArticleCreated, name)
+	assert.Equal(t, `<div id="news-`+created.ID.Hex()+`">Fresh</div>`, data)
+
+	name, data = readEvent(t, r)
+	assert.Equal(t, "news-"+article.ID.Hex(), name)
+	assert.Contains(t, data, "Batch tagged")
+
+	name, data = readEvent(t, r)
+	assert.Equal(t, "news-"+created.ID.Hex(), name)
+	assert.Empty(t, data)
+
+	// Disconnecting ends the subscription
+	cancel()
+	assert.Eventually(t, func() bool { return b.Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestStreamHandler_FiltersNewArticles(t *testing.T) {
+	b := broadcast.New(8)
+	server := setupStreamServer(t, b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ukrainian := bufio.NewReader(openStream(t, ctx, server.URL+"/news/stream?lang=uk").Body)
+	searched := bufio.NewReader(openStream(t, ctx, server.URL+"/news/stream?lang=uk&q=rocket").Body)
+	secondPage := bufio.NewReader(openStream(t, ctx, server.URL+"/news/stream?lang=uk&page=2").Body)
+	require.Eventually(t, func() bool { return b.Subscribers() == 3 }, time.Second, 10*time.Millisecond)
+
+	article := func(title, lang string, status string) *domain.News {
+		return &domain.News{ID: primitive.NewObjectID(), Title: title, Language: lang, Status: status}
+	}
+	var events []domain.Event
+	for _, news := range []*domain.News{
+		article("Draft", "uk", domain.StatusDraft),
+		article("English", "en", domain.StatusPublished),
+		article("Ukrainian", "uk", domain.StatusPublished),
+	} {
+		events = append(events, domain.NewEvent(domain.EventArticleCreated, news.ID.Hex(), news))
+	}
+	deleted := primitive.NewObjectID().Hex()
+	b.Publish(append(events, domain.NewEvent(domain.EventArticleDeleted, deleted, nil))...)
+
+	name, data := readEvent(t, ukrainian)
+	assert.Equal(t, domain.EventArticleCreated, name)
+	assert.Contains(t, data, "Ukrainian")
+	name, _ = readEvent(t, ukrainian)
+	assert.Equal(t, "news-"+deleted, name)
+
+	for _, r := range []*bufio.Reader{searched, secondPage} {
+		name, _ = readEvent(t, r)
+		assert.Equal(t, "news-"+deleted, name)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"news_service/internal/domain"
+	"news_service/internal/middleware"
+	"news_service/internal/transfer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransferHandler serves bulk import and export of articles to admins.
+// Imports go through the service so they record events, exports read the
+// repository.
+type TransferHandler struct {
+	service    domain.NewsService
+	repo       domain.NewsRepository
+	adminToken string
+}
+
+func NewTransferHandler(service domain.NewsService, repo domain.NewsRepository, adminToken string) *TransferHandler {
+	return &TransferHandler{
+		service:    service,
+		repo:       repo,
+		adminToken: adminToken,
+	}
+}
+
+func (h *TransferHandler) RegisterRoutes(router *gin.Engine) {
+	// Exports include drafts, and imports may overwrite any article
+	admin := middleware.AdminToken(h.adminToken)
+	router.GET("/news/export", admin, h.Export)
+	router.POST("/news/import", admin, h.Import)
+}
+
+// Export streams every article as JSON Lines or CSV, chosen by ?format=
+func (h *TransferHandler) Export(c *gin.Context) {
+	format, err := transfer.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="news.`+string(format)+`"`)
+	c.Status(http.StatusOK)
+
+	count, err := transfer.Export(c.Request.Context(), h.repo, c.Writer, format)
+	if err != nil {
+		// The status is already sent, the client sees a truncated body
+		slog.ErrorContext(c.Request.Context(), "failed to export news", "exported", count, "error", err)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "news exported", "format", format, "count", count)
+}
+
+// Import stores the articles in the request body, either raw or as the "file"
+// field of a multipart form. The format comes from ?format= or the content
+// type, the conflict handling from ?mode=skip|upsert, and ?preserve_ids=true
+// keeps IDs and timestamps. The response is the import report.
+func (h *TransferHandler) Import(c *gin.Context) {
+	opts, err := importOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	body := io.Reader(c.Request.Body)
+	if c.ContentType() == "multipart/form-data" {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing file field"})
+			return
+		}
+		defer file.Close()
+		body = file
+		if c.Query("format") == "" {
+			opts.Format = transfer.FormatFromPath(header.Filename)
+		}
+	}
+
+	report, err := transfer.Import(c.Request.Context(), h.service, body, opts)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "failed to import news", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "news imported",
+		"created", report.Created,
+		"updated", report.Updated,
+		"skipped", report.Skipped,
+		"failed", report.Failed,
+	)
+	c.JSON(http.StatusOK, report)
+}
+
+func importOptions(c *gin.Context) (transfer.ImportOptions, error) {
+	var opts transfer.ImportOptions
+
+	name := c.Query("format")
+	if name == "" && c.ContentType() == "text/csv" {
+		name = "csv"
+	}
+	format, err := transfer.ParseFormat(name)
+	if err != nil {
+		return opts, err
+	}
+
+	mode, err := transfer.ParseMode(c.Query("mode"))
+	if err != nil {
+		return opts, err
+	}
+
+	preserve := false
+	if value := c.Query("preserve_ids"); value != "" {
+		if preserve, err = strconv.ParseBool(value); err != nil {
+			return opts, err
+		}
+	}
+
+	return transfer.ImportOptions{Format: format, Mode: mode, PreserveIDs: preserve}, nil
+}
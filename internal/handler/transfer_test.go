@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+	"news_service/internal/transfer"
+)
+
+// sliceRepository stores inserted articles in a slice
+type sliceRepository struct {
+	domain.NewsRepository
+	news []*domain.News
+}
+
+func (r *sliceRepository) Insert(ctx context.Context, news *domain.News) error {
+	for _, existing := range r.news {
+		if existing.Slug == news.Slug {
+			return domain.ErrConflict
+		}
+	}
+	r.news = append(r.news, news)
+	return nil
+}
+
+func (r *sliceRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	for _, news := range r.news {
+		if err := fn(news); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importService imports into a sliceRepository
+type importService struct {
+	domain.NewsService
+	repo *sliceRepository
+}
+
+func (s importService) ImportNews(ctx context.Context, news *domain.News, upsert bool) (bool, error) {
+	return true, s.repo.Insert(ctx, news)
+}
+
+func setupTransferRouter(repo *sliceRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewTransferHandler(importService{repo: repo}, repo, "admin").RegisterRoutes(router)
+	return router
+}
+
+// transferRequest returns a request carrying the admin token
+func transferRequest(method, url string, body io.Reader) *http.Request {
+	req, _ := http.NewRequest(method, url, body)
+	req.Header.Set("Authorization", "Bearer admin")
+	return req
+}
+
+func TestTransferHandler_RequiresToken(t *testing.T) {
+	repo := &sliceRepository{news: []*domain.News{{Title: "Draft", Content: "Draft content", Status: domain.StatusDraft}}}
+	router := setupTransferRouter(repo)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/news/export", nil),
+		httptest.NewRequest("POST", "/news/import?mode=upsert&preserve_ids=true", strings.NewReader("title,content\nOverwritten,Overwritten content\n")),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, req.URL.Path)
+		assert.NotContains(t, w.Body.String(), "Draft", req.URL.Path)
+	}
+	assert.Len(t, repo.news, 1)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/news/export", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTransferHandler_Import(t *testing.T) {
+	repo := &sliceRepository{}
+	router := setupTransferRouter(repo)
+
+	body := "title,content,slug\nFirst article,First article content,first\nSecond,short,second\nFirst again,First article content,first\n"
+	w := httptest.NewRecorder()
+	req := transferRequest("POST", "/news/import?mode=skip", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report transfer.ImportReport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Len(t, repo.news, 1)
+}
+
+func TestTransferHandler_ImportMultipart(t *testing.T) {
+	repo := &sliceRepository{}
+	router := setupTransferRouter(repo)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "news.jsonl")
+	require.NoError(t, err)
+	part.Write([]byte(`{"title":"Uploaded article","content":"Uploaded article content"}` + "\n"))
+	require.NoError(t, form.Close())
+
+	w := httptest.NewRecorder()
+	req := transferRequest("POST", "/news/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, repo.news, 1)
+	assert.Equal(t, "Uploaded article", repo.news[0].Title)
+}
+
+func TestTransferHandler_ImportBadOptions(t *testing.T) {
+	router := setupTransferRouter(&sliceRepository{})
+
+	for _, query := range []string{"format=xml", "mode=replace", "preserve_ids=maybe"} {
+		w := httptest.NewRecorder()
+		req := transferRequest("POST", "/news/import?"+query, strings.NewReader(""))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestTransferHandler_Export(t *testing.T) {
+	repo := &sliceRepository{news: []*domain.News{{Title: "Exported article", Content: "Exported content"}}}
+	router := setupTransferRouter(repo)
+
+	w := httptest.NewRecorder()
+	req := transferRequest("GET", "/news/export?format=csv", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "news.csv")
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id,title,content"))
+	assert.Contains(t, w.Body.String(), "Exported article")
+}
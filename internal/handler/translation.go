@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"news_service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// translationRequest is a new language variant of an article
+type translationRequest struct {
+	Language string `json:"language" form:"language" binding:"required"`
+	Title    string `json:"title" form:"title" binding:"required"`
+	Content  string `json:"content" form:"content" binding:"required"`
+}
+
+// ShowTranslateForm offers to translate an article into the languages it is
+// not available in yet
+func (h *NewsHandler) ShowTranslateForm(c *gin.Context) {
+	id := c.Param("id")
+	translations, err := h.service.GetTranslations(c.Request.Context(), id)
+	if err != nil || len(translations) == 0 {
+		slog.WarnContext(c.Request.Context(), "failed to get translations", "id", id, "error", err)
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"error": "News not found",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "news/translate.html", gin.H{
+		"ID":      id,
+		"Missing": missingLanguages(translations),
+	})
+}
+
+func (h *NewsHandler) CreateTranslation(c *gin.Context) {
+	id := c.Param("id")
+	var req translationRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.HTML(http.StatusBadRequest, "news/translate.html", gin.H{
+			"ID":    id,
+			"error": "Invalid input",
+		})
+		return
+	}
+
+	news := domain.News{Language: req.Language, Title: req.Title, Content: req.Content}
+	if err := h.service.CreateTranslation(c.Request.Context(), id, &news); err != nil {
+		status, message := http.StatusInternalServerError, "Failed to create translation"
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			status, message = http.StatusNotFound, "News not found"
+		case errors.Is(err, domain.ErrConflict):
+			status, message = http.StatusConflict, "The article is already translated into this language"
+		case errors.Is(err, domain.ErrInvalidTranslation):
+			status, message = http.StatusBadRequest, "Unsupported language"
+		default:
+			slog.ErrorContext(c.Request.Context(), "failed to create translation", "id", id, "error", err)
+		}
+		c.HTML(status, "news/translate.html", gin.H{
+			"ID":    id,
+			"error": message,
+		})
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, "/"+news.Language+"/news/"+news.ID.Hex())
+}
+
+// missingLanguages returns the supported languages an article has no
+// variant in
+func missingLanguages(translations []*domain.News) []string {
+	have := make(map[string]bool, len(translations))
+	for _, variant := range translations {
+		have[variant.Language] = true
+	}
+
+	var missing []string
+	for _, lang := range domain.Languages {
+		if !have[lang] {
+			missing = append(missing, lang)
+		}
+	}
+	return missing
+}
+
+// alternates returns the other language variants of an article, linked from
+// the page head with hreflang
+func alternates(news *domain.News, translations []*domain.News) []*domain.News {
+	var others []*domain.News
+	for _, variant := range translations {
+		if variant.ID != news.ID {
+			others = append(others, variant)
+		}
+	}
+	return others
+}
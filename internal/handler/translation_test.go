@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+	"news_service/internal/i18n"
+)
+
+func TestNewsHandler_CreateTranslation(t *testing.T) {
+	mockService := new(MockNewsService)
+	router := setupTestRouter(mockService)
+
+	translationID := primitive.NewObjectID()
+	mockService.On("CreateTranslation", "1", mock.MatchedBy(func(news *domain.News) bool {
+		return news.Language == "uk" && news.Title == "Заголовок"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.News).ID = translationID
+	}).Return(nil)
+
+	form := url.Values{"language": {"uk"}, "title": {"Заголовок"}, "content": {"Зміст статті"}}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/news/1/translations", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/uk/news/"+translationID.Hex(), w.Header().Get("Location"))
+	mockService.AssertExpectations(t)
+}
+
+func TestNewsHandler_CreateTranslationConflict(t *testing.T) {
+	mockService := new(MockNewsService)
+	router := setupTestRouter(mockService)
+	router.SetHTMLTemplate(template.Must(template.New("news/translate.html").Parse(`{{.error}}`)))
+
+	mockService.On("CreateTranslation", "1", mock.Anything).Return(domain.ErrConflict)
+
+	form := url.Values{"language": {"en"}, "title": {"Title"}, "content": {"Some content"}}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/news/1/translations", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "already translated")
+}
+
+func TestNewsHandler_GetNewsRedirectsToPrefixLanguage(t *testing.T) {
+	mockService := new(MockNewsService)
+	router := setupTestRouter(mockService)
+
+	original := &domain.News{ID: primitive.NewObjectID(), Language: "en"}
+	translation := &domain.News{ID: primitive.NewObjectID(), Language: "uk", TranslationOf: &original.ID}
+	mockService.On("GetNewsByID", original.ID.Hex()).Return(original, nil)
+	mockService.On("GetTranslations", original.ID.Hex()).Return([]*domain.News{original, translation}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/news/"+original.ID.Hex(), nil)
+	req = req.WithContext(i18n.WithLanguage(req.Context(), "uk", true))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "/uk/news/"+translation.ID.Hex(), w.Header().Get("Location"))
+}
+
+func TestMissingLanguages(t *testing.T) {
+	assert.Equal(t, []string{"uk"}, missingLanguages([]*domain.News{{Language: "en"}}))
+	assert.Nil(t, missingLanguages([]*domain.News{{Language: "en"}, {Language: "uk"}}))
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"news_service/internal/middleware"
+	"news_service/internal/webhook"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookService manages webhook endpoints, see webhook.Service
+type WebhookService interface {
+	Create(ctx context.Context, endpoint *webhook.Endpoint) error
+	Get(ctx context.Context, id string) (*webhook.Endpoint, error)
+	List(ctx context.Context) ([]*webhook.Endpoint, error)
+	Update(ctx context.Context, endpoint *webhook.Endpoint) error
+	Delete(ctx context.Context, id string) error
+	Deliveries(ctx context.Context, endpointID string, limit int) ([]*webhook.Delivery, error)
+	Redeliver(ctx context.Context, endpointID, deliveryID string) error
+}
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+// WebhookHandler serves the admin API for webhook endpoints
+type WebhookHandler struct {
+	service    WebhookService
+	adminToken string
+}
+
+func NewWebhookHandler(service WebhookService, adminToken string) *WebhookHandler {
+	return &WebhookHandler{
+		service:    service,
+		adminToken: adminToken,
+	}
+}
+
+func (h *WebhookHandler) RegisterRoutes(router *gin.Engine) {
+	admin := router.Group("/admin/webhooks", middleware.AdminToken(h.adminToken))
+	admin.GET("", h.List)
+	admin.POST("", h.Create)
+	admin.GET("/:id", h.Get)
+	admin.PUT("/:id", h.Update)
+	admin.DELETE("/:id", h.Delete)
+	admin.GET("/:id/deliveries", h.Deliveries)
+	admin.POST("/:id/deliveries/:delivery_id/redeliver", h.Redeliver)
+}
+
+// webhookRequest is the body of create and update requests. Active defaults
+// to true and an empty secret is generated on create and kept on update.
+type webhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret"`
+	Active      *bool    `json:"active"`
+}
+
+func (r *webhookRequest) endpoint(id string) *webhook.Endpoint {
+	active := r.Active == nil || *r.Active
+	return &webhook.Endpoint{
+		ID:          id,
+		URL:         r.URL,
+		Description: r.Description,
+		Events:      r.Events,
+		Secret:      r.Secret,
+		Active:      active,
+	}
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	endpoints, err := h.service.List(c.Request.Context())
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": endpoints})
+}
+
+// Create registers an endpoint. The response is the only one that includes
+// the signing secret.
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint := req.endpoint("")
+	if err := h.service.Create(c.Request.Context(), endpoint); err != nil {
+		h.error(c, err)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "webhook registered", "webhook_id", endpoint.ID, "url", endpoint.URL)
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+func (h *WebhookHandler) Get(c *gin.Context) {
+	endpoint, err := h.service.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+	endpoint.Secret = ""
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var req webhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endpoint := req.endpoint(c.Param("id"))
+	if err := h.service.Update(c.Request.Context(), endpoint); err != nil {
+		h.error(c, err)
+		return
+	}
+	endpoint.Secret = ""
+	c.JSON(http.StatusOK, endpoint)
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.error(c, err)
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "webhook removed", "webhook_id", c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// Deliveries returns the delivery log of an endpoint, newest first, limited
+// by ?limit=
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultDeliveryLimit)))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+	limit = min(limit, maxDeliveryLimit)
+
+	deliveries, err := h.service.Deliveries(c.Request.Context(), c.Param("id"), limit)
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	err := h.service.Redeliver(c.Request.Context(), c.Param("id"), c.Param("delivery_id"))
+	if err != nil {
+		h.error(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"status": webhook.StatusPending})
+}
+
+func (h *WebhookHandler) error(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+	case errors.Is(err, webhook.ErrInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		slog.ErrorContext(c.Request.Context(), "webhook admin request failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
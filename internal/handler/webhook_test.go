@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/webhook"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Create(ctx context.Context, endpoint *webhook.Endpoint) error {
+	args := m.Called(endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) Get(ctx context.Context, id string) (*webhook.Endpoint, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhook.Endpoint), args.Error(1)
+}
+
+func (m *MockWebhookService) List(ctx context.Context) ([]*webhook.Endpoint, error) {
+	args := m.Called()
+	return args.Get(0).([]*webhook.Endpoint), args.Error(1)
+}
+
+func (m *MockWebhookService) Update(ctx context.Context, endpoint *webhook.Endpoint) error {
+	args := m.Called(endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) Deliveries(ctx context.Context, endpointID string, limit int) ([]*webhook.Delivery, error) {
+	args := m.Called(endpointID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhook.Delivery), args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, endpointID, deliveryID string) error {
+	args := m.Called(endpointID, deliveryID)
+	return args.Error(0)
+}
+
+func setupWebhookRouter(service WebhookService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewWebhookHandler(service, "admin").RegisterRoutes(router)
+	return router
+}
+
+func adminRequest(router *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		_ = json.NewEncoder(&buf).Encode(body)
+	}
+	req, _ := http.NewRequest(method, path, &buf)
+	req.Header.Set("Authorization", "Bearer admin")
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandler_RequiresToken(t *testing.T) {
+	router := setupWebhookRouter(new(MockWebhookService))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/webhooks", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestWebhookHandler_Create(t *testing.T) {
+	service := new(MockWebhookService)
+	service.On("Create", mock.MatchedBy(func(e *webhook.Endpoint) bool {
+		return e.URL == "https://example.com/hook" && e.Active && len(e.Events) == 1
+	})).Run(func(args mock.Arguments) {
+		endpoint := args.Get(0).(*webhook.Endpoint)
+		endpoint.ID = "ep1"
+		endpoint.Secret = "whsec_x"
+	}).Return(nil)
+	router := setupWebhookRouter(service)
+
+	w := adminRequest(router, http.MethodPost, "/admin/webhooks", gin.H{
+		"url":    "https://example.com/hook",
+		"events": []string{"ArticleCreated"},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var endpoint webhook.Endpoint
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &endpoint))
+	assert.Equal(t, "ep1", endpoint.ID)
+	assert.Equal(t, "whsec_x", endpoint.Secret)
+	service.AssertExpectations(t)
+}
+
+func TestWebhookHandler_CreateInvalid(t *testing.T) {
+	service := new(MockWebhookService)
+	service.On("Create", mock.Anything).Return(webhook.ErrInvalid)
+	router := setupWebhookRouter(service)
+
+	w := adminRequest(router, http.MethodPost, "/admin/webhooks", gin.H{"url": "ftp://example.com"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/webhooks", gin.H{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookHandler_GetHidesSecret(t *testing.T) {
+	service := new(MockWebhookService)
+	service.On("Get", "ep1").Return(&webhook.Endpoint{ID: "ep1", Secret: "whsec_x"}, nil)
+	service.On("Get", "missing").Return(nil, webhook.ErrNotFound)
+	router := setupWebhookRouter(service)
+
+	w := adminRequest(router, http.MethodGet, "/admin/webhooks/ep1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "whsec_x")
+
+	w = adminRequest(router, http.MethodGet, "/admin/webhooks/missing", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestWebhookHandler_Deliveries(t *testing.T) {
+	service := new(MockWebhookService)
+	service.On("Deliveries", "ep1", maxDeliveryLimit).Return([]*webhook.Delivery{
+		{ID: "d1", Status: webhook.StatusFailed, Attempts: []webhook.Attempt{{ResponseCode: 500}}},
+	}, nil)
+	service.On("Redeliver", "ep1", "d1").Return(nil)
+	router := setupWebhookRouter(service)
+
+	w := adminRequest(router, http.MethodGet, "/admin/webhooks/ep1/deliveries?limit=1000", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"response_code":500`)
+
+	w = adminRequest(router, http.MethodGet, "/admin/webhooks/ep1/deliveries?limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/webhooks/ep1/deliveries/d1/redeliver", nil)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	service.AssertExpectations(t)
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// CheckFunc reports whether a dependency is usable
+type CheckFunc func(ctx context.Context) error
+
+// Result is the outcome of a single check
+type Result struct {
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Latency string `json:"latency"`
+}
+
+// Report is the outcome of all registered checks
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Registry runs the readiness checks of the service
+type Registry struct {
+	mu      sync.RWMutex
+	checks  map[string]CheckFunc
+	timeout time.Duration
+}
+
+// NewRegistry creates a registry whose checks are each bounded by timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		checks:  make(map[string]CheckFunc),
+		timeout: timeout,
+	}
+}
+
+// Register adds a named check, replacing any check with the same name
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = check
+}
+
+// Check runs all checks concurrently and reports ok only if every one passed
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make(map[string]CheckFunc, len(r.checks))
+	for name, check := range r.checks {
+		checks[name] = check
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(ctx)
+			result := Result{Status: StatusOK, Latency: time.Since(start).String()}
+			if err != nil {
+				result.Status = StatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = StatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// Ping checks that the MongoDB primary is reachable
+func Ping(client *mongo.Client) CheckFunc {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, readpref.Primary())
+	}
+}
+
+// Flag is a readiness check that background components set once they are
+// running and clear when they stop
+type Flag struct {
+	ready  atomic.Bool
+	reason atomic.Value
+}
+
+// NewFlag creates a flag that is not ready until Set is called
+func NewFlag(reason string) *Flag {
+	f := &Flag{}
+	f.reason.Store(reason)
+	return f
+}
+
+// Set marks the component as ready
+func (f *Flag) Set() {
+	f.ready.Store(true)
+}
+
+// Clear marks the component as not ready for the given reason
+func (f *Flag) Clear(reason string) {
+	f.reason.Store(reason)
+	f.ready.Store(false)
+}
+
+// Check implements CheckFunc
+func (f *Flag) Check(ctx context.Context) error {
+	if f.ready.Load() {
+		return nil
+	}
+	return errors.New(f.reason.Load().(string))
+}
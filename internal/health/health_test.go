@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	registry := NewRegistry(time.Second)
+	registry.Register("ok", func(ctx context.Context) error { return nil })
+
+	report := registry.Check(context.Background())
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["ok"].Status)
+
+	registry.Register("broken", func(ctx context.Context) error { return errors.New("down") })
+
+	report = registry.Check(context.Background())
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["ok"].Status)
+	assert.Equal(t, StatusUnavailable, report.Checks["broken"].Status)
+	assert.Equal(t, "down", report.Checks["broken"].Error)
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	registry := NewRegistry(10 * time.Millisecond)
+	registry.Register("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := registry.Check(context.Background())
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
+
+func TestFlag(t *testing.T) {
+	flag := NewFlag("not started")
+	assert.EqualError(t, flag.Check(context.Background()), "not started")
+
+	flag.Set()
+	assert.NoError(t, flag.Check(context.Background()))
+
+	flag.Clear("stopped")
+	assert.EqualError(t, flag.Check(context.Background()), "stopped")
+}
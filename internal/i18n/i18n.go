@@ -0,0 +1,120 @@
+// Package i18n selects the language of a request from its URL prefix, a
+// cookie or the Accept-Language header
+package i18n
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+// CookieName remembers the language last chosen through a URL prefix
+const CookieName = "lang"
+
+type contextKey struct{}
+
+type selection struct {
+	lang   string
+	prefix bool
+}
+
+// Language returns the language selected for the request, or "" when the
+// client expressed no preference
+func Language(ctx context.Context) string {
+	sel, _ := ctx.Value(contextKey{}).(selection)
+	return sel.lang
+}
+
+// FromPrefix reports whether the language was given as a URL prefix, the
+// only choice specific enough to switch between variants of one article
+func FromPrefix(ctx context.Context) bool {
+	sel, _ := ctx.Value(contextKey{}).(selection)
+	return sel.prefix
+}
+
+// WithLanguage returns a context selecting lang
+func WithLanguage(ctx context.Context, lang string, prefix bool) context.Context {
+	return context.WithValue(ctx, contextKey{}, selection{lang: lang, prefix: prefix})
+}
+
+// Handler selects the language of each request before routing it. A
+// supported language as the first path segment, as in /uk/news/1, wins and
+// is stripped so the request is routed as /news/1; otherwise the cookie set
+// by the last such request is used, then Accept-Language.
+func Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lang, rest, ok := splitPrefix(r.URL.Path); ok {
+			http.SetCookie(w, &http.Cookie{
+				Name:     CookieName,
+				Value:    lang,
+				Path:     "/",
+				MaxAge:   int((365 * 24 * time.Hour).Seconds()),
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+			r.URL.Path = rest
+			r.URL.RawPath = ""
+			next.ServeHTTP(w, r.WithContext(WithLanguage(r.Context(), lang, true)))
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Language, Cookie")
+		lang := ""
+		if cookie, err := r.Cookie(CookieName); err == nil && domain.SupportedLanguage(cookie.Value) {
+			lang = cookie.Value
+		} else if header := r.Header.Get("Accept-Language"); header != "" {
+			if lang = Negotiate(header); lang == "" {
+				lang = domain.DefaultLanguage
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(WithLanguage(r.Context(), lang, false)))
+	})
+}
+
+// splitPrefix splits "/uk/news/1" into "uk" and "/news/1"
+func splitPrefix(path string) (string, string, bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if !domain.SupportedLanguage(segment) {
+		return "", "", false
+	}
+	return segment, "/" + rest, true
+}
+
+// Negotiate returns the supported language the Accept-Language header
+// prefers most, or "" when it accepts none of them
+func Negotiate(header string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		// Only the primary subtag matters: uk-UA is uk
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if quality > 0 && domain.SupportedLanguage(primary) {
+			candidates = append(candidates, candidate{lang: primary, quality: quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[0].lang
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"uk-UA,uk;q=0.9,en;q=0.8", "uk"},
+		{"en-US,en;q=0.9", "en"},
+		{"de-DE,de;q=0.9,uk;q=0.5,en;q=0.7", "en"},
+		{"UK", "uk"},
+		{"de, fr;q=0.5", ""},
+		{"uk;q=0, en;q=0.1", "en"},
+		{"uk;q=bad", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.header))
+		})
+	}
+}
+
+type seen struct {
+	path   string
+	lang   string
+	prefix bool
+}
+
+func serve(t *testing.T, req *http.Request) (seen, *httptest.ResponseRecorder) {
+	t.Helper()
+	var got seen
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = seen{path: r.URL.Path, lang: Language(r.Context()), prefix: FromPrefix(r.Context())}
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return got, w
+}
+
+func TestHandler_Prefix(t *testing.T) {
+	got, w := serve(t, httptest.NewRequest(http.MethodGet, "/uk/news/1?x=y", nil))
+	assert.Equal(t, seen{path: "/news/1", lang: "uk", prefix: true}, got)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "uk", cookies[0].Value)
+
+	got, _ = serve(t, httptest.NewRequest(http.MethodGet, "/en", nil))
+	assert.Equal(t, seen{path: "/", lang: "en", prefix: true}, got)
+
+	// Only supported languages are prefixes
+	got, _ = serve(t, httptest.NewRequest(http.MethodGet, "/news/1", nil))
+	assert.Equal(t, seen{path: "/news/1"}, got)
+}
+
+func TestHandler_CookieAndHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-GB")
+	req.AddCookie(&http.Cookie{Name: CookieName, Value: "uk"})
+	got, w := serve(t, req)
+	assert.Equal(t, "uk", got.lang)
+	assert.False(t, got.prefix)
+	assert.Contains(t, w.Header().Get("Vary"), "Accept-Language")
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "uk-UA,uk;q=0.9")
+	got, _ = serve(t, req)
+	assert.Equal(t, "uk", got.lang)
+
+	// Browsers asking for other languages get the default one
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "de")
+	got, _ = serve(t, req)
+	assert.Equal(t, "en", got.lang)
+}
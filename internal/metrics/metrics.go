@@ -0,0 +1,133 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+const namespace = "news_service"
+
+// Metrics holds the Prometheus collectors exported by the service
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+
+	RepositoryDuration *prometheus.HistogramVec
+	RepositoryErrors   *prometheus.CounterVec
+
+	PoolConnections    *prometheus.GaugeVec
+	PoolCheckedOut     *prometheus.GaugeVec
+	PoolCheckoutFailed *prometheus.CounterVec
+
+	ArticlesCreated prometheus.Counter
+	ArticlesUpdated prometheus.Counter
+	ArticlesDeleted prometheus.Counter
+}
+
+// New creates the service metrics and registers them, together with the Go
+// runtime and process collectors, on a dedicated registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		RepositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "News repository operation latency by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		RepositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_errors_total",
+			Help:      "Number of failed news repository operations by method.",
+		}, []string{"operation"}),
+		PoolConnections: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mongodb_pool_connections",
+			Help:      "Number of open MongoDB connections by server address.",
+		}, []string{"address"}),
+		PoolCheckedOut: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mongodb_pool_checked_out_connections",
+			Help:      "Number of MongoDB connections currently in use by server address.",
+		}, []string{"address"}),
+		PoolCheckoutFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "mongodb_pool_checkout_failures_total",
+			Help:      "Number of failed MongoDB connection checkouts by server address.",
+		}, []string{"address"}),
+		ArticlesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "articles_created_total",
+			Help:      "Number of news articles created.",
+		}),
+		ArticlesUpdated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "articles_updated_total",
+			Help:      "Number of news articles updated.",
+		}),
+		ArticlesDeleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "articles_deleted_total",
+			Help:      "Number of news articles deleted.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests,
+		m.HTTPDuration,
+		m.RepositoryDuration,
+		m.RepositoryErrors,
+		m.PoolConnections,
+		m.PoolCheckedOut,
+		m.PoolCheckoutFailed,
+		m.ArticlesCreated,
+		m.ArticlesUpdated,
+		m.ArticlesDeleted,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler serving the metrics in Prometheus format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// PoolMonitor returns a MongoDB pool monitor feeding the connection pool metrics
+func (m *Metrics) PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				m.PoolConnections.WithLabelValues(e.Address).Inc()
+			case event.ConnectionClosed:
+				m.PoolConnections.WithLabelValues(e.Address).Dec()
+			case event.GetSucceeded:
+				m.PoolCheckedOut.WithLabelValues(e.Address).Inc()
+			case event.ConnectionReturned:
+				m.PoolCheckedOut.WithLabelValues(e.Address).Dec()
+			case event.GetFailed:
+				m.PoolCheckoutFailed.WithLabelValues(e.Address).Inc()
+			}
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPoolMonitor(t *testing.T) {
+	m := New()
+	monitor := m.PoolMonitor()
+
+	for _, typ := range []string{
+		event.ConnectionCreated,
+		event.ConnectionCreated,
+		event.GetSucceeded,
+		event.GetSucceeded,
+		event.ConnectionReturned,
+		event.ConnectionClosed,
+		event.GetFailed,
+	} {
+		monitor.Event(&event.PoolEvent{Type: typ, Address: "db:27017"})
+	}
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.PoolConnections.WithLabelValues("db:27017")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.PoolCheckedOut.WithLabelValues("db:27017")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.PoolCheckoutFailed.WithLabelValues("db:27017")))
+}
+
+func TestHandler(t *testing.T) {
+	m := New()
+	m.ArticlesCreated.Inc()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/metrics", nil)
+	m.Handler().ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "news_service_articles_created_total 1")
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
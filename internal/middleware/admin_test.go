@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupAdminRouter(token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", AdminToken(token), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestAdminToken(t *testing.T) {
+	router := setupAdminRouter("s3cret")
+
+	w := doRequest(router, http.MethodGet, "/admin", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	w = doRequest(router, http.MethodGet, "/admin", http.Header{"Authorization": {"Bearer wrong"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = doRequest(router, http.MethodGet, "/admin", http.Header{"Authorization": {"Bearer s3cret"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doRequest(router, http.MethodGet, "/admin", http.Header{"X-Api-Token": {"s3cret"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAdminToken_Disabled(t *testing.T) {
+	router := setupAdminRouter("")
+
+	w := doRequest(router, http.MethodGet, "/admin", http.Header{"Authorization": {"Bearer "}})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
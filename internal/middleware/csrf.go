@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"mime"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// CSRFCookie holds the token of a browser session
+	CSRFCookie = "csrf_token"
+	// CSRFField is the form field carrying the token
+	CSRFField = "csrf_token"
+	// CSRFHeader is the header carrying the token, as sent by htmx
+	CSRFHeader = "X-CSRF-Token"
+
+	csrfKey         = "csrf_token"
+	csrfTokenLength = 32
+)
+
+// CSRF protects state-changing requests from cross-site forgery with a
+// token per browser session, kept in a cookie. HTML responses get the token
+// added to every POST form as a hidden field and to every element making an
+// htmx write request as a header. Unsafe requests without a matching token
+// are refused with 403 unless no browser would send them cross-site without
+// a preflight: JSON bodies and requests with an API token.
+func CSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := sessionToken(c)
+		c.Set(csrfKey, token)
+
+		if needsCSRFCheck(c) && !validCSRFToken(c, token) {
+			slog.WarnContext(c.Request.Context(), "CSRF token mismatch", "method", c.Request.Method, "path", c.Request.URL.Path)
+			c.HTML(http.StatusForbidden, "csrf.html", gin.H{
+				"error": "This page has expired. Go back, reload it and try again.",
+			})
+			c.Abort()
+			return
+		}
+
+		rewriteHTML(c, func(html []byte) []byte {
+			return InjectCSRF(html, token)
+		})
+	}
+}
+
+// CSRFToken returns the token of the request's session, or an empty string
+// when CSRF protection is off
+func CSRFToken(c *gin.Context) string {
+	return c.GetString(csrfKey)
+}
+
+// sessionToken returns the token from the session cookie, issuing a new one
+// when there is none
+func sessionToken(c *gin.Context) string {
+	if cookie, err := c.Cookie(CSRFCookie); err == nil && isCSRFToken(cookie) {
+		return cookie
+	}
+
+	b := make([]byte, csrfTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	token := hex.EncodeToString(b)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     CSRFCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	return token
+}
+
+func isCSRFToken(s string) bool {
+	if len(s) != 2*csrfTokenLength {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// needsCSRFCheck reports whether the request changes state and could have
+// been sent by a page on another site
+func needsCSRFCheck(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	if apiToken(c) != "" {
+		return false
+	}
+	// Other sites can only send these without asking first
+	contentType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch contentType {
+	case "", "application/x-www-form-urlencoded", "multipart/form-data", "text/plain":
+		return true
+	}
+	return false
+}
+
+func validCSRFToken(c *gin.Context, token string) bool {
+	sent := c.GetHeader(CSRFHeader)
+	if sent == "" {
+		sent = c.PostForm(CSRFField)
+	}
+	return token != "" && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1
+}
+
+var (
+	formTag     = regexp.MustCompile(`(?i)<form\b[^>]*>`)
+	postMethod  = regexp.MustCompile(`(?i)\bmethod\s*=\s*["']?post\b`)
+	htmxTag     = regexp.MustCompile(`(?i)<[a-z][a-z0-9-]*\b[^>]*\bhx-(?:post|put|patch|delete)\s*=[^>]*>`)
+	htmxHeaders = regexp.MustCompile(`\bhx-headers\s*=\s*'\{`)
+)
+
+// InjectCSRF adds token to the POST forms and htmx write requests in html
+func InjectCSRF(html []byte, token string) []byte {
+	if token == "" {
+		return html
+	}
+	html = formTag.ReplaceAllFunc(html, func(tag []byte) []byte {
+		if !postMethod.Match(tag) {
+			return tag
+		}
+		input := `<input type="hidden" name="` + CSRFField + `" value="` + token + `">`
+		return append(append([]byte{}, tag...), input...)
+	})
+	return htmxTag.ReplaceAllFunc(html, func(tag []byte) []byte {
+		header := `"` + CSRFHeader + `": "` + token + `"`
+		if loc := htmxHeaders.FindIndex(tag); loc != nil {
+			return bytes.Join([][]byte{tag[:loc[1]], []byte(header + ", "), tag[loc[1]:]}, nil)
+		}
+		end := len(tag) - 1
+		if tag[end-1] == '/' {
+			end--
+		}
+		return bytes.Join([][]byte{tag[:end], []byte(` hx-headers='{` + header + `}'`), tag[end:]}, nil)
+	})
+}
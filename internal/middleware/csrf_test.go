@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const csrfPage = `{{define "page.html"}}<form action="/news" method="POST"><input name="title"></form>` +
+	`<form hx-get="/news/search"></form>` +
+	`<button hx-delete="/news/1" hx-headers='{"X-HTTP-Method-Override": "DELETE"}'>Delete</button>` +
+	`<button hx-post="/news/batch/delete">Delete all</button>{{end}}` +
+	`{{define "csrf.html"}}{{.error}}{{end}}`
+
+func setupCSRFRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("").Parse(csrfPage)))
+	router.Use(CSRF())
+	router.GET("/", func(c *gin.Context) { c.HTML(http.StatusOK, "page.html", nil) })
+	router.GET("/token", func(c *gin.Context) { c.String(http.StatusOK, CSRFToken(c)) })
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.POST("/news", ok)
+	router.DELETE("/news/:id", ok)
+	return router
+}
+
+// getPage loads the page, returning it and the session cookie
+func getPage(t *testing.T, router *gin.Engine) (string, *http.Cookie) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, CSRFCookie, cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	return w.Body.String(), cookies[0]
+}
+
+func TestCSRF_InjectsToken(t *testing.T) {
+	router := setupCSRFRouter()
+	body, cookie := getPage(t, router)
+	token := cookie.Value
+
+	assert.Contains(t, body, `<form action="/news" method="POST"><input type="hidden" name="csrf_token" value="`+token+`">`)
+	assert.Contains(t, body, `<form hx-get="/news/search"></form>`, "GET forms are left alone")
+	assert.Contains(t, body, `hx-headers='{"X-CSRF-Token": "`+token+`", "X-HTTP-Method-Override": "DELETE"}'>Delete</button>`)
+	assert.Contains(t, body, `<button hx-post="/news/batch/delete" hx-headers='{"X-CSRF-Token": "`+token+`"}'>`)
+
+	// The session keeps its token
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/token", nil)
+	req.AddCookie(cookie)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, token, w.Body.String())
+	assert.Empty(t, w.Result().Cookies())
+}
+
+func TestCSRF_ChecksWrites(t *testing.T) {
+	router := setupCSRFRouter()
+	_, cookie := getPage(t, router)
+	form := "application/x-www-form-urlencoded"
+
+	cases := []struct {
+		name        string
+		method      string
+		contentType string
+		body        url.Values
+		header      map[string]string
+		cookie      bool
+		status      int
+	}{
+		{name: "form token", method: "POST", contentType: form, body: url.Values{CSRFField: {cookie.Value}}, cookie: true, status: http.StatusNoContent},
+		{name: "header token", method: "DELETE", header: map[string]string{CSRFHeader: cookie.Value}, cookie: true, status: http.StatusNoContent},
+		{name: "missing token", method: "POST", contentType: form, body: url.Values{"title": {"x"}}, cookie: true, status: http.StatusForbidden},
+		{name: "wrong token", method: "DELETE", header: map[string]string{CSRFHeader: strings.Repeat("0", 64)}, cookie: true, status: http.StatusForbidden},
+		{name: "no session", method: "POST", contentType: form, body: url.Values{CSRFField: {cookie.Value}}, status: http.StatusForbidden},
+		{name: "multipart", method: "POST", contentType: "multipart/form-data; boundary=x", cookie: true, status: http.StatusForbidden},
+		{name: "json", method: "POST", contentType: "application/json", cookie: true, status: http.StatusNoContent},
+		{name: "api token", method: "DELETE", header: map[string]string{"Authorization": "Bearer secret"}, status: http.StatusNoContent},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, "/news", strings.NewReader(tc.body.Encode()))
+		if tc.method == "DELETE" {
+			req.URL.Path = "/news/1"
+		}
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		for name, value := range tc.header {
+			req.Header.Set(name, value)
+		}
+		if tc.cookie {
+			req.AddCookie(cookie)
+		}
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, tc.status, w.Code, tc.name)
+		if tc.status == http.StatusForbidden {
+			assert.Contains(t, w.Body.String(), "This page has expired", tc.name)
+		}
+	}
+}
+
+func TestInjectCSRF(t *testing.T) {
+	token := strings.Repeat("ab", 32)
+	html := `<FORM method=post action="/x"><div hx-put="/x"/><form method="get">`
+	injected := string(InjectCSRF([]byte(html), token))
+
+	assert.Equal(t, 1, strings.Count(injected, `name="csrf_token"`))
+	assert.Regexp(t, regexp.MustCompile(`<div hx-put="/x" hx-headers='\{"X-CSRF-Token": "`+token+`"\}'/>`), injected)
+	assert.Equal(t, html, string(InjectCSRF([]byte(html), "")))
+}
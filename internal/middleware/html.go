@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rewriteHTML runs the rest of the chain holding back HTML responses, which
+// are passed through rewrite before being sent
+func rewriteHTML(c *gin.Context, rewrite func([]byte) []byte) {
+	writer := &htmlWriter{ResponseWriter: c.Writer}
+	c.Writer = writer
+	defer func() { c.Writer = writer.ResponseWriter }()
+	c.Next()
+	if writer.buffering {
+		_, _ = writer.ResponseWriter.Write(rewrite(writer.buf.Bytes()))
+	}
+}
+
+// htmlWriter buffers HTML responses, other responses, event streams among
+// them, pass straight through
+type htmlWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	buffering bool
+	decided   bool
+}
+
+func (w *htmlWriter) decide() {
+	if !w.decided {
+		w.decided = true
+		w.buffering = strings.HasPrefix(w.Header().Get("Content-Type"), "text/html")
+	}
+}
+
+func (w *htmlWriter) Write(b []byte) (int, error) {
+	w.decide()
+	if w.buffering {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *htmlWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *htmlWriter) Flush() {
+	w.decide()
+	if !w.buffering {
+		w.ResponseWriter.Flush()
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+
+	var seen string
+	router.GET("/", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	router.ServeHTTP(w, req)
+	assert.NotEmpty(t, seen)
+	assert.Equal(t, seen, w.Header().Get(RequestIDHeader))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "incoming-id")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "incoming-id", seen)
+	assert.Equal(t, "incoming-id", w.Header().Get(RequestIDHeader))
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID(), Logger(logger))
+	router.GET("/news/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/news/42", nil)
+	req.Header.Set(RequestIDHeader, "abc")
+	router.ServeHTTP(w, req)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "/news/42", record["path"])
+	assert.Equal(t, "/news/:id", record["route"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Equal(t, "abc", record["request_id"])
+}
@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limit describes a token bucket: Burst tokens refilled at Rate tokens per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Limits groups the limits applied to each class of request
+type Limits struct {
+	Read   Limit
+	Write  Limit
+	Search Limit
+}
+
+// RateLimitConfig configures the rate limiting middleware
+type RateLimitConfig struct {
+	Enabled bool
+	// IP limits apply to anonymous clients, keyed by client IP
+	IP Limits
+	// Token limits apply to clients presenting one of Tokens. Other tokens
+	// are limited by client IP like anonymous clients.
+	Token  Limits
+	Tokens []string
+	// SearchPath is the route treated as a search request
+	SearchPath string
+}
+
+// DefaultRateLimitConfig returns the limits used when nothing is configured
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Enabled: true,
+		IP: Limits{
+			Read:   Limit{Rate: 10, Burst: 20},
+			Write:  Limit{Rate: 1, Burst: 5},
+			Search: Limit{Rate: 1, Burst: 5},
+		},
+		Token: Limits{
+			Read:   Limit{Rate: 50, Burst: 100},
+			Write:  Limit{Rate: 10, Burst: 20},
+			Search: Limit{Rate: 5, Burst: 10},
+		},
+		SearchPath: "/news/search",
+	}
+}
+
+// ParseLimit parses a limit in the form "<count>/<unit>", e.g. "60/m".
+// The burst equals the count, the unit is one of s, m or h.
+func ParseLimit(s string) (Limit, error) {
+	count, unit, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q", s)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit count %q", count)
+	}
+
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid rate limit unit %q", unit)
+	}
+
+	return Limit{Rate: float64(n) / per.Seconds(), Burst: n}, nil
+}
+
+// RateLimitStore keeps token bucket state. Implementations backed by a shared
+// store (e.g. Redis) allow limits to be enforced across instances.
+type RateLimitStore interface {
+	// Take consumes a token from the bucket identified by key. When no token is
+	// available it returns false and the time until the next one is.
+	Take(key string, limit Limit) (bool, time.Duration, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	ttl     time.Duration
+	swept   time.Time
+}
+
+// NewMemoryStore creates an in-process rate limit store
+func NewMemoryStore() RateLimitStore {
+	return &memoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		ttl:     10 * time.Minute,
+	}
+}
+
+func (s *memoryStore) Take(key string, limit Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	if limit.Rate <= 0 {
+		return false, time.Hour, nil
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < s.ttl {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.last) > s.ttl {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
+
+// RateLimit returns a middleware enforcing cfg using store
+func RateLimit(cfg RateLimitConfig, store RateLimitStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		class, limit := classify(c, cfg)
+		key := "ip:" + c.ClientIP()
+		if token := apiToken(c); token != "" && cfg.knownToken(token) {
+			key = "token:" + token
+			limit = cfg.Token.forClass(class)
+		}
+
+		allowed, wait, err := store.Take(key+":"+class, limit)
+		if err != nil {
+			// Fail open: a broken store must not take the site down
+			c.Next()
+			return
+		}
+
+		if !allowed {
+			seconds := int(math.Ceil(wait.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.HTML(http.StatusTooManyRequests, "error.html", gin.H{
+				"error": "Too many requests, please try again later",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// knownToken reports whether token is one of the configured API tokens
+func (cfg RateLimitConfig) knownToken(token string) bool {
+	for _, t := range cfg.Tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func classify(c *gin.Context, cfg RateLimitConfig) (string, Limit) {
+	switch {
+	case c.Request.URL.Path == cfg.SearchPath:
+		return "search", cfg.IP.Search
+	case c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions:
+		return "read", cfg.IP.Read
+	default:
+		return "write", cfg.IP.Write
+	}
+}
+
+func (l Limits) forClass(class string) Limit {
+	switch class {
+	case "search":
+		return l.Search
+	case "write":
+		return l.Write
+	default:
+		return l.Read
+	}
+}
+
+// apiToken extracts the API token from the Authorization bearer or X-API-Token header
+func apiToken(c *gin.Context) string {
+	if token := c.GetHeader("X-API-Token"); token != "" {
+		return token
+	}
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return ""
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRateLimitRouter(cfg RateLimitConfig, trustedProxies ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		panic(err)
+	}
+	router.SetHTMLTemplate(template.Must(template.New("error.html").Parse("{{.error}}")))
+	router.Use(RateLimit(cfg, NewMemoryStore()))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/", ok)
+	router.GET("/news/search", ok)
+	router.POST("/news", ok)
+	return router
+}
+
+func testRateLimitConfig() RateLimitConfig {
+	cfg := DefaultRateLimitConfig()
+	cfg.IP = Limits{
+		Read:   Limit{Rate: 1, Burst: 2},
+		Write:  Limit{Rate: 1, Burst: 1},
+		Search: Limit{Rate: 1, Burst: 1},
+	}
+	cfg.Token = Limits{
+		Read:   Limit{Rate: 1, Burst: 3},
+		Write:  Limit{Rate: 1, Burst: 1},
+		Search: Limit{Rate: 1, Burst: 1},
+	}
+	cfg.Tokens = []string{"secret"}
+	return cfg
+}
+
+func doRequest(router *gin.Engine, method, path string, header http.Header) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit_RejectsWithRetryAfter(t *testing.T) {
+	router := setupRateLimitRouter(testRateLimitConfig())
+
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", nil).Code)
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", nil).Code)
+
+	w := doRequest(router, "GET", "/", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimit_SeparateClasses(t *testing.T) {
+	router := setupRateLimitRouter(testRateLimitConfig())
+
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/news/search?q=go", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "GET", "/news/search?q=go", nil).Code)
+
+	// Exhausting search must not affect reads or writes
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", nil).Code)
+	assert.Equal(t, http.StatusOK, doRequest(router, "POST", "/news", nil).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "POST", "/news", nil).Code)
+}
+
+func TestRateLimit_TokenLimits(t *testing.T) {
+	router := setupRateLimitRouter(testRateLimitConfig())
+	header := http.Header{"Authorization": {"Bearer secret"}}
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", header).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "GET", "/", header).Code)
+
+	// Anonymous clients from the same IP have their own bucket
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", nil).Code)
+}
+
+func TestRateLimit_UnknownTokens(t *testing.T) {
+	router := setupRateLimitRouter(testRateLimitConfig())
+
+	// Made up tokens share the bucket of the client IP
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", http.Header{"X-Api-Token": {"a"}}).Code)
+	assert.Equal(t, http.StatusOK, doRequest(router, "GET", "/", http.Header{"Authorization": {"Bearer b"}}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "GET", "/", http.Header{"X-Api-Token": {"c"}}).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "GET", "/", nil).Code)
+}
+
+func TestRateLimit_ForwardedFor(t *testing.T) {
+	router := setupRateLimitRouter(testRateLimitConfig())
+
+	// Without trusted proxies a forged header does not change the client IP
+	for i, ip := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		w := doRequest(router, "GET", "/news/search", http.Header{"X-Forwarded-For": {ip}})
+		if i == 0 {
+			assert.Equal(t, http.StatusOK, w.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		}
+	}
+
+	// Behind a trusted proxy every forwarded client has its own bucket
+	router = setupRateLimitRouter(testRateLimitConfig(), "192.0.2.1")
+	for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+		w := doRequest(router, "GET", "/news/search", http.Header{"X-Forwarded-For": {ip}})
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	cfg := testRateLimitConfig()
+	cfg.Enabled = false
+	router := setupRateLimitRouter(cfg)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, doRequest(router, "POST", "/news", nil).Code)
+	}
+}
+
+func TestMemoryStore_Refill(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryStore().(*memoryStore)
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 1}
+
+	allowed, _, err := store.Take("k", limit)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, wait, err := store.Take("k", limit)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _, err = store.Take("k", limit)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("60/m")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 1, Burst: 60}, limit)
+
+	limit, err = ParseLimit("5/s")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 5, Burst: 5}, limit)
+
+	for _, invalid := range []string{"", "10", "x/s", "0/s", "10/d"} {
+		_, err := ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
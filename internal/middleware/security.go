@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NonceTemplate stands for the CSP nonce in policies
+const NonceTemplate = "{nonce}"
+
+// SecurityConfig configures the security headers of every response
+type SecurityConfig struct {
+	// CSP is the Content-Security-Policy, NonceTemplate in it is replaced by
+	// the nonce of the response. Empty sends no policy.
+	CSP string
+	// CSPReportOnly reports violations of the policy without enforcing it
+	CSPReportOnly bool
+	// CSPReportURI receives violation reports, none are sent when empty
+	CSPReportURI string
+	// FrameAncestors lists who may embed the pages, enforced even when the
+	// rest of the policy is report only. Empty allows anyone.
+	FrameAncestors string
+	// HSTSMaxAge is how long browsers should only use HTTPS, zero sends no
+	// Strict-Transport-Security
+	HSTSMaxAge        time.Duration
+	ReferrerPolicy    string
+	PermissionsPolicy string
+}
+
+// DefaultSecurityConfig returns a policy allowing scripts and styles from
+// this origin only, with a nonce
+func DefaultSecurityConfig() SecurityConfig {
+	return SecurityConfig{
+		CSP: "default-src 'self'; " +
+			"script-src 'self' 'nonce-{nonce}'; " +
+			"style-src 'self' 'nonce-{nonce}'; " +
+			"img-src 'self' data:; connect-src 'self'; object-src 'none'; " +
+			"base-uri 'self'; form-action 'self'",
+		FrameAncestors:    "'none'",
+		HSTSMaxAge:        365 * 24 * time.Hour,
+		ReferrerPolicy:    "strict-origin-when-cross-origin",
+		PermissionsPolicy: "camera=(), microphone=(), geolocation=(), payment=(), usb=()",
+	}
+}
+
+// noncePlaceholder is what templates write for the nonce, see
+// NoncePlaceholder. It is random so pages cannot be made to carry it through
+// user content.
+var noncePlaceholder = func() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("generate CSP nonce placeholder: " + err.Error())
+	}
+	return "csp-nonce-" + hex.EncodeToString(b)
+}()
+
+// NoncePlaceholder stands for the CSP nonce in templates, SecurityHeaders
+// replaces it with the nonce of the response in HTML
+func NoncePlaceholder() string {
+	return noncePlaceholder
+}
+
+// SecurityHeaders sets the security headers of every response and a fresh
+// CSP nonce for each HTML page
+func SecurityHeaders(cfg SecurityConfig) gin.HandlerFunc {
+	policy := cfg.CSP
+	if policy != "" && cfg.CSPReportURI != "" {
+		policy += "; report-uri " + cfg.CSPReportURI + "; report-to csp"
+	}
+	// Browsers ignore frame-ancestors in report only policies
+	var frameAncestors string
+	if cfg.FrameAncestors != "" {
+		frameAncestors = "frame-ancestors " + cfg.FrameAncestors
+		if policy != "" && !cfg.CSPReportOnly {
+			policy += "; " + frameAncestors
+			frameAncestors = ""
+		}
+	}
+	policyHeader := "Content-Security-Policy"
+	if cfg.CSPReportOnly {
+		policyHeader = "Content-Security-Policy-Report-Only"
+	}
+
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge/time.Second), 10) + "; includeSubDomains"
+	}
+
+	return func(c *gin.Context) {
+		nonce := newNonce()
+
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		if policy != "" {
+			header.Set(policyHeader, strings.ReplaceAll(policy, NonceTemplate, nonce))
+			if cfg.CSPReportURI != "" {
+				header.Set("Reporting-Endpoints", `csp="`+cfg.CSPReportURI+`"`)
+			}
+		}
+		if frameAncestors != "" {
+			header.Set("Content-Security-Policy", frameAncestors)
+		}
+		switch cfg.FrameAncestors {
+		case "'none'":
+			header.Set("X-Frame-Options", "DENY")
+		case "'self'":
+			header.Set("X-Frame-Options", "SAMEORIGIN")
+		}
+		if hsts != "" {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if cfg.PermissionsPolicy != "" {
+			header.Set("Permissions-Policy", cfg.PermissionsPolicy)
+		}
+
+		rewriteHTML(c, func(html []byte) []byte {
+			return bytes.ReplaceAll(html, []byte(noncePlaceholder), []byte(nonce))
+		})
+	}
+}
+
+func newNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// Without a nonce the policy blocks inline code, which fails safe
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSecurityRouter(cfg SecurityConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("page.html").Funcs(template.FuncMap{"cspNonce": NoncePlaceholder}).
+		Parse(`<script nonce="{{cspNonce}}">go()</script><p>{{.}}</p>`)))
+	router.Use(SecurityHeaders(cfg))
+	router.GET("/", func(c *gin.Context) { c.HTML(http.StatusOK, "page.html", c.Query("q")) })
+	router.GET("/api", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"nonce": NoncePlaceholder()}) })
+	return router
+}
+
+func get(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	cfg := DefaultSecurityConfig()
+	cfg.CSPReportURI = "/csp-report"
+	router := setupSecurityRouter(cfg)
+
+	w := get(router, "/")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	policy := w.Header().Get("Content-Security-Policy")
+	match := regexp.MustCompile(`'nonce-([A-Za-z0-9+/=]+)'`).FindStringSubmatch(policy)
+	require.NotNil(t, match, policy)
+	nonce := match[1]
+	assert.Contains(t, w.Body.String(), `<script nonce="`+nonce+`">`)
+	assert.NotContains(t, w.Body.String(), NoncePlaceholder())
+	assert.Contains(t, policy, "frame-ancestors 'none'")
+	assert.Contains(t, policy, "report-uri /csp-report; report-to csp")
+	assert.Equal(t, `csp="/csp-report"`, w.Header().Get("Reporting-Endpoints"))
+
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "strict-origin-when-cross-origin", w.Header().Get("Referrer-Policy"))
+	assert.Contains(t, w.Header().Get("Permissions-Policy"), "camera=()")
+
+	// Every response gets its own nonce
+	other := get(router, "/").Header().Get("Content-Security-Policy")
+	assert.NotEqual(t, policy, other)
+
+	// Only HTML is rewritten
+	w = get(router, "/api")
+	assert.Contains(t, w.Body.String(), NoncePlaceholder())
+	assert.NotEmpty(t, w.Header().Get("Content-Security-Policy"))
+}
+
+func TestSecurityHeaders_ReportOnly(t *testing.T) {
+	cfg := DefaultSecurityConfig()
+	cfg.CSPReportOnly = true
+	cfg.FrameAncestors = "'self'"
+	cfg.HSTSMaxAge = time.Hour
+	router := setupSecurityRouter(cfg)
+
+	w := get(router, "/")
+	reportOnly := w.Header().Get("Content-Security-Policy-Report-Only")
+	assert.Contains(t, reportOnly, "script-src 'self' 'nonce-")
+	assert.NotContains(t, reportOnly, "frame-ancestors")
+	assert.NotContains(t, reportOnly, "report-uri")
+	assert.Equal(t, "frame-ancestors 'self'", w.Header().Get("Content-Security-Policy"), "framing stays enforced")
+	assert.Equal(t, "SAMEORIGIN", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "max-age=3600; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestSecurityHeaders_Disabled(t *testing.T) {
+	router := setupSecurityRouter(SecurityConfig{})
+
+	w := get(router, "/")
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	for _, header := range []string{"Content-Security-Policy", "X-Frame-Options", "Strict-Transport-Security", "Referrer-Policy", "Permissions-Policy"} {
+		assert.Empty(t, w.Header().Get(header), header)
+	}
+	assert.NotContains(t, w.Body.String(), NoncePlaceholder())
+}
@@ -0,0 +1,349 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationsCollection = "schema_migrations"
+	lockCollection       = "schema_migrations_lock"
+	lockID               = "lock"
+)
+
+// ErrLocked is returned when another process holds the migration lock
+var ErrLocked = errors.New("migrations are locked by another process")
+
+// ErrLockLost is returned when the migration lock expired during a run and
+// another process may have taken it
+var ErrLockLost = errors.New("migration lock lost")
+
+// Func changes the database schema or data
+type Func func(ctx context.Context, db *mongo.Database) error
+
+// Migration is a versioned schema change defined in Go
+type Migration struct {
+	Version int64
+	Name    string
+	Up      Func
+	// Down reverts Up. Migrations without Down cannot be rolled back.
+	Down Func
+}
+
+// Record is a migration applied to the database
+type Record struct {
+	Version   int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+	Duration  int64     `bson:"duration_ms"`
+}
+
+// Status describes a known migration and whether it has been applied
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Options controls a migration run
+type Options struct {
+	// DryRun reports what would run without changing anything
+	DryRun bool
+	// Target is the version to migrate up to, or down to (exclusive).
+	// Zero means all pending migrations for Up and one step for Down; a
+	// negative Target makes Down revert every applied migration.
+	Target int64
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+	logger     *slog.Logger
+	lockTTL    time.Duration
+	owner      string
+}
+
+// New creates a migrator for the given migrations, which must have unique,
+// positive versions
+func New(db *mongo.Database, migrations []Migration, logger *slog.Logger) (*Migrator, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("migration %q: version must be positive", m.Name)
+		}
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d: Up is required", m.Version)
+		}
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("duplicate migration version %d", m.Version)
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		logger:     logger.With("component", "migrate"),
+		lockTTL:    time.Minute,
+		owner:      hostname + ":" + strconv.Itoa(os.Getpid()),
+	}, nil
+}
+
+// Status lists every known migration with its applied state
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		statuses = append(statuses, Status{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: record.AppliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+// Up applies pending migrations in version order and returns those applied,
+// or in dry-run mode those that would be
+func (m *Migrator) Up(ctx context.Context, opts Options) ([]Migration, error) {
+	return m.withLock(ctx, opts.DryRun, func(ctx context.Context) ([]Migration, error) {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var pending []Migration
+		for _, migration := range m.migrations {
+			if opts.Target > 0 && migration.Version > opts.Target {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				pending = append(pending, migration)
+			}
+		}
+
+		if opts.DryRun {
+			return pending, nil
+		}
+
+		var done []Migration
+		for _, migration := range pending {
+			start := time.Now()
+			if err := migration.Up(ctx, m.db); err != nil {
+				return done, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+
+			record := Record{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now(),
+				Duration:  time.Since(start).Milliseconds(),
+			}
+			if err := m.extend(ctx); err != nil {
+				return done, fmt.Errorf("record migration %d: %w", migration.Version, err)
+			}
+			if _, err := m.db.Collection(migrationsCollection).InsertOne(ctx, record); err != nil {
+				return done, fmt.Errorf("record migration %d: %w", migration.Version, err)
+			}
+
+			m.logger.InfoContext(ctx, "migration applied", "version", migration.Version, "name", migration.Name, "latency", time.Since(start))
+			done = append(done, migration)
+		}
+		return done, nil
+	})
+}
+
+// Down reverts applied migrations in reverse version order, down to but not
+// including opts.Target, only the latest one when Target is zero, or all of
+// them when Target is negative
+func (m *Migrator) Down(ctx context.Context, opts Options) ([]Migration, error) {
+	return m.withLock(ctx, opts.DryRun, func(ctx context.Context) ([]Migration, error) {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var revert []Migration
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if opts.Target > 0 && migration.Version <= opts.Target {
+				break
+			}
+			if migration.Down == nil {
+				return nil, fmt.Errorf("migration %d %s cannot be reverted", migration.Version, migration.Name)
+			}
+			revert = append(revert, migration)
+			if opts.Target == 0 {
+				break
+			}
+		}
+
+		if opts.DryRun {
+			return revert, nil
+		}
+
+		var done []Migration
+		for _, migration := range revert {
+			start := time.Now()
+			if err := migration.Down(ctx, m.db); err != nil {
+				return done, fmt.Errorf("revert migration %d %s: %w", migration.Version, migration.Name, err)
+			}
+			if err := m.extend(ctx); err != nil {
+				return done, fmt.Errorf("unrecord migration %d: %w", migration.Version, err)
+			}
+			if _, err := m.db.Collection(migrationsCollection).DeleteOne(ctx, bson.M{"_id": migration.Version}); err != nil {
+				return done, fmt.Errorf("unrecord migration %d: %w", migration.Version, err)
+			}
+
+			m.logger.InfoContext(ctx, "migration reverted", "version", migration.Version, "name", migration.Name, "latency", time.Since(start))
+			done = append(done, migration)
+		}
+		return done, nil
+	})
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]Record, error) {
+	cursor, err := m.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	applied := make(map[int64]Record, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// withLock runs fn while holding the migration lock, extending it until fn
+// returns. The context of fn is cancelled when the lock is lost. Dry runs
+// only read and do not take the lock.
+func (m *Migrator) withLock(ctx context.Context, dryRun bool, fn func(ctx context.Context) ([]Migration, error)) ([]Migration, error) {
+	if dryRun {
+		return fn(ctx)
+	}
+
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Release even when ctx was cancelled midway
+		if err := m.unlock(context.Background()); err != nil {
+			m.logger.Error("failed to release migration lock", "error", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.heartbeat(ctx, cancel)
+	}()
+	defer wg.Wait()
+	defer cancel(nil)
+
+	done, err := fn(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), ErrLockLost) {
+		err = fmt.Errorf("%w: %v", ErrLockLost, err)
+	}
+	return done, err
+}
+
+// heartbeat extends the lock until ctx is done, and cancels it when the lock
+// turns out to be lost
+func (m *Migrator) heartbeat(ctx context.Context, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(m.lockTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := m.extend(ctx)
+		switch {
+		case errors.Is(err, ErrLockLost):
+			m.logger.ErrorContext(ctx, "migration lock lost, stopping")
+			cancel(ErrLockLost)
+			return
+		case err != nil && ctx.Err() == nil:
+			// Retried on the next tick, before the lock expires
+			m.logger.WarnContext(ctx, "failed to extend migration lock", "error", err)
+		}
+	}
+}
+
+// extend pushes back the expiry of the lock, failing with ErrLockLost when
+// this process no longer holds it
+func (m *Migrator) extend(ctx context.Context) error {
+	result, err := m.db.Collection(lockCollection).UpdateOne(ctx,
+		bson.M{"_id": lockID, "owner": m.owner, "expires_at": bson.M{"$gte": time.Now()}},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(m.lockTTL)}},
+	)
+	if err != nil {
+		return fmt.Errorf("extend migration lock: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// lock takes the lock document, or an expired one left by a crashed process
+func (m *Migrator) lock(ctx context.Context) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id":        lockID,
+		"expires_at": bson.M{"$lt": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"owner":      m.owner,
+		"locked_at":  now,
+		"expires_at": now.Add(m.lockTTL),
+	}}
+
+	_, err := m.db.Collection(lockCollection).UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLocked
+	}
+	if err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) unlock(ctx context.Context) error {
+	_, err := m.db.Collection(lockCollection).DeleteOne(ctx, bson.M{"_id": lockID, "owner": m.owner})
+	return err
+}
@@ -0,0 +1,193 @@
+package migrate
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func setupTestDB(t *testing.T) (*mongo.Database, func()) {
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	require.NoError(t, err)
+
+	database := client.Database("test_news_service_migrate")
+	err = database.Drop(ctx)
+	require.NoError(t, err)
+
+	return database, func() {
+		err := database.Drop(ctx)
+		require.NoError(t, err)
+		client.Disconnect(ctx)
+	}
+}
+
+// counterMigration increments a counter document so tests can see what ran
+func counterMigration(version int64, field string) Migration {
+	change := func(delta int) Func {
+		return func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("counters").UpdateOne(ctx,
+				bson.M{"_id": "c"},
+				bson.M{"$inc": bson.M{field: delta}},
+				options.Update().SetUpsert(true),
+			)
+			return err
+		}
+	}
+	return Migration{Version: version, Name: field, Up: change(1), Down: change(-1)}
+}
+
+func counters(t *testing.T, db *mongo.Database) bson.M {
+	var doc bson.M
+	err := db.Collection("counters").FindOne(context.Background(), bson.M{"_id": "c"}).Decode(&doc)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestNew_Validation(t *testing.T) {
+	up := func(ctx context.Context, db *mongo.Database) error { return nil }
+
+	_, err := New(nil, []Migration{{Version: 0, Name: "zero", Up: up}}, discard)
+	assert.Error(t, err)
+
+	_, err = New(nil, []Migration{{Version: 1, Name: "a", Up: up}, {Version: 1, Name: "b", Up: up}}, discard)
+	assert.Error(t, err)
+
+	_, err = New(nil, []Migration{{Version: 1, Name: "no up"}}, discard)
+	assert.Error(t, err)
+
+	m, err := New(nil, []Migration{{Version: 2, Name: "b", Up: up}, {Version: 1, Name: "a", Up: up}}, discard)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), m.migrations[0].Version)
+}
+
+func TestMigrator_UpAndDown(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	m, err := New(db, []Migration{counterMigration(1, "a"), counterMigration(2, "b"), counterMigration(3, "c")}, discard)
+	require.NoError(t, err)
+
+	pending, err := m.Up(ctx, Options{DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, pending, 3)
+
+	applied, err := m.Up(ctx, Options{Target: 2})
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+
+	applied, err = m.Up(ctx, Options{})
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(3), applied[0].Version)
+
+	// Nothing left to do
+	applied, err = m.Up(ctx, Options{})
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	doc := counters(t, db)
+	assert.EqualValues(t, 1, doc["a"])
+	assert.EqualValues(t, 1, doc["c"])
+
+	reverted, err := m.Down(ctx, Options{})
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(3), reverted[0].Version)
+
+	reverted, err = m.Down(ctx, Options{Target: 1})
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(2), reverted[0].Version)
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+	assert.False(t, statuses[2].Applied)
+
+	doc = counters(t, db)
+	assert.EqualValues(t, 1, doc["a"])
+	assert.EqualValues(t, 0, doc["b"])
+	assert.EqualValues(t, 0, doc["c"])
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	first, err := New(db, []Migration{counterMigration(1, "a")}, discard)
+	require.NoError(t, err)
+	second, err := New(db, []Migration{counterMigration(1, "a")}, discard)
+	require.NoError(t, err)
+	second.owner = "other"
+
+	require.NoError(t, first.lock(ctx))
+	_, err = second.Up(ctx, Options{})
+	assert.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, first.unlock(ctx))
+	_, err = second.Up(ctx, Options{})
+	assert.NoError(t, err)
+}
+
+func TestMigrator_LockHeartbeat(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	second, err := New(db, []Migration{counterMigration(1, "a")}, discard)
+	require.NoError(t, err)
+	second.owner = "other"
+
+	// The migration outlasts the TTL, the lock is extended meanwhile
+	slow := Migration{Version: 1, Name: "slow", Up: func(ctx context.Context, db *mongo.Database) error {
+		time.Sleep(3 * second.lockTTL)
+		_, err := second.Up(ctx, Options{})
+		assert.ErrorIs(t, err, ErrLocked)
+		return nil
+	}}
+	first, err := New(db, []Migration{slow}, discard)
+	require.NoError(t, err)
+	first.lockTTL = 300 * time.Millisecond
+	second.lockTTL = first.lockTTL
+
+	done, err := first.Up(ctx, Options{})
+	require.NoError(t, err)
+	assert.Len(t, done, 1)
+}
+
+func TestMigrator_LockLost(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Another process takes the lock while the migration runs
+	steal := Migration{Version: 1, Name: "steal", Up: func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(lockCollection).UpdateOne(ctx, bson.M{"_id": lockID}, bson.M{"$set": bson.M{"owner": "other"}})
+		return err
+	}}
+	m, err := New(db, []Migration{steal, counterMigration(2, "b")}, discard)
+	require.NoError(t, err)
+
+	done, err := m.Up(ctx, Options{})
+	assert.ErrorIs(t, err, ErrLockLost)
+	assert.Empty(t, done)
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Applied, "nothing is recorded without the lock")
+	assert.False(t, statuses[1].Applied)
+}
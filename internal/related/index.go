@@ -0,0 +1,144 @@
+package related
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"news_service/internal/domain"
+)
+
+// index holds the term frequencies of every article and how many articles
+// each term appears in
+type index struct {
+	docs map[string]*document
+	df   map[string]int
+	// stale is set when the TF-IDF vectors no longer match the articles
+	stale bool
+}
+
+type document struct {
+	news  *domain.News
+	group string
+	tags  map[string]struct{}
+	tf    map[string]float64
+	// vector is the TF-IDF vector scaled to unit length
+	vector map[string]float64
+}
+
+func (d *document) published() time.Time {
+	if d.news.PublishedAt != nil {
+		return *d.news.PublishedAt
+	}
+	return d.news.CreatedAt
+}
+
+func newIndex() *index {
+	return &index{
+		docs:  make(map[string]*document),
+		df:    make(map[string]int),
+		stale: true,
+	}
+}
+
+// put adds a copy of news, replacing the article with the same ID
+func (idx *index) put(news *domain.News, titleWeight float64) *document {
+	snapshot := *news
+	news = &snapshot
+	id := news.ID.Hex()
+	idx.remove(id)
+
+	counts := make(map[string]float64)
+	for _, term := range terms(news.Title) {
+		counts[term] += titleWeight
+	}
+	for _, term := range terms(news.Content) {
+		counts[term]++
+	}
+
+	doc := &document{
+		news:  news,
+		group: news.TranslationGroup().Hex(),
+		tags:  make(map[string]struct{}, len(news.Tags)),
+		tf:    make(map[string]float64, len(counts)),
+	}
+	for _, tag := range news.Tags {
+		doc.tags[strings.ToLower(tag)] = struct{}{}
+	}
+	for term, count := range counts {
+		// Sublinear, so a word repeated throughout does not drown the rest
+		doc.tf[term] = 1 + math.Log(count)
+		idx.df[term]++
+	}
+
+	idx.docs[id] = doc
+	idx.stale = true
+	return doc
+}
+
+func (idx *index) remove(id string) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.tf {
+		if idx.df[term]--; idx.df[term] == 0 {
+			delete(idx.df, term)
+		}
+	}
+	delete(idx.docs, id)
+	idx.stale = true
+}
+
+// weigh brings the TF-IDF vectors up to date with the articles
+func (idx *index) weigh() {
+	if !idx.stale {
+		return
+	}
+	n := float64(len(idx.docs))
+	for _, doc := range idx.docs {
+		doc.vector = make(map[string]float64, len(doc.tf))
+		var norm float64
+		for term, tf := range doc.tf {
+			weight := tf * math.Log(1+n/float64(idx.df[term]))
+			doc.vector[term] = weight
+			norm += weight * weight
+		}
+		if norm > 0 {
+			norm = math.Sqrt(norm)
+			for term := range doc.vector {
+				doc.vector[term] /= norm
+			}
+		}
+	}
+	idx.stale = false
+}
+
+// terms splits text into lower case words, leaving out stop words and
+// single letters
+func terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, word := range words {
+		if len([]rune(word)) > 1 && !stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+var stopWords = func() map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(`
+		about after all also an and any are as at be been but by can could did
+		do does for from had has have he her his how if in into is it its
+		just more most no not of on one or our out over she so some such than
+		that the their them then there these they this to up was we were what
+		when where which while who will with would you your`) {
+		words[word] = true
+	}
+	return words
+}()
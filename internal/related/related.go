@@ -0,0 +1,333 @@
+// Package related finds the articles most like a given one, by TF-IDF
+// similarity of their text, shared tags and recency
+package related
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+// Options weigh the parts of the relatedness score
+type Options struct {
+	// Max is how many related articles are ranked and cached per article
+	Max int
+	// TitleWeight counts a word in the title as this many in the content
+	TitleWeight float64
+	// TagWeight is added for sharing every tag, scaled by the share of tags
+	// in common
+	TagWeight float64
+	// RecencyWeight boosts new articles by up to this fraction of their
+	// score, halving every HalfLife of age
+	RecencyWeight float64
+	HalfLife      time.Duration
+	// MaxAge is how long the index is used before it is read again, to pick
+	// up changes made by other instances. Zero keeps it for good.
+	MaxAge time.Duration
+}
+
+// DefaultOptions returns the weights used by the service
+func DefaultOptions() Options {
+	return Options{
+		Max:           10,
+		TitleWeight:   3,
+		TagWeight:     0.5,
+		RecencyWeight: 0.5,
+		HalfLife:      30 * 24 * time.Hour,
+		MaxAge:        5 * time.Minute,
+	}
+}
+
+// Recommender ranks related articles from an in-memory index of every
+// article, loaded on first use and again once older than MaxAge. Rankings
+// are cached per article until an article changes, as told through Publish.
+type Recommender struct {
+	repo domain.NewsRepository
+	opts Options
+	now  func() time.Time
+
+	// loadMu is held while the index is read from the repository, which
+	// happens without holding mu
+	loadMu sync.Mutex
+
+	mu       sync.Mutex
+	index    *index
+	loadedAt time.Time
+	ranked   map[string][]*document
+	// applied collects the changes applied while a new index is read, to be
+	// applied to it too, nil when none is
+	applied map[string]change
+
+	// pending holds changes published since the index was last brought up
+	// to date, under its own lock so publishing never waits for a load
+	pendingMu sync.Mutex
+	pending   map[string]change
+}
+
+// change is the state of an article after an event, nil with deleted
+// unset when it has to be read again
+type change struct {
+	article *domain.News
+	deleted bool
+}
+
+var _ domain.Publisher = (*Recommender)(nil)
+
+// New creates a recommender over the articles in repo
+func New(repo domain.NewsRepository, opts Options) *Recommender {
+	return &Recommender{
+		repo:    repo,
+		opts:    opts,
+		now:     time.Now,
+		ranked:  make(map[string][]*document),
+		pending: make(map[string]change),
+	}
+}
+
+// Publish records changed articles, the index catches up with them on the
+// next lookup
+func (r *Recommender) Publish(events ...domain.Event) {
+	r.pendingMu.Lock()
+	defer r.pendingMu.Unlock()
+	for _, event := range events {
+		r.pending[event.ArticleID] = change{
+			article: event.Article,
+			deleted: event.Type == domain.EventArticleDeleted,
+		}
+	}
+}
+
+// Related returns up to limit published articles in the language of the
+// article with the given ID, best match first. Other language variants of
+// the article are left out.
+func (r *Recommender) Related(ctx context.Context, id string, limit int) ([]*domain.News, error) {
+	if limit <= 0 || limit > r.opts.Max {
+		limit = r.opts.Max
+	}
+
+	if err := r.load(ctx); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.refresh(ctx); err != nil {
+		return nil, err
+	}
+	source, ok := r.index.docs[id]
+	if !ok {
+		// Possibly written by another instance
+		news, err := r.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		source = r.index.put(news, r.opts.TitleWeight)
+		r.ranked = make(map[string][]*document)
+	}
+
+	ranked, ok := r.ranked[id]
+	if !ok {
+		ranked = r.rank(source)
+		r.ranked[id] = ranked
+	}
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	related := make([]*domain.News, len(ranked))
+	for i, doc := range ranked {
+		news := *doc.news
+		related[i] = &news
+	}
+	return related, nil
+}
+
+// expired reports whether the index has to be read again
+func (r *Recommender) expired() bool {
+	return r.index == nil || (r.opts.MaxAge > 0 && r.now().Sub(r.loadedAt) >= r.opts.MaxAge)
+}
+
+// load reads the index when there is none or it has expired. An expired
+// index is read by one caller while the others keep using it, and kept when
+// reading fails.
+func (r *Recommender) load(ctx context.Context) error {
+	r.mu.Lock()
+	missing, expired := r.index == nil, r.expired()
+	r.mu.Unlock()
+	switch {
+	case missing:
+		r.loadMu.Lock()
+	case expired:
+		if !r.loadMu.TryLock() {
+			return nil
+		}
+	default:
+		return nil
+	}
+	defer r.loadMu.Unlock()
+
+	r.mu.Lock()
+	if !r.expired() {
+		// Read by another caller meanwhile
+		r.mu.Unlock()
+		return nil
+	}
+	r.applied = make(map[string]change)
+	r.mu.Unlock()
+
+	idx := newIndex()
+	err := r.repo.Each(ctx, func(news *domain.News) error {
+		idx.put(news, r.opts.TitleWeight)
+		return nil
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	applied := r.applied
+	r.applied = nil
+	if err != nil {
+		if r.index != nil {
+			return nil
+		}
+		return fmt.Errorf("load articles: %w", err)
+	}
+	// The read may have missed changes applied to the old index meanwhile
+	for id, c := range applied {
+		if c.deleted {
+			idx.remove(id)
+		} else {
+			idx.put(c.article, r.opts.TitleWeight)
+		}
+	}
+	r.index = idx
+	r.loadedAt = r.now()
+	r.ranked = make(map[string][]*document)
+	return nil
+}
+
+// refresh applies pending changes to the index, dropping the cached rankings
+// when anything changed
+func (r *Recommender) refresh(ctx context.Context) error {
+	r.pendingMu.Lock()
+	pending := r.pending
+	r.pending = make(map[string]change)
+	r.pendingMu.Unlock()
+
+	// Changes that cannot be applied now are retried on the next lookup
+	failed := func(err error) error {
+		r.pendingMu.Lock()
+		defer r.pendingMu.Unlock()
+		for id, c := range pending {
+			if _, newer := r.pending[id]; !newer {
+				r.pending[id] = c
+			}
+		}
+		return err
+	}
+
+	for id, c := range pending {
+		if !c.deleted && c.article == nil {
+			news, err := r.repo.GetByID(ctx, id)
+			switch {
+			case errors.Is(err, domain.ErrNotFound):
+				// Deleted since
+				c.deleted = true
+			case err != nil:
+				return failed(fmt.Errorf("reload article %s: %w", id, err))
+			default:
+				c.article = news
+			}
+		}
+
+		if c.deleted {
+			r.index.remove(id)
+		} else {
+			r.index.put(c.article, r.opts.TitleWeight)
+		}
+		if r.applied != nil {
+			r.applied[id] = c
+		}
+		delete(pending, id)
+	}
+	if r.index.stale {
+		r.ranked = make(map[string][]*document)
+	}
+	return nil
+}
+
+// rank scores every candidate against source and keeps the best
+func (r *Recommender) rank(source *document) []*document {
+	r.index.weigh()
+	now := r.now()
+
+	type scored struct {
+		doc   *document
+		score float64
+	}
+	var candidates []scored
+	for _, doc := range r.index.docs {
+		if doc.news.Status != domain.StatusPublished || doc.news.Language != source.news.Language ||
+			doc.group == source.group {
+			continue
+		}
+
+		score := cosine(source.vector, doc.vector) + r.opts.TagWeight*sharedTags(source.tags, doc.tags)
+		if score <= 0 {
+			continue
+		}
+		if r.opts.HalfLife > 0 {
+			age := math.Max(0, now.Sub(doc.published()).Hours())
+			score *= 1 + r.opts.RecencyWeight*math.Exp2(-age/r.opts.HalfLife.Hours())
+		}
+		candidates = append(candidates, scored{doc, score})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].doc.published().After(candidates[j].doc.published())
+	})
+	if len(candidates) > r.opts.Max {
+		candidates = candidates[:r.opts.Max]
+	}
+
+	ranked := make([]*document, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = c.doc
+	}
+	return ranked
+}
+
+// sharedTags returns the Jaccard similarity of two tag sets
+func sharedTags(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var common int
+	for tag := range a {
+		if _, ok := b[tag]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// cosine returns the dot product of two unit vectors
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	var sum float64
+	for term, weight := range a {
+		sum += weight * b[term]
+	}
+	return sum
+}
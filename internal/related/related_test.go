@@ -0,0 +1,211 @@
+package related
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+var errNotFound = errors.New("not found")
+
+type fakeRepository struct {
+	domain.NewsRepository
+	articles []*domain.News
+	loads    int
+	// reading runs at the start of Each
+	reading func()
+}
+
+func (r *fakeRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	r.loads++
+	if r.reading != nil {
+		r.reading()
+	}
+	for _, news := range r.articles {
+		if err := fn(news); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	for _, news := range r.articles {
+		if news.ID.Hex() == id {
+			return news, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+var now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func article(title, content string, age time.Duration, tags ...string) *domain.News {
+	published := now.Add(-age)
+	return &domain.News{
+		ID:          primitive.NewObjectID(),
+		Title:       title,
+		Content:     content,
+		Tags:        tags,
+		Language:    "en",
+		Status:      domain.StatusPublished,
+		CreatedAt:   published,
+		PublishedAt: &published,
+	}
+}
+
+func newRecommender(articles ...*domain.News) (*Recommender, *fakeRepository) {
+	repo := &fakeRepository{articles: articles}
+	r := New(repo, DefaultOptions())
+	r.now = func() time.Time { return now }
+	return r, repo
+}
+
+func titles(related []*domain.News) []string {
+	var titles []string
+	for _, news := range related {
+		titles = append(titles, news.Title)
+	}
+	return titles
+}
+
+func TestRecommender_RanksBySimilarity(t *testing.T) {
+	source := article("Go generics explained", "Type parameters let Go functions work over many types.", 0)
+	near := article("Generics in Go", "Go type parameters and constraints in practice.", 0)
+	far := article("Go to the beach", "Summer weather forecast for the weekend.", 0)
+	unrelated := article("Cooking pasta", "Boil water and add salt.", 0)
+	r, _ := newRecommender(source, near, far, unrelated)
+
+	related, err := r.Related(context.Background(), source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Generics in Go", "Go to the beach"}, titles(related))
+
+	related, err = r.Related(context.Background(), source.ID.Hex(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Generics in Go"}, titles(related))
+}
+
+func TestRecommender_TagsAndRecency(t *testing.T) {
+	source := article("Election results", "Votes counted in the capital.", 0, "politics")
+	tagged := article("Market update", "Votes counted in the capital.", 0, "politics")
+	untagged := article("Market report", "Votes counted in the capital.", 0)
+	r, _ := newRecommender(source, untagged, tagged)
+
+	related, err := r.Related(context.Background(), source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Market update", "Market report"}, titles(related))
+
+	old := article("Market history", "Votes counted in the capital.", 365*24*time.Hour)
+	fresh := article("Market today", "Votes counted in the capital.", time.Hour)
+	r, _ = newRecommender(source, old, fresh)
+
+	related, err = r.Related(context.Background(), source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Market today", "Market history"}, titles(related))
+}
+
+func TestRecommender_LeavesOutOtherArticles(t *testing.T) {
+	source := article("Rocket launch", "The rocket reached orbit.", 0)
+	draft := article("Rocket launch draft", "The rocket reached orbit.", 0)
+	draft.Status = domain.StatusDraft
+	german := article("Raketenstart", "The rocket reached orbit.", 0)
+	german.Language = "de"
+	translation := article("Rocket launch", "The rocket reached orbit.", 0)
+	translation.TranslationOf = &source.ID
+	match := article("Second rocket launch", "Another rocket reached orbit.", 0)
+	r, _ := newRecommender(source, draft, german, translation, match)
+
+	related, err := r.Related(context.Background(), source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Second rocket launch"}, titles(related))
+}
+
+func TestRecommender_Publish(t *testing.T) {
+	source := article("Rocket launch", "The rocket reached orbit.", 0)
+	match := article("Second rocket launch", "Another rocket reached orbit.", 0)
+	r, repo := newRecommender(source, match)
+	ctx := context.Background()
+
+	related, err := r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Second rocket launch"}, titles(related))
+
+	added := article("Rocket crash", "The rocket failed to reach orbit.", 0)
+	r.Publish(domain.Event{Type: domain.EventArticleCreated, ArticleID: added.ID.Hex(), Article: added})
+	related, err = r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Second rocket launch", "Rocket crash"}, titles(related))
+
+	r.Publish(domain.Event{Type: domain.EventArticleDeleted, ArticleID: match.ID.Hex()})
+	related, err = r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Rocket crash"}, titles(related))
+	assert.Equal(t, 1, repo.loads)
+
+	_, err = r.Related(ctx, primitive.NewObjectID().Hex(), 10)
+	assert.ErrorIs(t, err, errNotFound)
+}
+
+func TestRecommender_MaxAge(t *testing.T) {
+	source := article("Rocket launch", "The rocket reached orbit.", 0)
+	match := article("Second rocket launch", "Another rocket reached orbit.", 0)
+	other := article("Third rocket launch", "One more rocket reached orbit.", 0)
+	r, repo := newRecommender(source, match, other)
+	clock := now
+	r.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	related, err := r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Second rocket launch", "Third rocket launch"}, titles(related))
+
+	// Another instance archives one article and deletes the other
+	archived := *match
+	archived.Status = domain.StatusArchived
+	repo.articles = []*domain.News{source, &archived}
+
+	clock = clock.Add(r.opts.MaxAge - time.Second)
+	related, err = r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Len(t, related, 2, "the index is kept until it expires")
+
+	clock = clock.Add(time.Second)
+	related, err = r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, related)
+	assert.Equal(t, 2, repo.loads)
+}
+
+func TestRecommender_ChangesWhileLoading(t *testing.T) {
+	source := article("Rocket launch", "The rocket reached orbit.", 0)
+	r, repo := newRecommender(source)
+	clock := now
+	r.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	_, err := r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+
+	// Written through this instance after the repository was read
+	added := article("Second rocket launch", "Another rocket reached orbit.", 0)
+	repo.reading = func() {
+		repo.reading = nil
+		r.Publish(domain.Event{Type: domain.EventArticleCreated, ArticleID: added.ID.Hex(), Article: added})
+		related, err := r.Related(ctx, source.ID.Hex(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Second rocket launch"}, titles(related), "served from the current index meanwhile")
+	}
+	clock = clock.Add(r.opts.MaxAge)
+
+	related, err := r.Related(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Second rocket launch"}, titles(related))
+	assert.Equal(t, 2, repo.loads)
+}
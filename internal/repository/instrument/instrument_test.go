@@ -0,0 +1,144 @@
+package instrument
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+	"news_service/internal/logging"
+	"news_service/internal/metrics"
+)
+
+// stubRepository returns err from every call
+type stubRepository struct {
+	err error
+}
+
+func (s *stubRepository) Create(ctx context.Context, news *domain.News) error { return s.err }
+
+func (s *stubRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	return &domain.News{}, s.err
+}
+
+func (s *stubRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	return nil, 0, s.err
+}
+
+func (s *stubRepository) Update(ctx context.Context, news *domain.News) error { return s.err }
+
+func (s *stubRepository) Delete(ctx context.Context, id string) error { return s.err }
+
+func (s *stubRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	return nil, 0, s.err
+}
+
+func (s *stubRepository) Insert(ctx context.Context, news *domain.News) error { return s.err }
+
+func (s *stubRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	return s.err == nil, s.err
+}
+
+func (s *stubRepository) Each(ctx context.Context, fn func(*domain.News) error) error { return s.err }
+
+func (s *stubRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	return batchResults(ids), s.err
+}
+
+func (s *stubRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	return batchResults(ids), s.err
+}
+
+func (s *stubRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	return nil, s.err
+}
+
+// batchResults reports success for every id
+func batchResults(ids []string) []domain.BatchResult {
+	results := make([]domain.BatchResult, len(ids))
+	for i, id := range ids {
+		results[i] = domain.BatchResult{ID: id, OK: true}
+	}
+	return results
+}
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWithLogging_Error(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	repo := WithLogging(&stubRepository{err: errors.New("boom")}, logger)
+	ctx := logging.WithRequestID(context.Background(), "req-42")
+
+	_, err = repo.GetByID(ctx, "abc")
+	assert.EqualError(t, err, "boom")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "GetByID", records[0]["operation"])
+	assert.Equal(t, "req-42", records[0]["request_id"])
+	assert.Equal(t, "boom", records[0]["error"])
+	assert.Contains(t, records[0], "latency")
+}
+
+func TestWithLogging_SuccessAtDebug(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	repo := WithLogging(&stubRepository{}, logger)
+	require.NoError(t, repo.Delete(context.Background(), "abc"))
+	assert.Empty(t, buf.String())
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := metrics.New()
+
+	repo := WithMetrics(&stubRepository{}, m)
+	require.NoError(t, repo.Create(context.Background(), &domain.News{}))
+	require.NoError(t, repo.Delete(context.Background(), "abc"))
+
+	failing := WithMetrics(&stubRepository{err: errors.New("boom")}, m)
+	assert.Error(t, failing.Create(context.Background(), &domain.News{}))
+	_, _, err := failing.Search(context.Background(), "", "q", 1, 10)
+	assert.Error(t, err)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ArticlesCreated))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ArticlesDeleted))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RepositoryErrors.WithLabelValues("Create")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.RepositoryErrors.WithLabelValues("Search")))
+}
+
+func TestWithMetrics_Batch(t *testing.T) {
+	m := metrics.New()
+
+	repo := WithMetrics(&stubRepository{}, m)
+	_, err := repo.DeleteMany(context.Background(), []string{"a", "b"})
+	require.NoError(t, err)
+	_, err = repo.UpdateMany(context.Background(), []string{"c"}, domain.BatchUpdate{Status: domain.StatusArchived})
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.ArticlesDeleted))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.ArticlesUpdated))
+}
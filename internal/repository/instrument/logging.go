@@ -0,0 +1,120 @@
+package instrument
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+type loggingRepository struct {
+	next   domain.NewsRepository
+	logger *slog.Logger
+}
+
+// WithLogging wraps repo so that every operation is logged with its name and
+// latency. Failures are logged at error level, successes at debug level.
+func WithLogging(repo domain.NewsRepository, logger *slog.Logger) domain.NewsRepository {
+	return &loggingRepository{
+		next:   repo,
+		logger: logger.With("component", "repository"),
+	}
+}
+
+func (r *loggingRepository) log(ctx context.Context, op string, start time.Time, err error, attrs ...slog.Attr) {
+	attrs = append(attrs,
+		slog.String("operation", op),
+		slog.Duration("latency", time.Since(start)),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		r.logger.LogAttrs(ctx, slog.LevelError, "repository operation failed", attrs...)
+		return
+	}
+	r.logger.LogAttrs(ctx, slog.LevelDebug, "repository operation", attrs...)
+}
+
+func (r *loggingRepository) Create(ctx context.Context, news *domain.News) error {
+	start := time.Now()
+	err := r.next.Create(ctx, news)
+	r.log(ctx, "Create", start, err)
+	return err
+}
+
+func (r *loggingRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	start := time.Now()
+	news, err := r.next.GetByID(ctx, id)
+	r.log(ctx, "GetByID", start, err, slog.String("id", id))
+	return news, err
+}
+
+func (r *loggingRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	start := time.Now()
+	news, total, err := r.next.GetAll(ctx, lang, page, limit)
+	r.log(ctx, "GetAll", start, err, slog.String("lang", lang), slog.Int("page", page), slog.Int("limit", limit))
+	return news, total, err
+}
+
+func (r *loggingRepository) Update(ctx context.Context, news *domain.News) error {
+	start := time.Now()
+	err := r.next.Update(ctx, news)
+	r.log(ctx, "Update", start, err, slog.String("id", news.ID.Hex()))
+	return err
+}
+
+func (r *loggingRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.log(ctx, "Delete", start, err, slog.String("id", id))
+	return err
+}
+
+func (r *loggingRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	start := time.Now()
+	news, total, err := r.next.Search(ctx, lang, query, page, limit)
+	r.log(ctx, "Search", start, err, slog.String("lang", lang), slog.String("query", query), slog.Int("page", page), slog.Int("limit", limit))
+	return news, total, err
+}
+
+func (r *loggingRepository) Insert(ctx context.Context, news *domain.News) error {
+	start := time.Now()
+	err := r.next.Insert(ctx, news)
+	r.log(ctx, "Insert", start, err, slog.String("id", news.ID.Hex()))
+	return err
+}
+
+func (r *loggingRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	start := time.Now()
+	created, err := r.next.Upsert(ctx, news)
+	r.log(ctx, "Upsert", start, err, slog.String("id", news.ID.Hex()), slog.Bool("created", created))
+	return created, err
+}
+
+func (r *loggingRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	start := time.Now()
+	err := r.next.Each(ctx, fn)
+	r.log(ctx, "Each", start, err)
+	return err
+}
+
+func (r *loggingRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	start := time.Now()
+	results, err := r.next.DeleteMany(ctx, ids)
+	r.log(ctx, "DeleteMany", start, err, slog.Int("count", len(ids)))
+	return results, err
+}
+
+func (r *loggingRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	start := time.Now()
+	results, err := r.next.UpdateMany(ctx, ids, update)
+	r.log(ctx, "UpdateMany", start, err, slog.Int("count", len(ids)), slog.String("status", update.Status), slog.Any("tags", update.Tags))
+	return results, err
+}
+
+func (r *loggingRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	start := time.Now()
+	news, err := r.next.Translations(ctx, id)
+	r.log(ctx, "Translations", start, err, slog.String("id", id), slog.Int("count", len(news)))
+	return news, err
+}
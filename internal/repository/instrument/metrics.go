@@ -0,0 +1,145 @@
+package instrument
+
+import (
+	"context"
+	"time"
+
+	"news_service/internal/domain"
+	"news_service/internal/metrics"
+)
+
+type metricsRepository struct {
+	next    domain.NewsRepository
+	metrics *metrics.Metrics
+}
+
+// WithMetrics wraps repo so that the latency and errors of every operation are
+// recorded, along with the number of articles created, updated and deleted
+func WithMetrics(repo domain.NewsRepository, m *metrics.Metrics) domain.NewsRepository {
+	return &metricsRepository{
+		next:    repo,
+		metrics: m,
+	}
+}
+
+func (r *metricsRepository) observe(op string, start time.Time, err error) {
+	r.metrics.RepositoryDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	if err != nil {
+		r.metrics.RepositoryErrors.WithLabelValues(op).Inc()
+	}
+}
+
+func (r *metricsRepository) Create(ctx context.Context, news *domain.News) error {
+	start := time.Now()
+	err := r.next.Create(ctx, news)
+	r.observe("Create", start, err)
+	if err == nil {
+		r.metrics.ArticlesCreated.Inc()
+	}
+	return err
+}
+
+func (r *metricsRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	start := time.Now()
+	news, err := r.next.GetByID(ctx, id)
+	r.observe("GetByID", start, err)
+	return news, err
+}
+
+func (r *metricsRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	start := time.Now()
+	news, total, err := r.next.GetAll(ctx, lang, page, limit)
+	r.observe("GetAll", start, err)
+	return news, total, err
+}
+
+func (r *metricsRepository) Update(ctx context.Context, news *domain.News) error {
+	start := time.Now()
+	err := r.next.Update(ctx, news)
+	r.observe("Update", start, err)
+	if err == nil {
+		r.metrics.ArticlesUpdated.Inc()
+	}
+	return err
+}
+
+func (r *metricsRepository) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.next.Delete(ctx, id)
+	r.observe("Delete", start, err)
+	if err == nil {
+		r.metrics.ArticlesDeleted.Inc()
+	}
+	return err
+}
+
+func (r *metricsRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	start := time.Now()
+	news, total, err := r.next.Search(ctx, lang, query, page, limit)
+	r.observe("Search", start, err)
+	return news, total, err
+}
+
+func (r *metricsRepository) Insert(ctx context.Context, news *domain.News) error {
+	start := time.Now()
+	err := r.next.Insert(ctx, news)
+	r.observe("Insert", start, err)
+	if err == nil {
+		r.metrics.ArticlesCreated.Inc()
+	}
+	return err
+}
+
+func (r *metricsRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	start := time.Now()
+	created, err := r.next.Upsert(ctx, news)
+	r.observe("Upsert", start, err)
+	switch {
+	case err != nil:
+	case created:
+		r.metrics.ArticlesCreated.Inc()
+	default:
+		r.metrics.ArticlesUpdated.Inc()
+	}
+	return created, err
+}
+
+func (r *metricsRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	start := time.Now()
+	err := r.next.Each(ctx, fn)
+	r.observe("Each", start, err)
+	return err
+}
+
+func (r *metricsRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	start := time.Now()
+	results, err := r.next.DeleteMany(ctx, ids)
+	r.observe("DeleteMany", start, err)
+	r.metrics.ArticlesDeleted.Add(float64(succeeded(results)))
+	return results, err
+}
+
+func (r *metricsRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	start := time.Now()
+	results, err := r.next.UpdateMany(ctx, ids, update)
+	r.observe("UpdateMany", start, err)
+	r.metrics.ArticlesUpdated.Add(float64(succeeded(results)))
+	return results, err
+}
+
+func (r *metricsRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	start := time.Now()
+	news, err := r.next.Translations(ctx, id)
+	r.observe("Translations", start, err)
+	return news, err
+}
+
+func succeeded(results []domain.BatchResult) int {
+	count := 0
+	for _, result := range results {
+		if result.OK {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,100 @@
+package instrument
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"news_service/internal/domain"
+	"news_service/internal/tracing"
+)
+
+type tracingRepository struct {
+	next   domain.NewsRepository
+	tracer trace.Tracer
+}
+
+// WithTracing wraps repo so that every operation runs in its own span
+func WithTracing(repo domain.NewsRepository) domain.NewsRepository {
+	return &tracingRepository{
+		next:   repo,
+		tracer: tracing.Tracer(),
+	}
+}
+
+func (r *tracingRepository) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return r.tracer.Start(ctx, "newsRepository."+op, trace.WithAttributes(attrs...))
+}
+
+func (r *tracingRepository) Create(ctx context.Context, news *domain.News) (err error) {
+	ctx, span := r.start(ctx, "Create")
+	defer func() { tracing.End(span, err) }()
+	return r.next.Create(ctx, news)
+}
+
+func (r *tracingRepository) GetByID(ctx context.Context, id string) (_ *domain.News, err error) {
+	ctx, span := r.start(ctx, "GetByID", attribute.String("news.id", id))
+	defer func() { tracing.End(span, err) }()
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *tracingRepository) GetAll(ctx context.Context, lang string, page, limit int) (_ []*domain.News, _ int64, err error) {
+	ctx, span := r.start(ctx, "GetAll", attribute.String("lang", lang), attribute.Int("page", page), attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
+	return r.next.GetAll(ctx, lang, page, limit)
+}
+
+func (r *tracingRepository) Update(ctx context.Context, news *domain.News) (err error) {
+	ctx, span := r.start(ctx, "Update", attribute.String("news.id", news.ID.Hex()))
+	defer func() { tracing.End(span, err) }()
+	return r.next.Update(ctx, news)
+}
+
+func (r *tracingRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := r.start(ctx, "Delete", attribute.String("news.id", id))
+	defer func() { tracing.End(span, err) }()
+	return r.next.Delete(ctx, id)
+}
+
+func (r *tracingRepository) Search(ctx context.Context, lang, query string, page, limit int) (_ []*domain.News, _ int64, err error) {
+	ctx, span := r.start(ctx, "Search", attribute.String("lang", lang), attribute.String("query", query), attribute.Int("page", page), attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
+	return r.next.Search(ctx, lang, query, page, limit)
+}
+
+func (r *tracingRepository) Insert(ctx context.Context, news *domain.News) (err error) {
+	ctx, span := r.start(ctx, "Insert", attribute.String("news.id", news.ID.Hex()))
+	defer func() { tracing.End(span, err) }()
+	return r.next.Insert(ctx, news)
+}
+
+func (r *tracingRepository) Upsert(ctx context.Context, news *domain.News) (_ bool, err error) {
+	ctx, span := r.start(ctx, "Upsert", attribute.String("news.id", news.ID.Hex()))
+	defer func() { tracing.End(span, err) }()
+	return r.next.Upsert(ctx, news)
+}
+
+func (r *tracingRepository) Each(ctx context.Context, fn func(*domain.News) error) (err error) {
+	ctx, span := r.start(ctx, "Each")
+	defer func() { tracing.End(span, err) }()
+	return r.next.Each(ctx, fn)
+}
+
+func (r *tracingRepository) DeleteMany(ctx context.Context, ids []string) (_ []domain.BatchResult, err error) {
+	ctx, span := r.start(ctx, "DeleteMany", attribute.Int("batch.size", len(ids)))
+	defer func() { tracing.End(span, err) }()
+	return r.next.DeleteMany(ctx, ids)
+}
+
+func (r *tracingRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) (_ []domain.BatchResult, err error) {
+	ctx, span := r.start(ctx, "UpdateMany", attribute.Int("batch.size", len(ids)), attribute.String("batch.status", update.Status))
+	defer func() { tracing.End(span, err) }()
+	return r.next.UpdateMany(ctx, ids, update)
+}
+
+func (r *tracingRepository) Translations(ctx context.Context, id string) (_ []*domain.News, err error) {
+	ctx, span := r.start(ctx, "Translations", attribute.String("news.id", id))
+	defer func() { tracing.End(span, err) }()
+	return r.next.Translations(ctx, id)
+}
@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/domain"
+)
+
+func (r *newsRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	return r.bulkWrite(ctx, ids, func(id primitive.ObjectID) mongo.WriteModel {
+		return mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": id})
+	})
+}
+
+func (r *newsRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	if update.Status != "" {
+		set["status"] = update.Status
+	}
+	if update.Status == domain.StatusPublished {
+		set["published_at"] = bson.M{"$ifNull": bson.A{"$published_at", now}}
+	}
+	if len(update.Tags) > 0 {
+		// Append the tags an article does not have yet, keeping its order.
+		// $literal stops tags starting with $ being read as field paths.
+		existing := bson.M{"$ifNull": bson.A{"$tags", bson.A{}}}
+		set["tags"] = bson.M{"$concatArrays": bson.A{
+			existing,
+			bson.M{"$filter": bson.M{
+				"input": bson.M{"$literal": update.Tags},
+				"cond":  bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$this", existing}}}},
+			}},
+		}}
+	}
+	pipeline := mongo.Pipeline{{{Key: "$set", Value: set}}}
+
+	return r.bulkWrite(ctx, ids, func(id primitive.ObjectID) mongo.WriteModel {
+		return mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": id}).SetUpdate(pipeline)
+	})
+}
+
+// bulkWrite runs one write per existing article in a single unordered bulk
+// write and reports the outcome for every distinct ID, including those that
+// are invalid or do not exist
+func (r *newsRepository) bulkWrite(ctx context.Context, ids []string, model func(primitive.ObjectID) mongo.WriteModel) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, 0, len(ids))
+	index := make(map[primitive.ObjectID]int, len(ids))
+	seen := make(map[string]bool, len(ids))
+	var oids []primitive.ObjectID
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			results = append(results, domain.BatchResult{ID: id, Error: "invalid id"})
+			continue
+		}
+		index[oid] = len(results)
+		oids = append(oids, oid)
+		results = append(results, domain.BatchResult{ID: id, Error: domain.ErrNotFound.Error()})
+	}
+	if len(oids) == 0 {
+		return results, nil
+	}
+
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": oids}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var existing []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return results, nil
+	}
+
+	models := make([]mongo.WriteModel, len(existing))
+	positions := make([]int, len(existing))
+	for i, doc := range existing {
+		models[i] = model(doc.ID)
+		positions[i] = index[doc.ID]
+		results[positions[i]] = domain.BatchResult{ID: results[positions[i]].ID, OK: true}
+	}
+
+	_, err = r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			result := &results[positions[writeErr.Index]]
+			result.OK = false
+			result.Error = writeErr.Message
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,185 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// newsIndexes declares every index the news repository relies on
+var newsIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("created_at_desc"),
+	},
+	{
+		Keys: bson.D{{Key: "slug", Value: 1}},
+		Options: options.Index().
+			SetName("slug_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+	},
+	{
+		// Each article is indexed in its own language, see textLanguage
+		Keys: bson.D{{Key: "title", Value: "text"}, {Key: "content", Value: "text"}},
+		Options: options.Index().
+			SetName("title_content_text").
+			SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "content", Value: 1}}).
+			SetDefaultLanguage("english").
+			SetLanguageOverride("text_language"),
+	},
+	{
+		Keys:    bson.D{{Key: "language", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("language_created_at"),
+	},
+	{
+		// One variant per language of an article
+		Keys: bson.D{{Key: "translation_of", Value: 1}, {Key: "language", Value: 1}},
+		Options: options.Index().
+			SetName("translation_of_language_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"translation_of": bson.M{"$exists": true}}),
+	},
+	{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "published_at", Value: -1}},
+		Options: options.Index().SetName("status_published_at"),
+	},
+}
+
+// declaredIndexes maps every collection to the indexes it needs
+var declaredIndexes = []struct {
+	collection string
+	indexes    []mongo.IndexModel
+}{
+	{collectionName, newsIndexes},
+	{outboxCollection, outboxIndexes},
+	{deliveriesCollection, deliveryIndexes},
+}
+
+// IndexReport describes how the indexes of a collection compare to the
+// declared ones
+type IndexReport struct {
+	Collection string
+	// Created lists declared indexes created by EnsureIndexes
+	Created []string
+	// Missing lists declared indexes that do not exist
+	Missing []string
+	// Unexpected lists existing indexes that are not declared
+	Unexpected []string
+}
+
+// OK reports whether the collection has exactly the declared indexes
+func (r *IndexReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Unexpected) == 0
+}
+
+// CheckIndexes compares the indexes of every collection with the declared
+// ones without changing anything
+func CheckIndexes(ctx context.Context, client *mongo.Client, database string) ([]*IndexReport, error) {
+	db := client.Database(database)
+
+	reports := make([]*IndexReport, 0, len(declaredIndexes))
+	for _, declared := range declaredIndexes {
+		report, err := checkIndexes(ctx, db.Collection(declared.collection), declared.indexes)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// EnsureIndexes creates any missing declared index. Creating an index that
+// already exists is a no-op, so it is safe to run on every startup.
+// Unexpected indexes are reported but never dropped.
+func EnsureIndexes(ctx context.Context, client *mongo.Client, database string) ([]*IndexReport, error) {
+	db := client.Database(database)
+
+	reports := make([]*IndexReport, 0, len(declaredIndexes))
+	for _, declared := range declaredIndexes {
+		report, err := ensureIndexes(ctx, db.Collection(declared.collection), declared.indexes)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func ensureIndexes(ctx context.Context, collection *mongo.Collection, declared []mongo.IndexModel) (*IndexReport, error) {
+	report, err := checkIndexes(ctx, collection, declared)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.Missing) == 0 {
+		return report, nil
+	}
+
+	missing := make(map[string]bool, len(report.Missing))
+	for _, name := range report.Missing {
+		missing[name] = true
+	}
+	var models []mongo.IndexModel
+	for _, model := range declared {
+		if missing[indexName(model)] {
+			models = append(models, model)
+		}
+	}
+
+	created, err := collection.Indexes().CreateMany(ctx, models)
+	if err != nil {
+		return nil, fmt.Errorf("create indexes on %s: %w", collection.Name(), err)
+	}
+	report.Created = created
+	report.Missing = nil
+
+	return report, nil
+}
+
+func checkIndexes(ctx context.Context, collection *mongo.Collection, declared []mongo.IndexModel) (*IndexReport, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list indexes on %s: %w", collection.Name(), err)
+	}
+
+	var existing []struct {
+		Name string `bson:"name"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, fmt.Errorf("list indexes on %s: %w", collection.Name(), err)
+	}
+
+	present := make(map[string]bool, len(existing))
+	for _, index := range existing {
+		present[index.Name] = true
+	}
+
+	report := &IndexReport{Collection: collection.Name()}
+	expected := map[string]bool{"_id_": true}
+	for _, model := range declared {
+		name := indexName(model)
+		expected[name] = true
+		if !present[name] {
+			report.Missing = append(report.Missing, name)
+		}
+	}
+	for name := range present {
+		if !expected[name] {
+			report.Unexpected = append(report.Unexpected, name)
+		}
+	}
+	sort.Strings(report.Unexpected)
+
+	return report, nil
+}
+
+func indexName(model mongo.IndexModel) string {
+	if model.Options != nil && model.Options.Name != nil {
+		return *model.Options.Name
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/domain"
+)
+
+func TestEnsureIndexes(t *testing.T) {
+	client, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	reports, err := CheckIndexes(ctx, client, "test_news_service")
+	require.NoError(t, err)
+	require.Len(t, reports, len(declaredIndexes))
+	assert.Equal(t, collectionName, reports[0].Collection)
+	assert.Len(t, reports[0].Missing, len(newsIndexes))
+	assert.False(t, reports[0].OK())
+
+	reports, err = EnsureIndexes(ctx, client, "test_news_service")
+	require.NoError(t, err)
+	assert.Len(t, reports[0].Created, len(newsIndexes))
+	assert.Len(t, reports[1].Created, len(outboxIndexes))
+	assert.Len(t, reports[2].Created, len(deliveryIndexes))
+	for _, report := range reports {
+		assert.True(t, report.OK(), report.Collection)
+	}
+
+	// Running again is a no-op
+	reports, err = EnsureIndexes(ctx, client, "test_news_service")
+	require.NoError(t, err)
+	for _, report := range reports {
+		assert.Empty(t, report.Created, report.Collection)
+		assert.True(t, report.OK(), report.Collection)
+	}
+}
+
+func TestEnsureIndexes_ReportsUnexpected(t *testing.T) {
+	client, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	collection := client.Database("test_news_service").Collection(collectionName)
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: 1}},
+		Options: options.Index().SetName("legacy_title"),
+	})
+	require.NoError(t, err)
+
+	reports, err := EnsureIndexes(ctx, client, "test_news_service")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"legacy_title"}, reports[0].Unexpected)
+	assert.False(t, reports[0].OK())
+}
+
+func TestEnsureIndexes_UniqueSlug(t *testing.T) {
+	client, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := EnsureIndexes(ctx, client, "test_news_service")
+	require.NoError(t, err)
+
+	repo := NewNewsRepository(client, "test_news_service")
+	require.NoError(t, repo.Create(ctx, &domain.News{Title: "First", Content: "First content", Slug: "same"}))
+	assert.Error(t, repo.Create(ctx, &domain.News{Title: "Second", Content: "Second content", Slug: "same"}))
+
+	// Articles without a slug are not constrained
+	require.NoError(t, repo.Create(ctx, &domain.News{Title: "Third", Content: "Third content"}))
+	require.NoError(t, repo.Create(ctx, &domain.News{Title: "Fourth", Content: "Fourth content"}))
+}
@@ -0,0 +1,143 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/domain"
+	"news_service/internal/migrate"
+)
+
+// Migrations returns the data migrations of the news collection in order
+func Migrations() []migrate.Migration {
+	return []migrate.Migration{
+		{
+			Version: 1,
+			Name:    "backfill_status_and_published_at",
+			Up:      backfillStatusUp,
+			Down:    backfillStatusDown,
+		},
+		{
+			Version: 2,
+			Name:    "backfill_slug",
+			Up:      backfillSlugUp,
+			Down:    backfillSlugDown,
+		},
+		{
+			Version: 3,
+			Name:    "backfill_language",
+			Up:      backfillLanguageUp,
+			Down:    backfillLanguageDown,
+		},
+	}
+}
+
+// backfillStatusUp marks articles created before statuses existed as
+// published at their creation time
+func backfillStatusUp(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(collectionName).UpdateMany(ctx,
+		bson.M{"status": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"status":          domain.StatusPublished,
+			"published_at":    "$created_at",
+			"status_backfill": true,
+		}}}},
+	)
+	return err
+}
+
+func backfillStatusDown(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(collectionName).UpdateMany(ctx,
+		bson.M{"status_backfill": true},
+		bson.M{"$unset": bson.M{"status": "", "published_at": "", "status_backfill": ""}},
+	)
+	return err
+}
+
+// backfillSlugUp gives every article without a slug one derived from its title
+func backfillSlugUp(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(collectionName)
+
+	cursor, err := collection.Find(ctx,
+		bson.M{"slug": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"title": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Title string             `bson:"title"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+
+		_, err := collection.UpdateByID(ctx, doc.ID, bson.M{"$set": bson.M{
+			"slug":          domain.UniqueSlug(doc.Title),
+			"slug_backfill": true,
+		}})
+		if err != nil {
+			return fmt.Errorf("set slug of %s: %w", doc.ID.Hex(), err)
+		}
+	}
+	return cursor.Err()
+}
+
+func backfillSlugDown(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(collectionName).UpdateMany(ctx,
+		bson.M{"slug_backfill": true},
+		bson.M{"$unset": bson.M{"slug": "", "slug_backfill": ""}},
+	)
+	return err
+}
+
+// backfillLanguageUp marks existing articles as written in the default
+// language. The text index is dropped so that EnsureIndexes recreates it
+// with the per-article language.
+func backfillLanguageUp(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(collectionName)
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"language": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			"language":          domain.DefaultLanguage,
+			"text_language":     textLanguage(domain.DefaultLanguage),
+			"language_backfill": true,
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	return dropIndex(ctx, collection, "title_content_text")
+}
+
+func backfillLanguageDown(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(collectionName)
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"language_backfill": true},
+		bson.M{"$unset": bson.M{"language": "", "text_language": "", "language_backfill": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	return dropIndex(ctx, collection, "title_content_text")
+}
+
+// dropIndex drops the named index if it exists
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"news_service/internal/domain"
+	"news_service/internal/migrate"
+)
+
+func TestMigrations_Backfill(t *testing.T) {
+	client, cleanup := setupTestDB(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	db := client.Database("test_news_service")
+	created := time.Now().Add(-time.Hour).Truncate(time.Millisecond).UTC()
+	_, err := db.Collection(collectionName).InsertOne(ctx, bson.M{
+		"title":      "Legacy Article",
+		"content":    "Written before statuses existed",
+		"created_at": created,
+		"updated_at": created,
+	})
+	require.NoError(t, err)
+
+	m, err := migrate.New(db, Migrations(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	_, err = m.Up(ctx, migrate.Options{})
+	require.NoError(t, err)
+
+	repo := NewNewsRepository(client, "test_news_service")
+	news, _, err := repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+	assert.Equal(t, domain.StatusPublished, news[0].Status)
+	assert.Contains(t, news[0].Slug, "legacy-article-")
+	require.NotNil(t, news[0].PublishedAt)
+	assert.True(t, created.Equal(*news[0].PublishedAt))
+	assert.Equal(t, domain.DefaultLanguage, news[0].Language)
+
+	_, err = m.Down(ctx, migrate.Options{Target: -1})
+	require.NoError(t, err)
+
+	news, _, err = repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, news[0].Status)
+	assert.Empty(t, news[0].Slug)
+	assert.Empty(t, news[0].Language)
+}
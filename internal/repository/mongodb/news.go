@@ -17,6 +17,43 @@ const (
 	collectionName = "news"
 )
 
+// textLanguages maps article languages to the MongoDB text search language
+// used to stem them. MongoDB has no Ukrainian stemmer, so Ukrainian text is
+// only tokenized.
+var textLanguages = map[string]string{
+	"en": "english",
+	"uk": "none",
+}
+
+// textLanguage returns the text search language for an article language
+func textLanguage(lang string) string {
+	if language, ok := textLanguages[lang]; ok {
+		return language
+	}
+	return "none"
+}
+
+// newsDocument is an article as stored, with the language its text index
+// entries are built in
+type newsDocument struct {
+	domain.News  `bson:",inline"`
+	TextLanguage string `bson:"text_language"`
+}
+
+func document(news *domain.News) newsDocument {
+	return newsDocument{News: *news, TextLanguage: textLanguage(news.Language)}
+}
+
+// listFilter restricts a query to published articles, in lang unless it is
+// empty
+func listFilter(filter bson.M, lang string) bson.M {
+	filter["status"] = domain.StatusPublished
+	if lang != "" {
+		filter["language"] = lang
+	}
+	return filter
+}
+
 type newsRepository struct {
 	client     *mongo.Client
 	database   string
@@ -33,11 +70,11 @@ func NewNewsRepository(client *mongo.Client, database string) domain.NewsReposit
 	}
 }
 
-func (r *newsRepository) Create(news *domain.News) error {
+func (r *newsRepository) Create(ctx context.Context, news *domain.News) error {
 	news.CreatedAt = time.Now()
 	news.UpdatedAt = time.Now()
 
-	result, err := r.collection.InsertOne(context.Background(), news)
+	result, err := r.collection.InsertOne(ctx, document(news))
 	if err != nil {
 		return err
 	}
@@ -48,17 +85,17 @@ func (r *newsRepository) Create(news *domain.News) error {
 	return nil
 }
 
-func (r *newsRepository) GetByID(id string) (*domain.News, error) {
+func (r *newsRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
 
 	var news domain.News
-	err = r.collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&news)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&news)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("news not found")
+			return nil, domain.ErrNotFound
 		}
 		return nil, err
 	}
@@ -66,25 +103,26 @@ func (r *newsRepository) GetByID(id string) (*domain.News, error) {
 	return &news, nil
 }
 
-func (r *newsRepository) GetAll(page, limit int) ([]*domain.News, int64, error) {
+func (r *newsRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
 	skip := (page - 1) * limit
+	filter := listFilter(bson.M{}, lang)
 	opts := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(limit)).
 		SetSort(bson.M{"created_at": -1})
 
-	cursor, err := r.collection.Find(context.Background(), bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var news []*domain.News
-	if err = cursor.All(context.Background(), &news); err != nil {
+	if err = cursor.All(ctx, &news); err != nil {
 		return nil, 0, err
 	}
 
-	total, err := r.collection.CountDocuments(context.Background(), bson.M{})
+	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -92,7 +130,7 @@ func (r *newsRepository) GetAll(page, limit int) ([]*domain.News, int64, error)
 	return news, total, nil
 }
 
-func (r *newsRepository) Update(news *domain.News) error {
+func (r *newsRepository) Update(ctx context.Context, news *domain.News) error {
 	news.UpdatedAt = time.Now()
 
 	update := bson.M{
@@ -104,52 +142,155 @@ func (r *newsRepository) Update(news *domain.News) error {
 	}
 
 	_, err := r.collection.UpdateOne(
-		context.Background(),
+		ctx,
 		bson.M{"_id": news.ID},
 		update,
 	)
 	return err
 }
 
-func (r *newsRepository) Delete(id string) error {
+func (r *newsRepository) Delete(ctx context.Context, id string) error {
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.collection.DeleteOne(context.Background(), bson.M{"_id": objectID})
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
 	return err
 }
 
-func (r *newsRepository) Search(query string, page, limit int) ([]*domain.News, int64, error) {
+// Search uses the text index, stemming the query like the articles of lang
+// are stemmed. Results are ordered by relevance, then newest first.
+func (r *newsRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
 	skip := (page - 1) * limit
-	filter := bson.M{
-		"$or": []bson.M{
-			{"title": bson.M{"$regex": query, "$options": "i"}},
-			{"content": bson.M{"$regex": query, "$options": "i"}},
-		},
+	text := bson.M{"$search": query}
+	if lang != "" {
+		text["$language"] = textLanguage(lang)
 	}
+	filter := listFilter(bson.M{"$text": text}, lang)
 
 	opts := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(limit)).
-		SetSort(bson.M{"created_at": -1})
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}})
 
-	cursor, err := r.collection.Find(context.Background(), filter, opts)
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer cursor.Close(context.Background())
+	defer cursor.Close(ctx)
 
 	var news []*domain.News
-	if err = cursor.All(context.Background(), &news); err != nil {
+	if err = cursor.All(ctx, &news); err != nil {
 		return nil, 0, err
 	}
 
-	total, err := r.collection.CountDocuments(context.Background(), filter)
+	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	return news, total, nil
 }
+
+func (r *newsRepository) Insert(ctx context.Context, news *domain.News) error {
+	now := time.Now()
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+
+	result, err := r.collection.InsertOne(ctx, document(news))
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		news.ID = oid
+	}
+	return nil
+}
+
+func (r *newsRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	now := time.Now()
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+
+	filter := bson.M{"_id": news.ID}
+	if news.ID.IsZero() {
+		filter = bson.M{"slug": news.Slug}
+	}
+
+	result, err := r.collection.ReplaceOne(ctx, filter, document(news), options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, domain.ErrConflict
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if oid, ok := result.UpsertedID.(primitive.ObjectID); ok {
+		news.ID = oid
+	} else if news.ID.IsZero() {
+		// Replaced by slug, keeping the stored ID
+		var stored struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		err := r.collection.FindOne(ctx, filter, options.FindOne().SetProjection(bson.M{"_id": 1})).Decode(&stored)
+		if err != nil {
+			return false, err
+		}
+		news.ID = stored.ID
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+func (r *newsRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var news domain.News
+		if err := cursor.Decode(&news); err != nil {
+			return err
+		}
+		if err := fn(&news); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *newsRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	news, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group := news.TranslationGroup()
+	cursor, err := r.collection.Find(ctx,
+		bson.M{"$or": bson.A{bson.M{"_id": group}, bson.M{"translation_of": group}}},
+		options.Find().SetSort(bson.M{"language": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var translations []*domain.News
+	if err := cursor.All(ctx, &translations); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"news_service/internal/domain"
+	"news_service/internal/repository/repotest"
 )
 
 func setupTestDB(t *testing.T) (*mongo.Client, func()) {
@@ -29,147 +30,56 @@ func setupTestDB(t *testing.T) (*mongo.Client, func()) {
 	}
 }
 
-func TestNewsRepository_Create(t *testing.T) {
-	client, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	repo := NewNewsRepository(client, "test_news_service")
-
-	news := &domain.News{
-		Title:   "Test News",
-		Content: "Test Content",
-	}
-
-	err := repo.Create(news)
-	require.NoError(t, err)
-	assert.NotEmpty(t, news.ID)
-	assert.NotZero(t, news.CreatedAt)
-	assert.NotZero(t, news.UpdatedAt)
-}
-
-func TestNewsRepository_GetByID(t *testing.T) {
-	client, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	repo := NewNewsRepository(client, "test_news_service")
+func TestNewsRepository_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) domain.NewsRepository {
+		client, cleanup := setupTestDB(t)
+		t.Cleanup(cleanup)
 
-	// Create test news
-	news := &domain.News{
-		Title:   "Test News",
-		Content: "Test Content",
-	}
-	err := repo.Create(news)
-	require.NoError(t, err)
-
-	// Test getting the news
-	retrieved, err := repo.GetByID(news.ID.Hex())
-	require.NoError(t, err)
-	assert.Equal(t, news.ID, retrieved.ID)
-	assert.Equal(t, news.Title, retrieved.Title)
-	assert.Equal(t, news.Content, retrieved.Content)
-}
-
-func TestNewsRepository_GetAll(t *testing.T) {
-	client, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	repo := NewNewsRepository(client, "test_news_service")
-
-	// Create multiple test news
-	for i := 0; i < 15; i++ {
-		news := &domain.News{
-			Title:   "Test News " + string(rune('A'+i)),
-			Content: "Test Content " + string(rune('A'+i)),
-		}
-		err := repo.Create(news)
+		_, err := EnsureIndexes(context.Background(), client, "test_news_service")
 		require.NoError(t, err)
-	}
-
-	// Test getting all news with pagination
-	news, total, err := repo.GetAll(1, 10)
-	require.NoError(t, err)
-	assert.Equal(t, int64(15), total)
-	assert.Len(t, news, 10)
-
-	// Test second page
-	news, total, err = repo.GetAll(2, 10)
-	require.NoError(t, err)
-	assert.Equal(t, int64(15), total)
-	assert.Len(t, news, 5)
+		return NewNewsRepository(client, "test_news_service")
+	})
 }
 
-func TestNewsRepository_Update(t *testing.T) {
+func TestNewsRepository_Languages(t *testing.T) {
 	client, cleanup := setupTestDB(t)
 	defer cleanup()
 
+	ctx := context.Background()
+	_, err := EnsureIndexes(ctx, client, "test_news_service")
+	require.NoError(t, err)
 	repo := NewNewsRepository(client, "test_news_service")
 
-	// Create test news
-	news := &domain.News{
-		Title:   "Test News",
-		Content: "Test Content",
-	}
-	err := repo.Create(news)
-	require.NoError(t, err)
+	original := &domain.News{Title: "Elections announced", Content: "The elections are announced", Language: "en", Status: domain.StatusPublished}
+	require.NoError(t, repo.Create(ctx, original))
+	group := original.ID
+	translation := &domain.News{Title: "Оголошено вибори", Content: "Вибори оголошено сьогодні", Language: "uk", TranslationOf: &group, Status: domain.StatusPublished}
+	require.NoError(t, repo.Create(ctx, translation))
 
-	// Update the news
-	news.Title = "Updated Title"
-	news.Content = "Updated Content"
-	err = repo.Update(news)
+	news, total, err := repo.GetAll(ctx, "uk", 1, 10)
 	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, translation.ID, news[0].ID)
 
-	// Verify the update
-	retrieved, err := repo.GetByID(news.ID.Hex())
+	// English queries are stemmed, "election" finds "elections"
+	news, _, err = repo.Search(ctx, "en", "election", 1, 10)
 	require.NoError(t, err)
-	assert.Equal(t, "Updated Title", retrieved.Title)
-	assert.Equal(t, "Updated Content", retrieved.Content)
-}
-
-func TestNewsRepository_Delete(t *testing.T) {
-	client, cleanup := setupTestDB(t)
-	defer cleanup()
+	require.Len(t, news, 1)
+	assert.Equal(t, original.ID, news[0].ID)
 
-	repo := NewNewsRepository(client, "test_news_service")
-
-	// Create test news
-	news := &domain.News{
-		Title:   "Test News",
-		Content: "Test Content",
-	}
-	err := repo.Create(news)
+	news, _, err = repo.Search(ctx, "uk", "вибори", 1, 10)
 	require.NoError(t, err)
+	require.Len(t, news, 1)
+	assert.Equal(t, translation.ID, news[0].ID)
 
-	// Delete the news
-	err = repo.Delete(news.ID.Hex())
+	translations, err := repo.Translations(ctx, translation.ID.Hex())
 	require.NoError(t, err)
+	require.Len(t, translations, 2)
+	assert.Equal(t, "en", translations[0].Language)
+	assert.Equal(t, "uk", translations[1].Language)
 
-	// Verify deletion
-	_, err = repo.GetByID(news.ID.Hex())
-	assert.Error(t, err)
+	// A second Ukrainian variant is rejected
+	duplicate := &domain.News{Title: "Вибори", Content: "Ще одна версія", Language: "uk", TranslationOf: &group}
+	assert.Error(t, repo.Create(ctx, duplicate))
 }
 
-func TestNewsRepository_Search(t *testing.T) {
-	client, cleanup := setupTestDB(t)
-	defer cleanup()
-
-	repo := NewNewsRepository(client, "test_news_service")
-
-	// Create test news
-	news := []*domain.News{
-		{Title: "Golang News", Content: "Go programming language"},
-		{Title: "Python News", Content: "Python programming language"},
-		{Title: "Java News", Content: "Java programming language"},
-	}
-
-	for _, n := range news {
-		err := repo.Create(n)
-		require.NoError(t, err)
-	}
-
-	// Test search
-	results, total, err := repo.Search("golang", 1, 10)
-	require.NoError(t, err)
-	assert.Equal(t, int64(1), total)
-	assert.Len(t, results, 1)
-	assert.Equal(t, "Golang News", results[0].Title)
-}
@@ -0,0 +1,191 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/domain"
+	"news_service/internal/events"
+)
+
+const (
+	outboxCollection = "outbox"
+	leaseCollection  = "outbox_lease"
+	leaseID          = "dispatcher"
+	// outboxRetention is how long delivered events are kept for inspection
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// outboxIndexes serve the pending query and expire delivered events
+var outboxIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "delivered_at", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("delivered_at_id"),
+	},
+	{
+		Keys: bson.D{{Key: "delivered_at", Value: 1}},
+		Options: options.Index().
+			SetName("delivered_at_ttl").
+			SetExpireAfterSeconds(int32(outboxRetention.Seconds())),
+	},
+}
+
+type outboxDocument struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	Type          string             `bson:"type"`
+	ArticleID     string             `bson:"article_id"`
+	Article       *domain.News       `bson:"article,omitempty"`
+	OccurredAt    time.Time          `bson:"occurred_at"`
+	DeliveredTo   []string           `bson:"delivered_to"`
+	Attempts      int                `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	LastError     string             `bson:"last_error,omitempty"`
+	DeliveredAt   *time.Time         `bson:"delivered_at"`
+	// FailedAt is set on events given up on, which do not expire
+	FailedAt *time.Time `bson:"failed_at,omitempty"`
+}
+
+// Outbox stores domain events in MongoDB. Appending with a context from
+// Transactor.WithinTransaction commits the events with the article change.
+type Outbox struct {
+	collection *mongo.Collection
+	leases     *mongo.Collection
+}
+
+var (
+	_ domain.Outbox = (*Outbox)(nil)
+	_ events.Store  = (*Outbox)(nil)
+)
+
+// NewOutbox creates an outbox in the given database
+func NewOutbox(client *mongo.Client, database string) *Outbox {
+	db := client.Database(database)
+	return &Outbox{
+		collection: db.Collection(outboxCollection),
+		leases:     db.Collection(leaseCollection),
+	}
+}
+
+func (o *Outbox) Append(ctx context.Context, evts ...domain.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(evts))
+	for i, event := range evts {
+		id, err := primitive.ObjectIDFromHex(event.ID)
+		if err != nil {
+			return fmt.Errorf("event id %q: %w", event.ID, err)
+		}
+		docs[i] = outboxDocument{
+			ID:            id,
+			Type:          event.Type,
+			ArticleID:     event.ArticleID,
+			Article:       event.Article,
+			OccurredAt:    event.OccurredAt,
+			DeliveredTo:   []string{},
+			NextAttemptAt: event.OccurredAt,
+		}
+	}
+
+	_, err := o.collection.InsertMany(ctx, docs)
+	return err
+}
+
+func (o *Outbox) Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": leaseID,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(ttl)}}
+
+	_, err := o.leases.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Held by someone else
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (o *Outbox) Pending(ctx context.Context, after string, limit int) ([]events.Record, error) {
+	filter := bson.M{"delivered_at": nil, "failed_at": nil}
+	if after != "" {
+		oid, err := primitive.ObjectIDFromHex(after)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": oid}
+	}
+
+	cursor, err := o.collection.Find(ctx, filter,
+		options.Find().SetSort(bson.M{"_id": 1}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []outboxDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	records := make([]events.Record, len(docs))
+	for i, doc := range docs {
+		records[i] = events.Record{
+			Event: domain.Event{
+				ID:         doc.ID.Hex(),
+				Type:       doc.Type,
+				ArticleID:  doc.ArticleID,
+				Article:    doc.Article,
+				OccurredAt: doc.OccurredAt,
+			},
+			DeliveredTo:   doc.DeliveredTo,
+			Attempts:      doc.Attempts,
+			NextAttemptAt: doc.NextAttemptAt,
+		}
+	}
+	return records, nil
+}
+
+func (o *Outbox) Delivered(ctx context.Context, id, subscriber string) error {
+	return o.update(ctx, id, bson.M{"$addToSet": bson.M{"delivered_to": subscriber}})
+}
+
+func (o *Outbox) Complete(ctx context.Context, id string) error {
+	return o.update(ctx, id, bson.M{"$set": bson.M{"delivered_at": time.Now()}})
+}
+
+func (o *Outbox) Retry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	return o.update(ctx, id, bson.M{"$set": bson.M{
+		"attempts":        attempts,
+		"next_attempt_at": next,
+		"last_error":      lastErr,
+	}})
+}
+
+func (o *Outbox) Fail(ctx context.Context, id string, attempts int, lastErr string) error {
+	return o.update(ctx, id, bson.M{"$set": bson.M{
+		"attempts":   attempts,
+		"last_error": lastErr,
+		"failed_at":  time.Now(),
+	}})
+}
+
+func (o *Outbox) update(ctx context.Context, id string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = o.collection.UpdateByID(ctx, oid, update)
+	return err
+}
@@ -0,0 +1,54 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"news_service/internal/domain"
+)
+
+type transactor struct {
+	client *mongo.Client
+}
+
+type directTransactor struct{}
+
+// NewTransactor returns a Transactor backed by MongoDB sessions. Transactions
+// need a replica set or sharded cluster; on a standalone server the returned
+// Transactor runs fn without one and supported is false.
+func NewTransactor(ctx context.Context, client *mongo.Client) (_ domain.Transactor, supported bool, err error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, false, fmt.Errorf("detect deployment: %w", err)
+	}
+
+	if hello.SetName == "" && hello.Msg != "isdbgrid" {
+		return directTransactor{}, false, nil
+	}
+	return &transactor{client: client}, true, nil
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := t.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// The session context carries the transaction to every operation made
+	// with it, including those behind repository decorators
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+func (directTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
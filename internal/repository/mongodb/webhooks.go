@@ -0,0 +1,352 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"news_service/internal/webhook"
+)
+
+const (
+	endpointsCollection  = "webhook_endpoints"
+	deliveriesCollection = "webhook_deliveries"
+	// deliveryRetention is how long the delivery log is kept
+	deliveryRetention = 30 * 24 * time.Hour
+)
+
+// deliveryIndexes make enqueueing idempotent, serve the claim and log queries
+// and expire old deliveries
+var deliveryIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "endpoint_id", Value: 1}, {Key: "event_id", Value: 1}},
+		Options: options.Index().SetName("endpoint_id_event_id_unique").SetUnique(true),
+	},
+	{
+		Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+		Options: options.Index().SetName("status_next_attempt_at"),
+	},
+	{
+		Keys:    bson.D{{Key: "endpoint_id", Value: 1}, {Key: "_id", Value: -1}},
+		Options: options.Index().SetName("endpoint_id_id_desc"),
+	},
+	{
+		Keys: bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().
+			SetName("created_at_ttl").
+			SetExpireAfterSeconds(int32(deliveryRetention.Seconds())),
+	},
+}
+
+type endpointDocument struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	URL         string             `bson:"url"`
+	Description string             `bson:"description,omitempty"`
+	Events      []string           `bson:"events"`
+	Secret      string             `bson:"secret"`
+	Active      bool               `bson:"active"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
+
+func (d *endpointDocument) endpoint() *webhook.Endpoint {
+	return &webhook.Endpoint{
+		ID:          d.ID.Hex(),
+		URL:         d.URL,
+		Description: d.Description,
+		Events:      d.Events,
+		Secret:      d.Secret,
+		Active:      d.Active,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+}
+
+type deliveryDocument struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	EndpointID    primitive.ObjectID `bson:"endpoint_id"`
+	EventID       string             `bson:"event_id"`
+	EventType     string             `bson:"event_type"`
+	Payload       []byte             `bson:"payload"`
+	Status        string             `bson:"status"`
+	Attempts      []webhook.Attempt  `bson:"attempts"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at"`
+	CreatedAt     time.Time          `bson:"created_at"`
+}
+
+func (d *deliveryDocument) delivery() *webhook.Delivery {
+	return &webhook.Delivery{
+		ID:            d.ID.Hex(),
+		EndpointID:    d.EndpointID.Hex(),
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Payload:       d.Payload,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+	}
+}
+
+// WebhookStore keeps webhook endpoints and deliveries in MongoDB
+type WebhookStore struct {
+	endpoints  *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+var _ webhook.Store = (*WebhookStore)(nil)
+
+// NewWebhookStore creates a webhook store in the given database
+func NewWebhookStore(client *mongo.Client, database string) *WebhookStore {
+	db := client.Database(database)
+	return &WebhookStore{
+		endpoints:  db.Collection(endpointsCollection),
+		deliveries: db.Collection(deliveriesCollection),
+	}
+}
+
+func (s *WebhookStore) CreateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	now := time.Now()
+	doc := endpointDocument{
+		ID:          primitive.NewObjectID(),
+		URL:         endpoint.URL,
+		Description: endpoint.Description,
+		Events:      nonNil(endpoint.Events),
+		Secret:      endpoint.Secret,
+		Active:      endpoint.Active,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := s.endpoints.InsertOne(ctx, doc); err != nil {
+		return err
+	}
+
+	endpoint.ID = doc.ID.Hex()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+	return nil
+}
+
+func (s *WebhookStore) GetEndpoint(ctx context.Context, id string) (*webhook.Endpoint, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, webhook.ErrNotFound
+	}
+
+	var doc endpointDocument
+	if err := s.endpoints.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, webhook.ErrNotFound
+		}
+		return nil, err
+	}
+	return doc.endpoint(), nil
+}
+
+func (s *WebhookStore) ListEndpoints(ctx context.Context) ([]*webhook.Endpoint, error) {
+	cursor, err := s.endpoints.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []endpointDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]*webhook.Endpoint, len(docs))
+	for i := range docs {
+		endpoints[i] = docs[i].endpoint()
+	}
+	return endpoints, nil
+}
+
+func (s *WebhookStore) UpdateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	oid, err := primitive.ObjectIDFromHex(endpoint.ID)
+	if err != nil {
+		return webhook.ErrNotFound
+	}
+
+	endpoint.UpdatedAt = time.Now()
+	result, err := s.endpoints.UpdateByID(ctx, oid, bson.M{"$set": bson.M{
+		"url":         endpoint.URL,
+		"description": endpoint.Description,
+		"events":      nonNil(endpoint.Events),
+		"secret":      endpoint.Secret,
+		"active":      endpoint.Active,
+		"updated_at":  endpoint.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (s *WebhookStore) DeleteEndpoint(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return webhook.ErrNotFound
+	}
+
+	result, err := s.endpoints.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return webhook.ErrNotFound
+	}
+	_, err = s.deliveries.DeleteMany(ctx, bson.M{"endpoint_id": oid})
+	return err
+}
+
+func (s *WebhookStore) Enqueue(ctx context.Context, deliveries ...*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(deliveries))
+	for i, delivery := range deliveries {
+		id, err := primitive.ObjectIDFromHex(delivery.ID)
+		if err != nil {
+			return err
+		}
+		endpointID, err := primitive.ObjectIDFromHex(delivery.EndpointID)
+		if err != nil {
+			return err
+		}
+		docs[i] = deliveryDocument{
+			ID:            id,
+			EndpointID:    endpointID,
+			EventID:       delivery.EventID,
+			EventType:     delivery.EventType,
+			Payload:       delivery.Payload,
+			Status:        delivery.Status,
+			Attempts:      []webhook.Attempt{},
+			NextAttemptAt: delivery.NextAttemptAt,
+			CreatedAt:     delivery.CreatedAt,
+		}
+	}
+
+	_, err := s.deliveries.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		// Deliveries queued by an earlier attempt at the same event
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongo.IsDuplicateKeyError(writeErr) {
+				return err
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+func (s *WebhookStore) Claim(ctx context.Context, lease time.Duration) (*webhook.Delivery, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status":          webhook.StatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_attempt_at": 1}).
+		SetReturnDocument(options.After)
+
+	var doc deliveryDocument
+	if err := s.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc.delivery(), nil
+}
+
+func (s *WebhookStore) RecordAttempt(ctx context.Context, id string, attempt webhook.Attempt, status string, next time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return webhook.ErrNotFound
+	}
+	_, err = s.deliveries.UpdateByID(ctx, oid, bson.M{
+		"$push": bson.M{"attempts": attempt},
+		"$set":  bson.M{"status": status, "next_attempt_at": next},
+	})
+	return err
+}
+
+func (s *WebhookStore) GetDelivery(ctx context.Context, id string) (*webhook.Delivery, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, webhook.ErrNotFound
+	}
+
+	var doc deliveryDocument
+	if err := s.deliveries.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, webhook.ErrNotFound
+		}
+		return nil, err
+	}
+	return doc.delivery(), nil
+}
+
+func (s *WebhookStore) ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*webhook.Delivery, error) {
+	oid, err := primitive.ObjectIDFromHex(endpointID)
+	if err != nil {
+		return nil, webhook.ErrNotFound
+	}
+
+	cursor, err := s.deliveries.Find(ctx,
+		bson.M{"endpoint_id": oid},
+		options.Find().SetSort(bson.M{"_id": -1}).SetLimit(int64(limit)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []deliveryDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*webhook.Delivery, len(docs))
+	for i := range docs {
+		deliveries[i] = docs[i].delivery()
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookStore) Redeliver(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return webhook.ErrNotFound
+	}
+
+	result, err := s.deliveries.UpdateByID(ctx, oid, bson.M{"$set": bson.M{
+		"status":          webhook.StatusPending,
+		"next_attempt_at": time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+// nonNil stores empty lists as arrays rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
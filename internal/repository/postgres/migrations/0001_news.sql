@@ -0,0 +1,30 @@
+-- IDs are MongoDB style object IDs in hex, which sort by creation time under
+-- the C collation
+CREATE TABLE news (
+    id             text COLLATE "C" PRIMARY KEY,
+    title          text NOT NULL,
+    content        text NOT NULL,
+    slug           text,
+    status         text NOT NULL DEFAULT '',
+    tags           text[] NOT NULL DEFAULT '{}',
+    language       text NOT NULL DEFAULT '',
+    translation_of text COLLATE "C",
+    published_at   timestamptz,
+    created_at     timestamptz NOT NULL,
+    updated_at     timestamptz NOT NULL,
+    -- English is stemmed, other languages are only tokenized. Titles weigh
+    -- ten times as much as content, as in the MongoDB text index.
+    search tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector(CASE WHEN language = 'en' THEN 'english'::regconfig ELSE 'simple'::regconfig END, title), 'A') ||
+        setweight(to_tsvector(CASE WHEN language = 'en' THEN 'english'::regconfig ELSE 'simple'::regconfig END, content), 'D')
+    ) STORED
+);
+
+CREATE INDEX news_created_at ON news (created_at DESC);
+CREATE UNIQUE INDEX news_slug_unique ON news (slug);
+CREATE INDEX news_search ON news USING gin (search);
+CREATE INDEX news_language_created_at ON news (language, created_at DESC);
+-- One variant per language of an article
+CREATE UNIQUE INDEX news_translation_of_language_unique ON news (translation_of, language)
+    WHERE translation_of IS NOT NULL;
+CREATE INDEX news_status_published_at ON news (status, published_at DESC);
@@ -0,0 +1,21 @@
+CREATE TABLE outbox (
+    id              text COLLATE "C" PRIMARY KEY,
+    type            text NOT NULL,
+    article_id      text NOT NULL,
+    article         jsonb,
+    occurred_at     timestamptz NOT NULL,
+    delivered_to    text[] NOT NULL DEFAULT '{}',
+    attempts        integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    last_error      text NOT NULL DEFAULT '',
+    delivered_at    timestamptz
+);
+
+CREATE INDEX outbox_pending ON outbox (id) WHERE delivered_at IS NULL;
+CREATE INDEX outbox_delivered_at ON outbox (delivered_at) WHERE delivered_at IS NOT NULL;
+
+CREATE TABLE outbox_lease (
+    id         text PRIMARY KEY,
+    owner      text NOT NULL,
+    expires_at timestamptz NOT NULL
+);
@@ -0,0 +1,27 @@
+CREATE TABLE webhook_endpoints (
+    id          text COLLATE "C" PRIMARY KEY,
+    url         text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    events      text[] NOT NULL DEFAULT '{}',
+    secret      text NOT NULL,
+    active      boolean NOT NULL,
+    created_at  timestamptz NOT NULL,
+    updated_at  timestamptz NOT NULL
+);
+
+CREATE TABLE webhook_deliveries (
+    id              text COLLATE "C" PRIMARY KEY,
+    endpoint_id     text COLLATE "C" NOT NULL REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+    event_id        text NOT NULL,
+    event_type      text NOT NULL,
+    payload         bytea NOT NULL,
+    status          text NOT NULL,
+    attempts        jsonb NOT NULL DEFAULT '[]',
+    next_attempt_at timestamptz NOT NULL,
+    created_at      timestamptz NOT NULL,
+    UNIQUE (endpoint_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_endpoint_id ON webhook_deliveries (endpoint_id, id DESC);
+CREATE INDEX webhook_deliveries_created_at ON webhook_deliveries (created_at);
@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// eachBatchSize is how many articles Each reads at a time
+const eachBatchSize = 100
+
+// newsColumns are the columns scanned by scanNews, in order
+const newsColumns = `id, title, content, coalesce(slug, ''), status, tags, language,
+	translation_of, published_at, created_at, updated_at`
+
+type newsRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewNewsRepository creates a new instance of PostgreSQL news repository
+func NewNewsRepository(pool *pgxpool.Pool) domain.NewsRepository {
+	return &newsRepository{pool: pool}
+}
+
+func scanNews(row pgx.Row) (*domain.News, error) {
+	var (
+		news          domain.News
+		id            string
+		translationOf *string
+	)
+	err := row.Scan(&id, &news.Title, &news.Content, &news.Slug, &news.Status, &news.Tags, &news.Language,
+		&translationOf, &news.PublishedAt, &news.CreatedAt, &news.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if news.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("news id %q: %w", id, err)
+	}
+	if translationOf != nil {
+		group, err := primitive.ObjectIDFromHex(*translationOf)
+		if err != nil {
+			return nil, fmt.Errorf("translation_of %q: %w", *translationOf, err)
+		}
+		news.TranslationOf = &group
+	}
+	if len(news.Tags) == 0 {
+		news.Tags = nil
+	}
+	return &news, nil
+}
+
+func (r *newsRepository) queryNews(ctx context.Context, sql string, args ...any) ([]*domain.News, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var news []*domain.News
+	for rows.Next() {
+		n, err := scanNews(rows)
+		if err != nil {
+			return nil, err
+		}
+		news = append(news, n)
+	}
+	return news, rows.Err()
+}
+
+// values returns the stored columns of news, in the order of insertColumns
+func values(news *domain.News) []any {
+	var slug, translationOf *string
+	if news.Slug != "" {
+		slug = &news.Slug
+	}
+	if news.TranslationOf != nil {
+		group := news.TranslationOf.Hex()
+		translationOf = &group
+	}
+	return []any{
+		news.ID.Hex(), news.Title, news.Content, slug, news.Status, nonNil(news.Tags), news.Language,
+		translationOf, news.PublishedAt, news.CreatedAt, news.UpdatedAt,
+	}
+}
+
+const insertColumns = `id, title, content, slug, status, tags, language,
+	translation_of, published_at, created_at, updated_at`
+
+const insertNews = `INSERT INTO news (` + insertColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+func (r *newsRepository) Create(ctx context.Context, news *domain.News) error {
+	if news.ID.IsZero() {
+		news.ID = primitive.NewObjectID()
+	}
+	news.CreatedAt = time.Now()
+	news.UpdatedAt = time.Now()
+
+	_, err := conn(ctx, r.pool).Exec(ctx, insertNews, values(news)...)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+func (r *newsRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	news, err := scanNews(conn(ctx, r.pool).QueryRow(ctx,
+		`SELECT `+newsColumns+` FROM news WHERE id = $1`, objectID.Hex()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return news, err
+}
+
+// listFilter restricts a query to published articles, in lang unless it is
+// empty
+func listFilter(where []string, args []any, lang string) ([]string, []any) {
+	args = append(args, domain.StatusPublished)
+	where = append(where, fmt.Sprintf("status = $%d", len(args)))
+	if lang != "" {
+		args = append(args, lang)
+		where = append(where, fmt.Sprintf("language = $%d", len(args)))
+	}
+	return where, args
+}
+
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(where, " AND ")
+}
+
+func (r *newsRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	where, args := listFilter(nil, nil, lang)
+	filter := whereClause(where)
+
+	news, err := r.queryNews(ctx,
+		fmt.Sprintf(`SELECT %s FROM news%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+			newsColumns, filter, len(args)+1, len(args)+2),
+		append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := conn(ctx, r.pool).QueryRow(ctx, `SELECT count(*) FROM news`+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return news, total, nil
+}
+
+func (r *newsRepository) Update(ctx context.Context, news *domain.News) error {
+	news.UpdatedAt = time.Now()
+
+	_, err := conn(ctx, r.pool).Exec(ctx,
+		`UPDATE news SET title = $2, content = $3, updated_at = $4 WHERE id = $1`,
+		news.ID.Hex(), news.Title, news.Content, news.UpdatedAt)
+	return err
+}
+
+func (r *newsRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.pool).Exec(ctx, `DELETE FROM news WHERE id = $1`, objectID.Hex())
+	return err
+}
+
+// Search matches the query against the search column like MongoDB text
+// search does, see textQuery. Results are ordered by relevance, then newest
+// first.
+func (r *newsRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	var args []any
+	tsquery := textQuery(query, textConfig(lang), &args)
+	if tsquery == "" {
+		return nil, 0, nil
+	}
+	where, args := listFilter([]string{"search @@ terms.q"}, args, lang)
+	from := fmt.Sprintf(` FROM news, (SELECT %s AS q) AS terms%s`, tsquery, whereClause(where))
+
+	news, err := r.queryNews(ctx,
+		fmt.Sprintf(`SELECT %s%s ORDER BY ts_rank(search, terms.q) DESC, created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+			newsColumns, from, len(args)+1, len(args)+2),
+		append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := conn(ctx, r.pool).QueryRow(ctx, `SELECT count(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return news, total, nil
+}
+
+func (r *newsRepository) Insert(ctx context.Context, news *domain.News) error {
+	now := time.Now()
+	if news.ID.IsZero() {
+		news.ID = primitive.NewObjectID()
+	}
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+
+	_, err := conn(ctx, r.pool).Exec(ctx, insertNews, values(news)...)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+func (r *newsRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	now := time.Now()
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+
+	// Without an ID the article is matched by slug and keeps its stored ID
+	target := "id"
+	if news.ID.IsZero() {
+		target = "slug"
+		news.ID = primitive.NewObjectID()
+	}
+
+	var (
+		id       string
+		inserted bool
+	)
+	err := conn(ctx, r.pool).QueryRow(ctx, insertNews+` ON CONFLICT (`+target+`) DO UPDATE SET
+		title = EXCLUDED.title, content = EXCLUDED.content, slug = EXCLUDED.slug,
+		status = EXCLUDED.status, tags = EXCLUDED.tags, language = EXCLUDED.language,
+		translation_of = EXCLUDED.translation_of, published_at = EXCLUDED.published_at,
+		created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at
+		RETURNING id, xmax = 0`, values(news)...).Scan(&id, &inserted)
+	if isUniqueViolation(err) {
+		return false, domain.ErrConflict
+	}
+	if err != nil {
+		return false, err
+	}
+
+	news.ID, err = primitive.ObjectIDFromHex(id)
+	return inserted, err
+}
+
+func (r *newsRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	// Reading in batches frees the connection while fn runs
+	after := ""
+	for {
+		batch, err := r.queryNews(ctx,
+			`SELECT `+newsColumns+` FROM news WHERE id > $1 ORDER BY id LIMIT $2`, after, eachBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, news := range batch {
+			if err := fn(news); err != nil {
+				return err
+			}
+		}
+		if len(batch) < eachBatchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].ID.Hex()
+	}
+}
+
+func (r *newsRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	return r.batch(ctx, ids, func(q querier, ids []string) (pgx.Rows, error) {
+		return q.Query(ctx, `DELETE FROM news WHERE id = ANY($1) RETURNING id`, ids)
+	})
+}
+
+func (r *newsRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	return r.batch(ctx, ids, func(q querier, ids []string) (pgx.Rows, error) {
+		// Append the tags an article does not have yet, keeping its order
+		return q.Query(ctx, `UPDATE news SET
+			updated_at = $2,
+			status = CASE WHEN $3::text = '' THEN status ELSE $3::text END,
+			published_at = CASE WHEN $3::text = 'published' THEN coalesce(published_at, $2) ELSE published_at END,
+			tags = tags || ARRAY(
+				SELECT tag FROM unnest($4::text[]) WITH ORDINALITY AS added(tag, position)
+				WHERE tag <> ALL(tags) ORDER BY position
+			)
+			WHERE id = ANY($1) RETURNING id`,
+			ids, time.Now(), update.Status, update.Tags)
+	})
+}
+
+// batch runs a statement over the valid IDs that returns the IDs it changed,
+// and reports the outcome for every distinct ID, including those that are
+// invalid or do not exist
+func (r *newsRepository) batch(ctx context.Context, ids []string, run func(querier, []string) (pgx.Rows, error)) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, 0, len(ids))
+	index := make(map[string]int, len(ids))
+	seen := make(map[string]bool, len(ids))
+	var valid []string
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			results = append(results, domain.BatchResult{ID: id, Error: "invalid id"})
+			continue
+		}
+		index[oid.Hex()] = len(results)
+		valid = append(valid, oid.Hex())
+		results = append(results, domain.BatchResult{ID: id, Error: domain.ErrNotFound.Error()})
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	rows, err := run(conn(ctx, r.pool), valid)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range changed {
+		i := index[id]
+		results[i] = domain.BatchResult{ID: results[i].ID, OK: true}
+	}
+	return results, nil
+}
+
+func (r *newsRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	news, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.queryNews(ctx,
+		`SELECT `+newsColumns+` FROM news WHERE id = $1 OR translation_of = $1 ORDER BY language`,
+		news.TranslationGroup().Hex())
+}
@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"news_service/internal/domain"
+	"news_service/internal/events"
+)
+
+const (
+	leaseID = "dispatcher"
+	// outboxRetention is how long delivered events are kept for inspection
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Outbox stores domain events in PostgreSQL. Appending with a context from
+// Transactor.WithinTransaction commits the events with the article change.
+type Outbox struct {
+	pool *pgxpool.Pool
+}
+
+var (
+	_ domain.Outbox = (*Outbox)(nil)
+	_ events.Store  = (*Outbox)(nil)
+)
+
+// NewOutbox creates an outbox in the database of pool
+func NewOutbox(pool *pgxpool.Pool) *Outbox {
+	return &Outbox{pool: pool}
+}
+
+func (o *Outbox) Append(ctx context.Context, evts ...domain.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, event := range evts {
+		batch.Queue(`INSERT INTO outbox (id, type, article_id, article, occurred_at, next_attempt_at)
+			VALUES ($1, $2, $3, $4, $5, $5)`,
+			event.ID, event.Type, event.ArticleID, event.Article, event.OccurredAt)
+	}
+	return conn(ctx, o.pool).SendBatch(ctx, batch).Close()
+}
+
+func (o *Outbox) Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	tag, err := o.pool.Exec(ctx, `INSERT INTO outbox_lease (id, owner, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE outbox_lease.owner = EXCLUDED.owner OR outbox_lease.expires_at < $4`,
+		leaseID, owner, now.Add(ttl), now)
+	if err != nil {
+		return false, err
+	}
+	// Nothing is written while someone else holds the lease
+	return tag.RowsAffected() == 1, nil
+}
+
+func (o *Outbox) Pending(ctx context.Context, after string, limit int) ([]events.Record, error) {
+	rows, err := o.pool.Query(ctx, `SELECT id, type, article_id, article, occurred_at,
+		delivered_to, attempts, next_attempt_at
+		FROM outbox WHERE delivered_at IS NULL AND failed_at IS NULL AND id > $1
+		ORDER BY id LIMIT $2`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []events.Record
+	for rows.Next() {
+		var record events.Record
+		err := rows.Scan(&record.Event.ID, &record.Event.Type, &record.Event.ArticleID, &record.Event.Article,
+			&record.Event.OccurredAt, &record.DeliveredTo, &record.Attempts, &record.NextAttemptAt)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (o *Outbox) Delivered(ctx context.Context, id, subscriber string) error {
+	_, err := o.pool.Exec(ctx, `UPDATE outbox SET delivered_to = array_append(delivered_to, $2)
+		WHERE id = $1 AND NOT ($2 = ANY(delivered_to))`, id, subscriber)
+	return err
+}
+
+func (o *Outbox) Complete(ctx context.Context, id string) error {
+	now := time.Now()
+	if _, err := o.pool.Exec(ctx, `UPDATE outbox SET delivered_at = $2 WHERE id = $1`, id, now); err != nil {
+		return err
+	}
+	// There is no TTL index as in MongoDB, expire old events as new ones
+	// are delivered
+	_, err := o.pool.Exec(ctx, `DELETE FROM outbox WHERE delivered_at < $1`, now.Add(-outboxRetention))
+	return err
+}
+
+func (o *Outbox) Retry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	_, err := o.pool.Exec(ctx, `UPDATE outbox SET attempts = $2, next_attempt_at = $3, last_error = $4
+		WHERE id = $1`, id, attempts, next, lastErr)
+	return err
+}
+
+// Fail keeps the event with its last error, like a delivered one it is no
+// longer pending but it is not expired
+func (o *Outbox) Fail(ctx context.Context, id string, attempts int, lastErr string) error {
+	_, err := o.pool.Exec(ctx, `UPDATE outbox SET attempts = $2, last_error = $3, failed_at = $4
+		WHERE id = $1`, id, attempts, lastErr, time.Now())
+	return err
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+func TestOutbox(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	outbox := NewOutbox(pool)
+	ctx := context.Background()
+
+	first := domain.NewEvent(domain.EventArticleCreated, "a", &domain.News{Title: "Created"})
+	second := domain.NewEvent(domain.EventArticleDeleted, "a", nil)
+	require.NoError(t, outbox.Append(ctx, first, second))
+
+	records, err := outbox.Pending(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, first.ID, records[0].Event.ID)
+	assert.Equal(t, "Created", records[0].Event.Article.Title)
+	assert.Nil(t, records[1].Event.Article)
+
+	next := time.Now().Add(time.Minute)
+	require.NoError(t, outbox.Delivered(ctx, first.ID, "webhooks"))
+	require.NoError(t, outbox.Delivered(ctx, first.ID, "webhooks"))
+	require.NoError(t, outbox.Retry(ctx, first.ID, 1, next, "boom"))
+	require.NoError(t, outbox.Complete(ctx, second.ID))
+
+	records, err = outbox.Pending(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []string{"webhooks"}, records[0].DeliveredTo)
+	assert.Equal(t, 1, records[0].Attempts)
+	assert.WithinDuration(t, next, records[0].NextAttemptAt, time.Second)
+
+	// Reading on after the first event, then giving up on it
+	third := domain.NewEvent(domain.EventArticleUpdated, "b", nil)
+	require.NoError(t, outbox.Append(ctx, third))
+	records, err = outbox.Pending(ctx, first.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, third.ID, records[0].Event.ID)
+
+	require.NoError(t, outbox.Fail(ctx, first.ID, 2, "boom"))
+	records, err = outbox.Pending(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, third.ID, records[0].Event.ID)
+}
+
+func TestOutbox_Acquire(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	outbox := NewOutbox(pool)
+	ctx := context.Background()
+
+	acquired, err := outbox.Acquire(ctx, "one", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// Renewing is allowed, taking over a live lease is not
+	acquired, err = outbox.Acquire(ctx, "one", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = outbox.Acquire(ctx, "two", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	// An expired lease can be taken over
+	_, err = outbox.Acquire(ctx, "one", -time.Second)
+	require.NoError(t, err)
+	acquired, err = outbox.Acquire(ctx, "two", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestTransactor(t *testing.T) {
+	pool, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	transactor := NewTransactor(pool)
+	repo := NewNewsRepository(pool)
+	outbox := NewOutbox(pool)
+
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		news := &domain.News{Title: "Rolled back", Content: "Rolled back content"}
+		if err := repo.Create(ctx, news); err != nil {
+			return err
+		}
+		if err := outbox.Append(ctx, domain.NewEvent(domain.EventArticleCreated, news.ID.Hex(), news)); err != nil {
+			return err
+		}
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+
+	_, total, err := repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	records, err := outbox.Pending(ctx, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+)
+
+// textConfigs maps article languages to the text search configuration they
+// are indexed with, see the search column. PostgreSQL has no Ukrainian
+// stemmer, so Ukrainian text is only tokenized.
+var textConfigs = map[string]string{
+	"en": "english",
+	"uk": "simple",
+}
+
+// textConfig returns the configuration a query in lang is parsed with.
+// Queries in every language are stemmed as English, like MongoDB does with
+// the default language of its text index.
+func textConfig(lang string) string {
+	if lang == "" {
+		return "english"
+	}
+	if config, ok := textConfigs[lang]; ok {
+		return config
+	}
+	return "simple"
+}
+
+// textQuery builds a tsquery expression with the semantics of a MongoDB text
+// search: it matches any of the terms, or every quoted phrase when there are
+// phrases, and none of the terms negated with a leading minus. The text is
+// passed as arguments appended to args. An empty result means the query can
+// match nothing.
+func textQuery(query, config string, args *[]any) string {
+	var terms, phrases, negated []string
+	for query != "" {
+		query = strings.TrimLeft(query, " \t\r\n")
+		switch {
+		case query == "":
+		case query[0] == '"':
+			phrase, rest, _ := strings.Cut(query[1:], `"`)
+			if strings.TrimSpace(phrase) != "" {
+				phrases = append(phrases, phrase)
+			}
+			query = rest
+		default:
+			end := strings.IndexAny(query, " \t\r\n\"")
+			if end < 0 {
+				end = len(query)
+			}
+			term := query[:end]
+			query = query[end:]
+			if strings.HasPrefix(term, "-") {
+				if term = term[1:]; term != "" {
+					negated = append(negated, term)
+				}
+				continue
+			}
+			terms = append(terms, term)
+		}
+	}
+
+	arg := func(function, text string) string {
+		*args = append(*args, text)
+		return fmt.Sprintf("%s('%s', $%d)", function, config, len(*args))
+	}
+
+	var positive []string
+	operator := " || "
+	if len(phrases) > 0 {
+		operator = " && "
+		for _, phrase := range phrases {
+			positive = append(positive, arg("phraseto_tsquery", phrase))
+		}
+	} else {
+		for _, term := range terms {
+			positive = append(positive, arg("plainto_tsquery", term))
+		}
+	}
+	if len(positive) == 0 {
+		return ""
+	}
+
+	expr := "(" + strings.Join(positive, operator) + ")"
+	for _, term := range negated {
+		expr += " && !!" + arg("plainto_tsquery", term)
+	}
+	return expr
+}
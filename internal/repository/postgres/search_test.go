@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+		args  []any
+	}{
+		{
+			query: "golang  release",
+			want:  "(plainto_tsquery('english', $1) || plainto_tsquery('english', $2))",
+			args:  []any{"golang", "release"},
+		},
+		{
+			query: `go "new release" -beta`,
+			want:  "(phraseto_tsquery('english', $1)) && !!plainto_tsquery('english', $2)",
+			args:  []any{"new release", "beta"},
+		},
+		{
+			query: `"go" "1.22`,
+			want:  "(phraseto_tsquery('english', $1) && phraseto_tsquery('english', $2))",
+			args:  []any{"go", "1.22"},
+		},
+		{query: "-beta", want: ""},
+		{query: `  "" - `, want: ""},
+	}
+
+	for _, tc := range cases {
+		var args []any
+		assert.Equal(t, tc.want, textQuery(tc.query, "english", &args), tc.query)
+		if tc.want != "" {
+			assert.Equal(t, tc.args, args, tc.query)
+		}
+	}
+}
+
+func TestTextConfig(t *testing.T) {
+	assert.Equal(t, "english", textConfig(""))
+	assert.Equal(t, "english", textConfig("en"))
+	assert.Equal(t, "simple", textConfig("uk"))
+}
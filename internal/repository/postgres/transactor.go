@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"news_service/internal/domain"
+)
+
+type transactor struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactor returns a Transactor backed by PostgreSQL transactions
+func NewTransactor(pool *pgxpool.Pool) domain.Transactor {
+	return &transactor{pool: pool}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Nested calls join the enclosing transaction
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	// The context carries the transaction to every statement made with it,
+	// including those behind repository decorators
+	return pgx.BeginFunc(ctx, t.pool, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/webhook"
+)
+
+// deliveryRetention is how long the delivery log is kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+const (
+	endpointColumns = `id, url, description, events, secret, active, created_at, updated_at`
+	deliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts,
+		next_attempt_at, created_at`
+)
+
+// WebhookStore keeps webhook endpoints and deliveries in PostgreSQL
+type WebhookStore struct {
+	pool *pgxpool.Pool
+}
+
+var _ webhook.Store = (*WebhookStore)(nil)
+
+// NewWebhookStore creates a webhook store in the database of pool
+func NewWebhookStore(pool *pgxpool.Pool) *WebhookStore {
+	return &WebhookStore{pool: pool}
+}
+
+func scanEndpoint(row pgx.Row) (*webhook.Endpoint, error) {
+	var endpoint webhook.Endpoint
+	err := row.Scan(&endpoint.ID, &endpoint.URL, &endpoint.Description, &endpoint.Events,
+		&endpoint.Secret, &endpoint.Active, &endpoint.CreatedAt, &endpoint.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoint.Events) == 0 {
+		endpoint.Events = nil
+	}
+	return &endpoint, nil
+}
+
+func scanDelivery(row pgx.Row) (*webhook.Delivery, error) {
+	var delivery webhook.Delivery
+	err := row.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.EventType,
+		&delivery.Payload, &delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (s *WebhookStore) CreateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	now := time.Now()
+	id := primitive.NewObjectID().Hex()
+	_, err := s.pool.Exec(ctx, `INSERT INTO webhook_endpoints (`+endpointColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)`,
+		id, endpoint.URL, endpoint.Description, nonNil(endpoint.Events), endpoint.Secret, endpoint.Active, now)
+	if err != nil {
+		return err
+	}
+
+	endpoint.ID = id
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+	return nil
+}
+
+func (s *WebhookStore) GetEndpoint(ctx context.Context, id string) (*webhook.Endpoint, error) {
+	return scanEndpoint(s.pool.QueryRow(ctx, `SELECT `+endpointColumns+` FROM webhook_endpoints WHERE id = $1`, id))
+}
+
+func (s *WebhookStore) ListEndpoints(ctx context.Context) ([]*webhook.Endpoint, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+endpointColumns+` FROM webhook_endpoints ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []*webhook.Endpoint
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, rows.Err()
+}
+
+func (s *WebhookStore) UpdateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	endpoint.UpdatedAt = time.Now()
+	tag, err := s.pool.Exec(ctx, `UPDATE webhook_endpoints SET
+		url = $2, description = $3, events = $4, secret = $5, active = $6, updated_at = $7
+		WHERE id = $1`,
+		endpoint.ID, endpoint.URL, endpoint.Description, nonNil(endpoint.Events), endpoint.Secret,
+		endpoint.Active, endpoint.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteEndpoint removes the endpoint, its deliveries go with it through the
+// foreign key
+func (s *WebhookStore) DeleteEndpoint(ctx context.Context, id string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (s *WebhookStore) Enqueue(ctx context.Context, deliveries ...*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, delivery := range deliveries {
+		// Deliveries queued by an earlier attempt at the same event are kept
+		batch.Queue(`INSERT INTO webhook_deliveries (`+deliveryColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6, '[]', $7, $8)
+			ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+			delivery.ID, delivery.EndpointID, delivery.EventID, delivery.EventType, delivery.Payload,
+			delivery.Status, delivery.NextAttemptAt, delivery.CreatedAt)
+	}
+	// There is no TTL index as in MongoDB, expire the log as it grows
+	batch.Queue(`DELETE FROM webhook_deliveries WHERE created_at < $1`, time.Now().Add(-deliveryRetention))
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
+func (s *WebhookStore) Claim(ctx context.Context, lease time.Duration) (*webhook.Delivery, error) {
+	now := time.Now()
+	delivery, err := scanDelivery(s.pool.QueryRow(ctx, `UPDATE webhook_deliveries SET next_attempt_at = $3
+		WHERE id = (
+			SELECT id FROM webhook_deliveries
+			WHERE status = $1 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		webhook.StatusPending, now, now.Add(lease)))
+	if errors.Is(err, webhook.ErrNotFound) {
+		return nil, nil
+	}
+	return delivery, err
+}
+
+func (s *WebhookStore) RecordAttempt(ctx context.Context, id string, attempt webhook.Attempt, status string, next time.Time) error {
+	attempts, err := json.Marshal([]webhook.Attempt{attempt})
+	if err != nil {
+		return err
+	}
+	_, err = s.pool.Exec(ctx, `UPDATE webhook_deliveries SET
+		attempts = attempts || $2::jsonb, status = $3, next_attempt_at = $4
+		WHERE id = $1`, id, string(attempts), status, next)
+	return err
+}
+
+func (s *WebhookStore) GetDelivery(ctx context.Context, id string) (*webhook.Delivery, error) {
+	return scanDelivery(s.pool.QueryRow(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+}
+
+func (s *WebhookStore) ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*webhook.Delivery, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE endpoint_id = $1 ORDER BY id DESC LIMIT $2`, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*webhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *WebhookStore) Redeliver(ctx context.Context, id string) error {
+	tag, err := s.pool.Exec(ctx, `UPDATE webhook_deliveries SET status = $2, next_attempt_at = $3
+		WHERE id = $1`, id, webhook.StatusPending, time.Now())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+// nonNil stores empty lists as empty arrays rather than NULL
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,489 @@
+// Package repotest checks that a domain.NewsRepository behaves like every
+// other backend does, so the service can rely on the interface alone.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// Factory returns an empty repository, with search ready to use. It is called
+// once per subtest and releases what it opens with t.Cleanup, or skips t when
+// the backend is not available.
+type Factory func(t *testing.T) domain.NewsRepository
+
+// Run runs the contract every NewsRepository must meet as subtests of t
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		fn   func(*testing.T, domain.NewsRepository)
+	}{
+		{"Create", testCreate},
+		{"GetByID", testGetByID},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"Insert", testInsert},
+		{"Upsert", testUpsert},
+		{"Each", testEach},
+		{"DeleteMany", testDeleteMany},
+		{"UpdateMany", testUpdateMany},
+		{"Ordering", testOrdering},
+		{"Pagination", testPagination},
+		{"Languages", testLanguages},
+		{"Statuses", testStatuses},
+		{"Search", testSearch},
+		{"SearchPagination", testSearchPagination},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// base is the creation time of the articles made by insert. Times are whole
+// seconds, as some backends only keep milliseconds.
+var base = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// insert stores the published articles with the given titles, created and
+// updated a minute apart in the given order, and returns them
+func insert(t *testing.T, repo domain.NewsRepository, lang string, titles ...string) []*domain.News {
+	t.Helper()
+	news := make([]*domain.News, len(titles))
+	for i, title := range titles {
+		news[i] = &domain.News{
+			Title:     title,
+			Content:   "Content of " + title,
+			Status:    domain.StatusPublished,
+			Language:  lang,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}
+		news[i].UpdatedAt = news[i].CreatedAt
+		require.NoError(t, repo.Insert(context.Background(), news[i]))
+	}
+	return news
+}
+
+func titles(news []*domain.News) []string {
+	titles := make([]string, len(news))
+	for i, n := range news {
+		titles[i] = n.Title
+	}
+	return titles
+}
+
+func testCreate(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	before := time.Now().Add(-time.Second)
+
+	news := &domain.News{Title: "Created", Content: "Created content", Tags: []string{"go", "release"}}
+	require.NoError(t, repo.Create(ctx, news))
+	assert.False(t, news.ID.IsZero())
+	assert.True(t, news.CreatedAt.After(before))
+	assert.True(t, news.UpdatedAt.After(before))
+
+	found, err := repo.GetByID(ctx, news.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, news.ID, found.ID)
+	assert.Equal(t, "Created", found.Title)
+	assert.Equal(t, "Created content", found.Content)
+	assert.Equal(t, []string{"go", "release"}, found.Tags)
+	assert.WithinDuration(t, news.CreatedAt, found.CreatedAt, time.Millisecond)
+}
+
+func testGetByID(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// A malformed ID is an error of its own, not a missing article
+	_, err = repo.GetByID(ctx, "not-an-id")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testUpdate(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	news := insert(t, repo, "", "Original")[0]
+
+	news.Title = "Updated"
+	news.Content = "Updated content"
+	require.NoError(t, repo.Update(ctx, news))
+	assert.True(t, news.UpdatedAt.After(base))
+
+	found, err := repo.GetByID(ctx, news.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", found.Title)
+	assert.Equal(t, "Updated content", found.Content)
+	assert.True(t, base.Equal(found.CreatedAt), "created_at must not change")
+	assert.WithinDuration(t, news.UpdatedAt, found.UpdatedAt, time.Millisecond)
+
+	// Updating a missing article neither fails nor creates it
+	missing := &domain.News{ID: primitive.NewObjectID(), Title: "Missing", Content: "Missing content"}
+	require.NoError(t, repo.Update(ctx, missing))
+	_, err = repo.GetByID(ctx, missing.ID.Hex())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func testDelete(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	news := insert(t, repo, "", "Kept", "Deleted")
+
+	require.NoError(t, repo.Delete(ctx, news[1].ID.Hex()))
+	_, err := repo.GetByID(ctx, news[1].ID.Hex())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// Deleting is idempotent, but a malformed ID is rejected
+	assert.NoError(t, repo.Delete(ctx, news[1].ID.Hex()))
+	assert.Error(t, repo.Delete(ctx, "not-an-id"))
+
+	list, total, err := repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"Kept"}, titles(list))
+}
+
+func testInsert(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	news := insert(t, repo, "", "Imported")[0]
+
+	found, err := repo.GetByID(ctx, news.ID.Hex())
+	require.NoError(t, err)
+	assert.True(t, base.Equal(found.CreatedAt), "Insert must keep created_at")
+
+	duplicate := &domain.News{ID: news.ID, Title: "Duplicate", Content: "Duplicate content"}
+	assert.ErrorIs(t, repo.Insert(ctx, duplicate), domain.ErrConflict)
+}
+
+func testUpsert(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+
+	news := &domain.News{ID: primitive.NewObjectID(), Title: "First", Content: "First content", Slug: "first", CreatedAt: base}
+	inserted, err := repo.Upsert(ctx, news)
+	require.NoError(t, err)
+	assert.True(t, inserted)
+
+	// The same ID replaces every field
+	replacement := &domain.News{
+		ID:        news.ID,
+		Title:     "Replaced",
+		Content:   "Replaced content",
+		Slug:      "replaced",
+		Status:    domain.StatusDraft,
+		Tags:      []string{"go"},
+		Language:  "uk",
+		CreatedAt: base,
+	}
+	inserted, err = repo.Upsert(ctx, replacement)
+	require.NoError(t, err)
+	assert.False(t, inserted)
+
+	found, err := repo.GetByID(ctx, news.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Replaced", found.Title)
+	assert.Equal(t, "Replaced content", found.Content)
+	assert.Equal(t, "replaced", found.Slug)
+	assert.Equal(t, domain.StatusDraft, found.Status)
+	assert.Equal(t, []string{"go"}, found.Tags)
+	assert.Equal(t, "uk", found.Language)
+	assert.True(t, base.Equal(found.CreatedAt), "Upsert must keep created_at")
+
+	// Without an ID the slug is matched, and the stored ID kept
+	bySlug := &domain.News{Title: "By slug", Content: "By slug content", Slug: "by-slug"}
+	inserted, err = repo.Upsert(ctx, bySlug)
+	require.NoError(t, err)
+	assert.True(t, inserted)
+	require.False(t, bySlug.ID.IsZero())
+
+	again := &domain.News{Title: "By slug again", Content: "By slug content", Slug: "by-slug"}
+	inserted, err = repo.Upsert(ctx, again)
+	require.NoError(t, err)
+	assert.False(t, inserted)
+	assert.Equal(t, bySlug.ID, again.ID)
+
+	found, err = repo.GetByID(ctx, bySlug.ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "By slug again", found.Title)
+
+	// Taking the slug of another article is a conflict
+	clash := &domain.News{ID: primitive.NewObjectID(), Title: "Clash", Content: "Clash content", Slug: "by-slug"}
+	_, err = repo.Upsert(ctx, clash)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+}
+
+func testEach(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	news := insert(t, repo, "", "First", "Second", "Third")
+	// Drafts are visited too
+	draft := &domain.News{Title: "Draft", Content: "Draft content", Status: domain.StatusDraft}
+	require.NoError(t, repo.Insert(ctx, draft))
+
+	var visited []primitive.ObjectID
+	require.NoError(t, repo.Each(ctx, func(n *domain.News) error {
+		visited = append(visited, n.ID)
+		return nil
+	}))
+	want := []primitive.ObjectID{news[0].ID, news[1].ID, news[2].ID, draft.ID}
+	sort.Slice(want, func(i, j int) bool { return want[i].Hex() < want[j].Hex() })
+	assert.Equal(t, want, visited)
+
+	// An error from fn stops the walk and is returned
+	stop := errors.New("stop")
+	calls := 0
+	err := repo.Each(ctx, func(*domain.News) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func testDeleteMany(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	news := insert(t, repo, "", "Kept", "Deleted")
+	id := news[1].ID.Hex()
+	missing := primitive.NewObjectID().Hex()
+
+	// Every distinct ID gets one result, in request order
+	results, err := repo.DeleteMany(ctx, []string{id, "bad", missing, id})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BatchResult{
+		{ID: id, OK: true},
+		{ID: "bad", Error: "invalid id"},
+		{ID: missing, Error: domain.ErrNotFound.Error()},
+	}, results)
+
+	_, err = repo.GetByID(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = repo.GetByID(ctx, news[0].ID.Hex())
+	assert.NoError(t, err)
+}
+
+func testUpdateMany(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	draft := &domain.News{Title: "Draft", Content: "Draft content", Status: domain.StatusDraft, Tags: []string{"go"}, CreatedAt: base, UpdatedAt: base}
+	require.NoError(t, repo.Insert(ctx, draft))
+	id := draft.ID.Hex()
+	missing := primitive.NewObjectID().Hex()
+
+	results, err := repo.UpdateMany(ctx, []string{id, id, "bad", missing},
+		domain.BatchUpdate{Status: domain.StatusPublished, Tags: []string{"release", "go", "$literal"}})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BatchResult{
+		{ID: id, OK: true},
+		{ID: "bad", Error: "invalid id"},
+		{ID: missing, Error: domain.ErrNotFound.Error()},
+	}, results)
+
+	// Publishing sets the publication time, and new tags are appended
+	found, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, found.Status)
+	require.NotNil(t, found.PublishedAt)
+	assert.Equal(t, []string{"go", "release", "$literal"}, found.Tags)
+	assert.True(t, found.UpdatedAt.After(base))
+	published := *found.PublishedAt
+
+	// which a second publication keeps
+	_, err = repo.UpdateMany(ctx, []string{id}, domain.BatchUpdate{Status: domain.StatusPublished})
+	require.NoError(t, err)
+	found, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.WithinDuration(t, published, *found.PublishedAt, time.Millisecond)
+
+	_, err = repo.UpdateMany(ctx, []string{id}, domain.BatchUpdate{Status: domain.StatusArchived})
+	require.NoError(t, err)
+	found, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusArchived, found.Status)
+	assert.Equal(t, []string{"go", "release", "$literal"}, found.Tags)
+}
+
+func testOrdering(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+
+	// Newest first whatever the insertion order, which IDs follow
+	for _, n := range []struct {
+		title string
+		hours int
+	}{{"Second", 2}, {"Fourth", 4}, {"First", 1}, {"Third", 3}} {
+		news := &domain.News{
+			Title:     n.title,
+			Content:   "Content of " + n.title,
+			Status:    domain.StatusPublished,
+			CreatedAt: base.Add(time.Duration(n.hours) * time.Hour),
+		}
+		require.NoError(t, repo.Insert(ctx, news))
+	}
+
+	list, _, err := repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Fourth", "Third", "Second", "First"}, titles(list))
+}
+
+func testPagination(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	var names []string
+	for i := 0; i < 25; i++ {
+		names = append(names, fmt.Sprintf("Article %02d", i))
+	}
+	insert(t, repo, "", names...)
+
+	for _, tc := range []struct {
+		page, limit int
+		first, last string
+		count       int
+	}{
+		{page: 1, limit: 10, first: "Article 24", last: "Article 15", count: 10},
+		{page: 2, limit: 10, first: "Article 14", last: "Article 05", count: 10},
+		{page: 3, limit: 10, first: "Article 04", last: "Article 00", count: 5},
+		{page: 1, limit: 25, first: "Article 24", last: "Article 00", count: 25},
+		{page: 25, limit: 1, first: "Article 00", last: "Article 00", count: 1},
+	} {
+		name := fmt.Sprintf("page %d limit %d", tc.page, tc.limit)
+		list, total, err := repo.GetAll(ctx, "", tc.page, tc.limit)
+		require.NoError(t, err, name)
+		assert.Equal(t, int64(25), total, name)
+		require.Len(t, list, tc.count, name)
+		assert.Equal(t, tc.first, list[0].Title, name)
+		assert.Equal(t, tc.last, list[len(list)-1].Title, name)
+	}
+
+	// Past the last page the total is still reported
+	list, total, err := repo.GetAll(ctx, "", 4, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Equal(t, int64(25), total)
+
+	// Walking the pages visits every article once
+	seen := make(map[primitive.ObjectID]bool)
+	for page := 1; page <= 9; page++ {
+		list, _, err := repo.GetAll(ctx, "", page, 3)
+		require.NoError(t, err)
+		for _, n := range list {
+			assert.False(t, seen[n.ID], "%s listed twice", n.Title)
+			seen[n.ID] = true
+		}
+	}
+	assert.Len(t, seen, 25)
+}
+
+func testLanguages(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	insert(t, repo, "en", "English One", "English Two")
+	insert(t, repo, "uk", "Ukrainian One")
+
+	list, total, err := repo.GetAll(ctx, "en", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.ElementsMatch(t, []string{"English One", "English Two"}, titles(list))
+
+	_, total, err = repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	list, total, err = repo.GetAll(ctx, "de", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, list)
+}
+
+func testStatuses(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	news := insert(t, repo, "en", "Published Report", "Draft Report", "Archived Report")
+	for i, status := range []string{domain.StatusDraft, domain.StatusArchived} {
+		hidden := news[i+1]
+		require.NoError(t, repo.Delete(ctx, hidden.ID.Hex()))
+		hidden.Status = status
+		require.NoError(t, repo.Insert(ctx, hidden))
+	}
+
+	// Only published articles are listed or found
+	list, total, err := repo.GetAll(ctx, "", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"Published Report"}, titles(list))
+
+	list, total, err = repo.Search(ctx, "en", "report", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"Published Report"}, titles(list))
+
+	// but can still be read by ID
+	found, err := repo.GetByID(ctx, news[1].ID.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusDraft, found.Status)
+}
+
+func testSearch(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	for _, news := range []*domain.News{
+		{Title: "Golang Release", Content: "The new version of the Go programming language", Language: "en"},
+		{Title: "Python News", Content: "Python programming language", Language: "en"},
+		{Title: "Java News", Content: "Java programming language and GoLang interop", Language: "en"},
+	} {
+		news.Status = domain.StatusPublished
+		require.NoError(t, repo.Create(ctx, news))
+	}
+
+	// Case does not matter, in the query or the text
+	for _, query := range []string{"golang", "GOLANG", "GoLang"} {
+		list, total, err := repo.Search(ctx, "", query, 1, 10)
+		require.NoError(t, err, query)
+		assert.Equal(t, int64(2), total, query)
+		require.Len(t, list, 2, query)
+		// Title matches rank above content matches
+		assert.Equal(t, "Golang Release", list[0].Title, query)
+	}
+
+	// Any term matches
+	list, total, err := repo.Search(ctx, "en", "python java", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.ElementsMatch(t, []string{"Python News", "Java News"}, titles(list))
+
+	list, total, err = repo.Search(ctx, "", "cobol", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, list)
+
+	// Other languages are filtered out
+	_, total, err = repo.Search(ctx, "uk", "golang", 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func testSearchPagination(t *testing.T, repo domain.NewsRepository) {
+	ctx := context.Background()
+	var names []string
+	for i := 0; i < 12; i++ {
+		names = append(names, fmt.Sprintf("Report %02d", i))
+	}
+	insert(t, repo, "en", names...)
+	insert(t, repo, "en", "Unrelated")
+
+	seen := make(map[primitive.ObjectID]bool)
+	for page, count := range []int{5, 5, 2, 0} {
+		list, total, err := repo.Search(ctx, "", "report", page+1, 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(12), total, "page %d", page+1)
+		require.Len(t, list, count, "page %d", page+1)
+		for _, n := range list {
+			assert.True(t, strings.HasPrefix(n.Title, "Report"), n.Title)
+			assert.False(t, seen[n.ID], "%s found twice", n.Title)
+			seen[n.ID] = true
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// memoryRepository is the smallest repository meeting the contract, which
+// keeps the suite itself honest. Search matches any whole term, ignoring case.
+type memoryRepository struct {
+	mu   sync.Mutex
+	news []domain.News
+}
+
+func (m *memoryRepository) find(id primitive.ObjectID) int {
+	for i := range m.news {
+		if m.news[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// findSlug returns the index of the article with the given slug, or -1
+func (m *memoryRepository) findSlug(slug string) int {
+	for i := range m.news {
+		if slug != "" && m.news[i].Slug == slug {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *memoryRepository) Create(ctx context.Context, news *domain.News) error {
+	news.CreatedAt = time.Now()
+	news.UpdatedAt = time.Now()
+	return m.Insert(ctx, news)
+}
+
+func (m *memoryRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(oid)
+	if i < 0 {
+		return nil, domain.ErrNotFound
+	}
+	news := m.news[i]
+	return &news, nil
+}
+
+// page sorts matches by score, then newest first, and returns the requested
+// page
+func page(matches []domain.News, score func(*domain.News) int, page, limit int) ([]*domain.News, int64) {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if si, sj := score(&matches[i]), score(&matches[j]); si != sj {
+			return si > sj
+		}
+		return matches[i].CreatedAt.After(matches[j].CreatedAt)
+	})
+
+	var list []*domain.News
+	for i := (page - 1) * limit; i < len(matches) && i < page*limit; i++ {
+		list = append(list, &matches[i])
+	}
+	return list, int64(len(matches))
+}
+
+func (m *memoryRepository) filter(lang string, keep func(*domain.News) bool) []domain.News {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var matches []domain.News
+	for _, news := range m.news {
+		if news.Status == domain.StatusPublished && (lang == "" || news.Language == lang) && keep(&news) {
+			matches = append(matches, news)
+		}
+	}
+	return matches
+}
+
+func (m *memoryRepository) GetAll(ctx context.Context, lang string, p, limit int) ([]*domain.News, int64, error) {
+	matches := m.filter(lang, func(*domain.News) bool { return true })
+	list, total := page(matches, func(*domain.News) int { return 0 }, p, limit)
+	return list, total, nil
+}
+
+func (m *memoryRepository) Update(ctx context.Context, news *domain.News) error {
+	news.UpdatedAt = time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i := m.find(news.ID); i >= 0 {
+		m.news[i].Title = news.Title
+		m.news[i].Content = news.Content
+		m.news[i].UpdatedAt = news.UpdatedAt
+	}
+	return nil
+}
+
+func (m *memoryRepository) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i := m.find(oid); i >= 0 {
+		m.news = append(m.news[:i], m.news[i+1:]...)
+	}
+	return nil
+}
+
+func (m *memoryRepository) Search(ctx context.Context, lang, query string, p, limit int) ([]*domain.News, int64, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	count := func(text string) int {
+		n := 0
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			for _, term := range terms {
+				if word == term {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	score := func(news *domain.News) int {
+		return 10*count(news.Title) + count(news.Content)
+	}
+
+	matches := m.filter(lang, func(news *domain.News) bool { return score(news) > 0 })
+	list, total := page(matches, score, p, limit)
+	return list, total, nil
+}
+
+func (m *memoryRepository) Insert(ctx context.Context, news *domain.News) error {
+	if news.ID.IsZero() {
+		news.ID = primitive.NewObjectID()
+	}
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = time.Now()
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.find(news.ID) >= 0 || m.findSlug(news.Slug) >= 0 {
+		return domain.ErrConflict
+	}
+	m.news = append(m.news, *news)
+	return nil
+}
+
+func (m *memoryRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = time.Now()
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = time.Now()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.find(news.ID)
+	if news.ID.IsZero() {
+		i = m.findSlug(news.Slug)
+	}
+	if i < 0 {
+		if news.ID.IsZero() {
+			news.ID = primitive.NewObjectID()
+		}
+		if m.findSlug(news.Slug) >= 0 {
+			return false, domain.ErrConflict
+		}
+		m.news = append(m.news, *news)
+		return true, nil
+	}
+
+	if j := m.findSlug(news.Slug); j >= 0 && j != i {
+		return false, domain.ErrConflict
+	}
+	news.ID = m.news[i].ID
+	m.news[i] = *news
+	return false, nil
+}
+
+func (m *memoryRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	m.mu.Lock()
+	news := append([]domain.News(nil), m.news...)
+	m.mu.Unlock()
+
+	sort.Slice(news, func(i, j int) bool { return news[i].ID.Hex() < news[j].ID.Hex() })
+	for i := range news {
+		if err := fn(&news[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batch applies fn to every distinct existing article of ids and reports
+// the outcome for each ID
+func (m *memoryRepository) batch(ids []string, fn func(i int)) []domain.BatchResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var results []domain.BatchResult
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			results = append(results, domain.BatchResult{ID: id, Error: "invalid id"})
+			continue
+		}
+		i := m.find(oid)
+		if i < 0 {
+			results = append(results, domain.BatchResult{ID: id, Error: domain.ErrNotFound.Error()})
+			continue
+		}
+		fn(i)
+		results = append(results, domain.BatchResult{ID: id, OK: true})
+	}
+	return results
+}
+
+func (m *memoryRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	var deleted []primitive.ObjectID
+	results := m.batch(ids, func(i int) {
+		deleted = append(deleted, m.news[i].ID)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range deleted {
+		i := m.find(id)
+		m.news = append(m.news[:i], m.news[i+1:]...)
+	}
+	return results, nil
+}
+
+func (m *memoryRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	now := time.Now()
+	return m.batch(ids, func(i int) {
+		news := &m.news[i]
+		for _, tag := range update.Tags {
+			if !slices.Contains(news.Tags, tag) {
+				news.Tags = append(news.Tags, tag)
+			}
+		}
+		if update.Status != "" {
+			news.Status = update.Status
+		}
+		if update.Status == domain.StatusPublished && news.PublishedAt == nil {
+			news.PublishedAt = &now
+		}
+		news.UpdatedAt = now
+	}), nil
+}
+
+func (m *memoryRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRun(t *testing.T) {
+	Run(t, func(t *testing.T) domain.NewsRepository {
+		return &memoryRepository{}
+	})
+}
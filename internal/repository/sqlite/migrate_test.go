@@ -0,0 +1,29 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations(t *testing.T) {
+	list, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	for i, m := range list {
+		assert.Equal(t, i+1, m.Version, m.Name)
+		assert.NotEmpty(t, m.SQL)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// setupTestDB applied everything already
+	applied, err := Migrate(context.Background(), db)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+}
@@ -0,0 +1,43 @@
+-- IDs are MongoDB style object IDs in hex, which sort by creation time.
+-- Lists are JSON arrays and times Unix nanoseconds.
+CREATE TABLE news (
+    id             TEXT PRIMARY KEY,
+    title          TEXT NOT NULL,
+    content        TEXT NOT NULL,
+    slug           TEXT UNIQUE,
+    status         TEXT NOT NULL DEFAULT '',
+    tags           TEXT NOT NULL DEFAULT '[]',
+    language       TEXT NOT NULL DEFAULT '',
+    translation_of TEXT,
+    published_at   INTEGER,
+    created_at     INTEGER NOT NULL,
+    updated_at     INTEGER NOT NULL
+);
+
+CREATE INDEX news_created_at ON news (created_at DESC);
+CREATE INDEX news_language_created_at ON news (language, created_at DESC);
+-- One variant per language of an article
+CREATE UNIQUE INDEX news_translation_of_language_unique ON news (translation_of, language)
+    WHERE translation_of IS NOT NULL;
+CREATE INDEX news_status_published_at ON news (status, published_at DESC);
+
+-- The Porter stemmer only changes ASCII words, so English is stemmed and
+-- other languages are only tokenized, as in the MongoDB text index
+CREATE VIRTUAL TABLE news_fts USING fts5(
+    title, content,
+    content = 'news', content_rowid = 'rowid',
+    tokenize = 'porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER news_fts_insert AFTER INSERT ON news BEGIN
+    INSERT INTO news_fts (rowid, title, content) VALUES (new.rowid, new.title, new.content);
+END;
+
+CREATE TRIGGER news_fts_delete AFTER DELETE ON news BEGIN
+    INSERT INTO news_fts (news_fts, rowid, title, content) VALUES ('delete', old.rowid, old.title, old.content);
+END;
+
+CREATE TRIGGER news_fts_update AFTER UPDATE OF title, content ON news BEGIN
+    INSERT INTO news_fts (news_fts, rowid, title, content) VALUES ('delete', old.rowid, old.title, old.content);
+    INSERT INTO news_fts (rowid, title, content) VALUES (new.rowid, new.title, new.content);
+END;
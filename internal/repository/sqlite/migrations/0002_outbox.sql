@@ -0,0 +1,21 @@
+CREATE TABLE outbox (
+    id              TEXT PRIMARY KEY,
+    type            TEXT NOT NULL,
+    article_id      TEXT NOT NULL,
+    article         TEXT,
+    occurred_at     INTEGER NOT NULL,
+    delivered_to    TEXT NOT NULL DEFAULT '[]',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL,
+    last_error      TEXT NOT NULL DEFAULT '',
+    delivered_at    INTEGER
+);
+
+CREATE INDEX outbox_pending ON outbox (id) WHERE delivered_at IS NULL;
+CREATE INDEX outbox_delivered_at ON outbox (delivered_at) WHERE delivered_at IS NOT NULL;
+
+CREATE TABLE outbox_lease (
+    id         TEXT PRIMARY KEY,
+    owner      TEXT NOT NULL,
+    expires_at INTEGER NOT NULL
+);
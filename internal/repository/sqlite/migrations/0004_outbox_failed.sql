@@ -0,0 +1,4 @@
+ALTER TABLE outbox ADD COLUMN failed_at INTEGER;
+
+DROP INDEX outbox_pending;
+CREATE INDEX outbox_pending ON outbox (id) WHERE delivered_at IS NULL AND failed_at IS NULL;
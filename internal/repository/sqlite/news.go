@@ -0,0 +1,462 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// eachBatchSize is how many articles Each reads at a time
+const eachBatchSize = 100
+
+// newsColumns are the columns scanned by scanNews, in order
+const newsColumns = `news.id, news.title, news.content, coalesce(news.slug, ''), news.status, news.tags,
+	news.language, news.translation_of, news.published_at, news.created_at, news.updated_at`
+
+const insertNews = `INSERT INTO news (id, title, content, slug, status, tags, language,
+	translation_of, published_at, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+type newsRepository struct {
+	db *sql.DB
+}
+
+// NewNewsRepository creates a new instance of SQLite news repository
+func NewNewsRepository(db *sql.DB) domain.NewsRepository {
+	return &newsRepository{db: db}
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNews(row scanner) (*domain.News, error) {
+	var (
+		news                 domain.News
+		id, tags             string
+		translationOf        sql.NullString
+		publishedAt          sql.NullInt64
+		createdAt, updatedAt int64
+	)
+	err := row.Scan(&id, &news.Title, &news.Content, &news.Slug, &news.Status, &tags, &news.Language,
+		&translationOf, &publishedAt, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if news.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, fmt.Errorf("news id %q: %w", id, err)
+	}
+	if translationOf.Valid {
+		group, err := primitive.ObjectIDFromHex(translationOf.String)
+		if err != nil {
+			return nil, fmt.Errorf("translation_of %q: %w", translationOf.String, err)
+		}
+		news.TranslationOf = &group
+	}
+	if err := json.Unmarshal([]byte(tags), &news.Tags); err != nil {
+		return nil, fmt.Errorf("tags of %s: %w", id, err)
+	}
+	if len(news.Tags) == 0 {
+		news.Tags = nil
+	}
+	if publishedAt.Valid {
+		t := fromUnixNano(publishedAt.Int64)
+		news.PublishedAt = &t
+	}
+	news.CreatedAt = fromUnixNano(createdAt)
+	news.UpdatedAt = fromUnixNano(updatedAt)
+	return &news, nil
+}
+
+func (r *newsRepository) queryNews(ctx context.Context, query string, args ...any) ([]*domain.News, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var news []*domain.News
+	for rows.Next() {
+		n, err := scanNews(rows)
+		if err != nil {
+			return nil, err
+		}
+		news = append(news, n)
+	}
+	return news, rows.Err()
+}
+
+// values returns the stored columns of news, in the order of insertNews
+func values(news *domain.News) ([]any, error) {
+	tags, err := json.Marshal(nonNil(news.Tags))
+	if err != nil {
+		return nil, err
+	}
+	var slug, translationOf, publishedAt any
+	if news.Slug != "" {
+		slug = news.Slug
+	}
+	if news.TranslationOf != nil {
+		translationOf = news.TranslationOf.Hex()
+	}
+	if news.PublishedAt != nil {
+		publishedAt = unixNano(*news.PublishedAt)
+	}
+	return []any{
+		news.ID.Hex(), news.Title, news.Content, slug, news.Status, string(tags), news.Language,
+		translationOf, publishedAt, unixNano(news.CreatedAt), unixNano(news.UpdatedAt),
+	}, nil
+}
+
+func (r *newsRepository) insert(ctx context.Context, news *domain.News) error {
+	args, err := values(news)
+	if err != nil {
+		return err
+	}
+	_, err = conn(ctx, r.db).ExecContext(ctx, insertNews, args...)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	return err
+}
+
+func (r *newsRepository) Create(ctx context.Context, news *domain.News) error {
+	if news.ID.IsZero() {
+		news.ID = primitive.NewObjectID()
+	}
+	news.CreatedAt = time.Now()
+	news.UpdatedAt = time.Now()
+	return r.insert(ctx, news)
+}
+
+func (r *newsRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	news, err := scanNews(conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT `+newsColumns+` FROM news WHERE id = ?`, objectID.Hex()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	return news, err
+}
+
+// listFilter restricts a query to published articles, in lang unless it is
+// empty
+func listFilter(where []string, args []any, lang string) ([]string, []any) {
+	where = append(where, "news.status = ?")
+	args = append(args, domain.StatusPublished)
+	if lang != "" {
+		where = append(where, "news.language = ?")
+		args = append(args, lang)
+	}
+	return where, args
+}
+
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(where, " AND ")
+}
+
+func (r *newsRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	where, args := listFilter(nil, nil, lang)
+	filter := whereClause(where)
+
+	news, err := r.queryNews(ctx,
+		`SELECT `+newsColumns+` FROM news`+filter+` ORDER BY news.created_at DESC, news.id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(*) FROM news`+filter, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return news, total, nil
+}
+
+func (r *newsRepository) Update(ctx context.Context, news *domain.News) error {
+	news.UpdatedAt = time.Now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE news SET title = ?, content = ?, updated_at = ? WHERE id = ?`,
+		news.Title, news.Content, unixNano(news.UpdatedAt), news.ID.Hex())
+	return err
+}
+
+func (r *newsRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `DELETE FROM news WHERE id = ?`, objectID.Hex())
+	return err
+}
+
+// Search matches the query against the full text index like MongoDB text
+// search does, see matchQuery. Results are ordered by relevance, with title
+// matches weighing ten times as much as content matches, then newest first.
+func (r *newsRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	match := matchQuery(query)
+	if match == "" {
+		return nil, 0, nil
+	}
+	where, args := listFilter([]string{"news_fts MATCH ?"}, []any{match}, lang)
+	from := ` FROM news_fts JOIN news ON news.rowid = news_fts.rowid` + whereClause(where)
+
+	news, err := r.queryNews(ctx,
+		`SELECT `+newsColumns+from+` ORDER BY bm25(news_fts, 10.0, 1.0), news.created_at DESC, news.id DESC LIMIT ? OFFSET ?`,
+		append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(*)`+from, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return news, total, nil
+}
+
+func (r *newsRepository) Insert(ctx context.Context, news *domain.News) error {
+	now := time.Now()
+	if news.ID.IsZero() {
+		news.ID = primitive.NewObjectID()
+	}
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+	return r.insert(ctx, news)
+}
+
+func (r *newsRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	now := time.Now()
+	if news.CreatedAt.IsZero() {
+		news.CreatedAt = now
+	}
+	if news.UpdatedAt.IsZero() {
+		news.UpdatedAt = now
+	}
+
+	// Without an ID the article is matched by slug and keeps its stored ID
+	target := "id"
+	if news.ID.IsZero() {
+		target = "slug"
+		news.ID = primitive.NewObjectID()
+	}
+
+	args, err := values(news)
+	if err != nil {
+		return false, err
+	}
+	key := args[0]
+	if target == "slug" {
+		key = args[3]
+	}
+
+	// SQLite does not tell inserted and updated rows apart, so look first.
+	// Updating in place keeps the rowid the full text index refers to.
+	var inserted bool
+	err = withinTransaction(ctx, r.db, func(ctx context.Context) error {
+		var count int
+		err := conn(ctx, r.db).QueryRowContext(ctx,
+			`SELECT count(*) FROM news WHERE `+target+` = ?`, key).Scan(&count)
+		if err != nil {
+			return err
+		}
+		inserted = count == 0
+
+		var id string
+		err = conn(ctx, r.db).QueryRowContext(ctx, insertNews+` ON CONFLICT (`+target+`) DO UPDATE SET
+			title = excluded.title, content = excluded.content, slug = excluded.slug,
+			status = excluded.status, tags = excluded.tags, language = excluded.language,
+			translation_of = excluded.translation_of, published_at = excluded.published_at,
+			created_at = excluded.created_at, updated_at = excluded.updated_at
+			RETURNING id`, args...).Scan(&id)
+		if isUniqueViolation(err) {
+			return domain.ErrConflict
+		}
+		if err != nil {
+			return err
+		}
+		news.ID, err = primitive.ObjectIDFromHex(id)
+		return err
+	})
+	return inserted, err
+}
+
+func (r *newsRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	// Reading in batches frees the connection while fn runs
+	after := ""
+	for {
+		batch, err := r.queryNews(ctx,
+			`SELECT `+newsColumns+` FROM news WHERE id > ? ORDER BY id LIMIT ?`, after, eachBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, news := range batch {
+			if err := fn(news); err != nil {
+				return err
+			}
+		}
+		if len(batch) < eachBatchSize {
+			return nil
+		}
+		after = batch[len(batch)-1].ID.Hex()
+	}
+}
+
+func (r *newsRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	return r.batch(ctx, ids, func(ctx context.Context, ids []string) ([]string, error) {
+		return r.queryIDs(ctx, `DELETE FROM news WHERE id IN (`+placeholders(len(ids))+`) RETURNING id`,
+			anySlice(ids)...)
+	})
+}
+
+func (r *newsRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	return r.batch(ctx, ids, func(ctx context.Context, ids []string) ([]string, error) {
+		var changed []string
+		err := withinTransaction(ctx, r.db, func(ctx context.Context) error {
+			articles, err := r.queryNews(ctx,
+				`SELECT `+newsColumns+` FROM news WHERE id IN (`+placeholders(len(ids))+`)`, anySlice(ids)...)
+			if err != nil {
+				return err
+			}
+
+			now := time.Now()
+			for _, news := range articles {
+				// Append the tags an article does not have yet, keeping its order
+				for _, tag := range update.Tags {
+					if !slices.Contains(news.Tags, tag) {
+						news.Tags = append(news.Tags, tag)
+					}
+				}
+				if update.Status != "" {
+					news.Status = update.Status
+				}
+				if update.Status == domain.StatusPublished && news.PublishedAt == nil {
+					news.PublishedAt = &now
+				}
+				news.UpdatedAt = now
+
+				args, err := values(news)
+				if err != nil {
+					return err
+				}
+				_, err = conn(ctx, r.db).ExecContext(ctx,
+					`UPDATE news SET status = ?, tags = ?, published_at = ?, updated_at = ? WHERE id = ?`,
+					args[4], args[5], args[8], args[10], args[0])
+				if err != nil {
+					return err
+				}
+				changed = append(changed, news.ID.Hex())
+			}
+			return nil
+		})
+		return changed, err
+	})
+}
+
+func (r *newsRepository) queryIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// batch runs a statement over the valid IDs that returns the IDs it changed,
+// and reports the outcome for every distinct ID, including those that are
+// invalid or do not exist
+func (r *newsRepository) batch(ctx context.Context, ids []string, run func(context.Context, []string) ([]string, error)) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, 0, len(ids))
+	index := make(map[string]int, len(ids))
+	seen := make(map[string]bool, len(ids))
+	var valid []string
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			results = append(results, domain.BatchResult{ID: id, Error: "invalid id"})
+			continue
+		}
+		index[oid.Hex()] = len(results)
+		valid = append(valid, oid.Hex())
+		results = append(results, domain.BatchResult{ID: id, Error: domain.ErrNotFound.Error()})
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	changed, err := run(ctx, valid)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range changed {
+		i := index[id]
+		results[i] = domain.BatchResult{ID: results[i].ID, OK: true}
+	}
+	return results, nil
+}
+
+func (r *newsRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	news, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group := news.TranslationGroup().Hex()
+	return r.queryNews(ctx,
+		`SELECT `+newsColumns+` FROM news WHERE id = ? OR translation_of = ? ORDER BY language`,
+		group, group)
+}
+
+// nonNil stores empty lists as empty arrays rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func anySlice(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
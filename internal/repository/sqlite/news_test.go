@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+	"news_service/internal/repository/repotest"
+)
+
+// setupTestDB opens a migrated database in a temporary directory
+func setupTestDB(t *testing.T) (*sql.DB, func()) {
+	ctx := context.Background()
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "news.db"), time.Second)
+	require.NoError(t, err)
+
+	_, err = Migrate(ctx, db)
+	require.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+	}
+}
+
+func TestNewsRepository_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) domain.NewsRepository {
+		db, cleanup := setupTestDB(t)
+		t.Cleanup(cleanup)
+		return NewNewsRepository(db)
+	})
+}
+
+func TestNewsRepository_SearchSyntax(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNewsRepository(db)
+	ctx := context.Background()
+
+	for _, n := range []*domain.News{
+		{Title: "Golang News", Content: "Go programming language", Language: "en", Status: domain.StatusPublished},
+		{Title: "Python News", Content: "Python programming language", Language: "en", Status: domain.StatusPublished},
+		{Title: "Java News", Content: "Java programming language and Golang interop", Language: "en", Status: domain.StatusPublished},
+	} {
+		require.NoError(t, repo.Create(ctx, n))
+	}
+
+	// Negated terms exclude, quoted phrases match as a whole
+	_, total, err := repo.Search(ctx, "en", "python java -golang", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+
+	results, _, err := repo.Search(ctx, "en", `"python programming"`, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Python News", results[0].Title)
+}
+
+func TestNewsRepository_Languages(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := NewNewsRepository(db)
+	ctx := context.Background()
+
+	original := &domain.News{Title: "Elections announced", Content: "The elections are announced", Language: "en", Status: domain.StatusPublished}
+	require.NoError(t, repo.Create(ctx, original))
+	group := original.ID
+	translation := &domain.News{Title: "Оголошено вибори", Content: "Вибори оголошено сьогодні", Language: "uk", TranslationOf: &group, Status: domain.StatusPublished}
+	require.NoError(t, repo.Create(ctx, translation))
+
+	news, total, err := repo.GetAll(ctx, "uk", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, translation.ID, news[0].ID)
+
+	// English queries are stemmed, "election" finds "elections"
+	news, _, err = repo.Search(ctx, "en", "election", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+	assert.Equal(t, original.ID, news[0].ID)
+
+	news, _, err = repo.Search(ctx, "uk", "вибори", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, news, 1)
+	assert.Equal(t, translation.ID, news[0].ID)
+
+	translations, err := repo.Translations(ctx, translation.ID.Hex())
+	require.NoError(t, err)
+	require.Len(t, translations, 2)
+	assert.Equal(t, "en", translations[0].Language)
+	assert.Equal(t, "uk", translations[1].Language)
+
+	// A second Ukrainian variant is rejected
+	duplicate := &domain.News{Title: "Вибори", Content: "Ще одна версія", Language: "uk", TranslationOf: &group}
+	assert.ErrorIs(t, repo.Create(ctx, duplicate), domain.ErrConflict)
+}
+
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"news_service/internal/domain"
+	"news_service/internal/events"
+)
+
+const (
+	leaseID = "dispatcher"
+	// outboxRetention is how long delivered events are kept for inspection
+	outboxRetention = 7 * 24 * time.Hour
+)
+
+// Outbox stores domain events in SQLite. Appending with a context from
+// Transactor.WithinTransaction commits the events with the article change.
+type Outbox struct {
+	db *sql.DB
+}
+
+var (
+	_ domain.Outbox = (*Outbox)(nil)
+	_ events.Store  = (*Outbox)(nil)
+)
+
+// NewOutbox creates an outbox in db
+func NewOutbox(db *sql.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+func (o *Outbox) Append(ctx context.Context, evts ...domain.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+
+	return withinTransaction(ctx, o.db, func(ctx context.Context) error {
+		for _, event := range evts {
+			var article any
+			if event.Article != nil {
+				data, err := json.Marshal(event.Article)
+				if err != nil {
+					return err
+				}
+				article = string(data)
+			}
+			occurredAt := unixNano(event.OccurredAt)
+			_, err := conn(ctx, o.db).ExecContext(ctx, `INSERT INTO outbox
+				(id, type, article_id, article, occurred_at, next_attempt_at) VALUES (?, ?, ?, ?, ?, ?)`,
+				event.ID, event.Type, event.ArticleID, article, occurredAt, occurredAt)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (o *Outbox) Acquire(ctx context.Context, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	result, err := o.db.ExecContext(ctx, `INSERT INTO outbox_lease (id, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE outbox_lease.owner = excluded.owner OR outbox_lease.expires_at < ?`,
+		leaseID, owner, unixNano(now.Add(ttl)), unixNano(now))
+	if err != nil {
+		return false, err
+	}
+	// Nothing is written while someone else holds the lease
+	n, err := result.RowsAffected()
+	return n == 1, err
+}
+
+func (o *Outbox) Pending(ctx context.Context, after string, limit int) ([]events.Record, error) {
+	rows, err := o.db.QueryContext(ctx, `SELECT id, type, article_id, article, occurred_at,
+		delivered_to, attempts, next_attempt_at
+		FROM outbox WHERE delivered_at IS NULL AND failed_at IS NULL AND id > ?
+		ORDER BY id LIMIT ?`, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []events.Record
+	for rows.Next() {
+		var (
+			record                    events.Record
+			article                   sql.NullString
+			deliveredTo               string
+			occurredAt, nextAttemptAt int64
+		)
+		err := rows.Scan(&record.Event.ID, &record.Event.Type, &record.Event.ArticleID, &article,
+			&occurredAt, &deliveredTo, &record.Attempts, &nextAttemptAt)
+		if err != nil {
+			return nil, err
+		}
+		if article.Valid {
+			if err := json.Unmarshal([]byte(article.String), &record.Event.Article); err != nil {
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal([]byte(deliveredTo), &record.DeliveredTo); err != nil {
+			return nil, err
+		}
+		if len(record.DeliveredTo) == 0 {
+			record.DeliveredTo = nil
+		}
+		record.Event.OccurredAt = fromUnixNano(occurredAt)
+		record.NextAttemptAt = fromUnixNano(nextAttemptAt)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (o *Outbox) Delivered(ctx context.Context, id, subscriber string) error {
+	_, err := o.db.ExecContext(ctx, `UPDATE outbox SET delivered_to = json_insert(delivered_to, '$[#]', ?)
+		WHERE id = ? AND NOT EXISTS (SELECT 1 FROM json_each(delivered_to) WHERE value = ?)`,
+		subscriber, id, subscriber)
+	return err
+}
+
+func (o *Outbox) Complete(ctx context.Context, id string) error {
+	now := time.Now()
+	if _, err := o.db.ExecContext(ctx, `UPDATE outbox SET delivered_at = ? WHERE id = ?`, unixNano(now), id); err != nil {
+		return err
+	}
+	// There is no TTL index as in MongoDB, expire old events as new ones
+	// are delivered
+	_, err := o.db.ExecContext(ctx, `DELETE FROM outbox WHERE delivered_at < ?`, unixNano(now.Add(-outboxRetention)))
+	return err
+}
+
+func (o *Outbox) Retry(ctx context.Context, id string, attempts int, next time.Time, lastErr string) error {
+	_, err := o.db.ExecContext(ctx, `UPDATE outbox SET attempts = ?, next_attempt_at = ?, last_error = ?
+		WHERE id = ?`, attempts, unixNano(next), lastErr, id)
+	return err
+}
+
+// Fail keeps the event with its last error, like a delivered one it is no
+// longer pending but it is not expired
+func (o *Outbox) Fail(ctx context.Context, id string, attempts int, lastErr string) error {
+	_, err := o.db.ExecContext(ctx, `UPDATE outbox SET attempts = ?, last_error = ?, failed_at = ?
+		WHERE id = ?`, attempts, lastErr, unixNano(time.Now()), id)
+	return err
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"strings"
+	"unicode"
+)
+
+// matchQuery builds an FTS5 query with the semantics of a MongoDB text
+// search: it matches any of the terms, or every quoted phrase when there are
+// phrases, and none of the terms negated with a leading minus. Every term is
+// quoted, so FTS5 operators in the query are searched for as text. An empty
+// result means the query can match nothing.
+func matchQuery(query string) string {
+	var terms, phrases, negated []string
+	for query != "" {
+		query = strings.TrimLeft(query, " \t\r\n")
+		switch {
+		case query == "":
+		case query[0] == '"':
+			phrase, rest, _ := strings.Cut(query[1:], `"`)
+			if searchable(phrase) {
+				phrases = append(phrases, quote(phrase))
+			}
+			query = rest
+		default:
+			end := strings.IndexAny(query, " \t\r\n\"")
+			if end < 0 {
+				end = len(query)
+			}
+			term := query[:end]
+			query = query[end:]
+			if strings.HasPrefix(term, "-") {
+				if term = term[1:]; searchable(term) {
+					negated = append(negated, quote(term))
+				}
+				continue
+			}
+			if searchable(term) {
+				terms = append(terms, quote(term))
+			}
+		}
+	}
+
+	positive := strings.Join(terms, " OR ")
+	if len(phrases) > 0 {
+		positive = strings.Join(phrases, " AND ")
+	}
+	if positive == "" {
+		return ""
+	}
+
+	expr := "(" + positive + ")"
+	for _, term := range negated {
+		expr += " NOT " + term
+	}
+	return expr
+}
+
+// searchable reports whether text has a token the index would hold
+func searchable(text string) bool {
+	return strings.IndexFunc(text, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0
+}
+
+// quote makes text an FTS5 string, which matches its tokens as a phrase
+func quote(text string) string {
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
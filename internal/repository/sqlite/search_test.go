@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{query: "golang  release", want: `("golang" OR "release")`},
+		{query: `go "new release" -beta`, want: `("new release") NOT "beta"`},
+		{query: `"go" "1.22`, want: `("go" AND "1.22")`},
+		{query: `title:go OR NEAR(a`, want: `("title:go" OR "OR" OR "NEAR(a")`},
+		{query: "-beta", want: ""},
+		{query: `  "" - !!`, want: ""},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, matchQuery(tc.query), tc.query)
+	}
+}
@@ -0,0 +1,100 @@
+// Package sqlite stores articles, events and webhooks in a single SQLite
+// file, for deployments without a database server, with the pure Go driver
+// so the binary still builds without cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	// Registers the pure Go driver as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// driverName is the name modernc.org/sqlite registers itself under
+const driverName = "sqlite"
+
+// Open opens the database file at path, creating it and its directory when
+// missing. The database runs in WAL mode, so readers do not block the writer,
+// and transactions take the write lock up front to avoid deadlocks.
+func Open(ctx context.Context, path string, busyTimeout time.Duration) (*sql.DB, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("create data directory: %w", err)
+		}
+	}
+
+	// Pragmas are applied to every connection of the pool
+	params := url.Values{}
+	for _, pragma := range []string{
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+		"foreign_keys(ON)",
+		fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()),
+	} {
+		params.Add("_pragma", pragma)
+	}
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open(driverName, "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// querier runs statements, either on the database or in a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction started by Transactor.WithinTransaction when
+// ctx carries one, and the database otherwise
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// isUniqueViolation reports whether err is a UNIQUE or PRIMARY KEY
+// constraint failure. Drivers do not share an error type, but SQLite's
+// messages are stable.
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// Times are stored as Unix nanoseconds, which sort correctly and do not
+// depend on how a driver formats them. The zero time is stored as 0.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// placeholders returns n comma separated parameter placeholders
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
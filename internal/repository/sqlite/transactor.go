@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"news_service/internal/domain"
+)
+
+type transactor struct {
+	db *sql.DB
+}
+
+// NewTransactor returns a Transactor backed by SQLite transactions
+func NewTransactor(db *sql.DB) domain.Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, t.db, fn)
+}
+
+// withinTransaction runs fn in a transaction carried by its context, joining
+// the transaction of ctx when there is one
+func withinTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// The context carries the transaction to every statement made with it,
+	// including those behind repository decorators
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/webhook"
+)
+
+// deliveryRetention is how long the delivery log is kept
+const deliveryRetention = 30 * 24 * time.Hour
+
+const (
+	endpointColumns = `id, url, description, events, secret, active, created_at, updated_at`
+	deliveryColumns = `id, endpoint_id, event_id, event_type, payload, status, attempts,
+		next_attempt_at, created_at`
+)
+
+// WebhookStore keeps webhook endpoints and deliveries in SQLite
+type WebhookStore struct {
+	db *sql.DB
+}
+
+var _ webhook.Store = (*WebhookStore)(nil)
+
+// NewWebhookStore creates a webhook store in db
+func NewWebhookStore(db *sql.DB) *WebhookStore {
+	return &WebhookStore{db: db}
+}
+
+func scanEndpoint(row scanner) (*webhook.Endpoint, error) {
+	var (
+		endpoint             webhook.Endpoint
+		events               string
+		createdAt, updatedAt int64
+	)
+	err := row.Scan(&endpoint.ID, &endpoint.URL, &endpoint.Description, &events,
+		&endpoint.Secret, &endpoint.Active, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &endpoint.Events); err != nil {
+		return nil, err
+	}
+	if len(endpoint.Events) == 0 {
+		endpoint.Events = nil
+	}
+	endpoint.CreatedAt = fromUnixNano(createdAt)
+	endpoint.UpdatedAt = fromUnixNano(updatedAt)
+	return &endpoint, nil
+}
+
+func scanDelivery(row scanner) (*webhook.Delivery, error) {
+	var (
+		delivery                 webhook.Delivery
+		attempts                 string
+		nextAttemptAt, createdAt int64
+	)
+	err := row.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventID, &delivery.EventType,
+		&delivery.Payload, &delivery.Status, &attempts, &nextAttemptAt, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(attempts), &delivery.Attempts); err != nil {
+		return nil, err
+	}
+	delivery.NextAttemptAt = fromUnixNano(nextAttemptAt)
+	delivery.CreatedAt = fromUnixNano(createdAt)
+	return &delivery, nil
+}
+
+func (s *WebhookStore) CreateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	events, err := json.Marshal(nonNil(endpoint.Events))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	id := primitive.NewObjectID().Hex()
+	_, err = s.db.ExecContext(ctx, `INSERT INTO webhook_endpoints (`+endpointColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, endpoint.URL, endpoint.Description, string(events), endpoint.Secret, endpoint.Active,
+		unixNano(now), unixNano(now))
+	if err != nil {
+		return err
+	}
+
+	endpoint.ID = id
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+	return nil
+}
+
+func (s *WebhookStore) GetEndpoint(ctx context.Context, id string) (*webhook.Endpoint, error) {
+	return scanEndpoint(s.db.QueryRowContext(ctx, `SELECT `+endpointColumns+` FROM webhook_endpoints WHERE id = ?`, id))
+}
+
+func (s *WebhookStore) ListEndpoints(ctx context.Context) ([]*webhook.Endpoint, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+endpointColumns+` FROM webhook_endpoints ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var endpoints []*webhook.Endpoint
+	for rows.Next() {
+		endpoint, err := scanEndpoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, rows.Err()
+}
+
+func (s *WebhookStore) UpdateEndpoint(ctx context.Context, endpoint *webhook.Endpoint) error {
+	events, err := json.Marshal(nonNil(endpoint.Events))
+	if err != nil {
+		return err
+	}
+
+	endpoint.UpdatedAt = time.Now()
+	result, err := s.db.ExecContext(ctx, `UPDATE webhook_endpoints SET
+		url = ?, description = ?, events = ?, secret = ?, active = ?, updated_at = ?
+		WHERE id = ?`,
+		endpoint.URL, endpoint.Description, string(events), endpoint.Secret, endpoint.Active,
+		unixNano(endpoint.UpdatedAt), endpoint.ID)
+	return affectedOne(result, err)
+}
+
+// DeleteEndpoint removes the endpoint, its deliveries go with it through the
+// foreign key
+func (s *WebhookStore) DeleteEndpoint(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = ?`, id)
+	return affectedOne(result, err)
+}
+
+func (s *WebhookStore) Enqueue(ctx context.Context, deliveries ...*webhook.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return withinTransaction(ctx, s.db, func(ctx context.Context) error {
+		for _, delivery := range deliveries {
+			// Deliveries queued by an earlier attempt at the same event are kept
+			_, err := conn(ctx, s.db).ExecContext(ctx, `INSERT INTO webhook_deliveries (`+deliveryColumns+`)
+				VALUES (?, ?, ?, ?, ?, ?, '[]', ?, ?)
+				ON CONFLICT (endpoint_id, event_id) DO NOTHING`,
+				delivery.ID, delivery.EndpointID, delivery.EventID, delivery.EventType, delivery.Payload,
+				delivery.Status, unixNano(delivery.NextAttemptAt), unixNano(delivery.CreatedAt))
+			if err != nil {
+				return err
+			}
+		}
+		// There is no TTL index as in MongoDB, expire the log as it grows
+		_, err := conn(ctx, s.db).ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE created_at < ?`,
+			unixNano(time.Now().Add(-deliveryRetention)))
+		return err
+	})
+}
+
+// Claim needs no row locks, SQLite runs one writing statement at a time
+func (s *WebhookStore) Claim(ctx context.Context, lease time.Duration) (*webhook.Delivery, error) {
+	now := time.Now()
+	delivery, err := scanDelivery(s.db.QueryRowContext(ctx, `UPDATE webhook_deliveries SET next_attempt_at = ?
+		WHERE id = (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at LIMIT 1
+		)
+		RETURNING `+deliveryColumns,
+		unixNano(now.Add(lease)), webhook.StatusPending, unixNano(now)))
+	if errors.Is(err, webhook.ErrNotFound) {
+		return nil, nil
+	}
+	return delivery, err
+}
+
+func (s *WebhookStore) RecordAttempt(ctx context.Context, id string, attempt webhook.Attempt, status string, next time.Time) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET
+		attempts = json_insert(attempts, '$[#]', json(?)), status = ?, next_attempt_at = ?
+		WHERE id = ?`, string(data), status, unixNano(next), id)
+	return err
+}
+
+func (s *WebhookStore) GetDelivery(ctx context.Context, id string) (*webhook.Delivery, error) {
+	return scanDelivery(s.db.QueryRowContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id = ?`, id))
+}
+
+func (s *WebhookStore) ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*webhook.Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries
+		WHERE endpoint_id = ? ORDER BY id DESC LIMIT ?`, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*webhook.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *WebhookStore) Redeliver(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `UPDATE webhook_deliveries SET status = ?, next_attempt_at = ?
+		WHERE id = ?`, webhook.StatusPending, unixNano(time.Now()), id)
+	return affectedOne(result, err)
+}
+
+// affectedOne turns a statement that changed no row into webhook.ErrNotFound
+func affectedOne(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
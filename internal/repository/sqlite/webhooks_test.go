@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/webhook"
+)
+
+func TestWebhookStore_Endpoints(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	store := NewWebhookStore(db)
+	ctx := context.Background()
+
+	endpoint := &webhook.Endpoint{URL: "https://example.com/hook", Secret: "s", Active: true}
+	require.NoError(t, store.CreateEndpoint(ctx, endpoint))
+	require.NotEmpty(t, endpoint.ID)
+
+	endpoint.Events = []string{"ArticleCreated"}
+	require.NoError(t, store.UpdateEndpoint(ctx, endpoint))
+
+	endpoints, err := store.ListEndpoints(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, []string{"ArticleCreated"}, endpoints[0].Events)
+
+	require.NoError(t, store.DeleteEndpoint(ctx, endpoint.ID))
+	_, err = store.GetEndpoint(ctx, endpoint.ID)
+	assert.ErrorIs(t, err, webhook.ErrNotFound)
+	assert.ErrorIs(t, store.DeleteEndpoint(ctx, endpoint.ID), webhook.ErrNotFound)
+}
+
+func TestWebhookStore_Deliveries(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store := NewWebhookStore(db)
+	endpoint := &webhook.Endpoint{URL: "https://example.com/hook", Active: true}
+	require.NoError(t, store.CreateEndpoint(ctx, endpoint))
+
+	delivery := &webhook.Delivery{
+		ID:            "650000000000000000000001",
+		EndpointID:    endpoint.ID,
+		EventID:       "e1",
+		EventType:     "ArticleCreated",
+		Payload:       []byte(`{}`),
+		Status:        webhook.StatusPending,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	require.NoError(t, store.Enqueue(ctx, delivery))
+	// The same event is queued once per endpoint
+	duplicate := *delivery
+	duplicate.ID = "650000000000000000000002"
+	require.NoError(t, store.Enqueue(ctx, &duplicate))
+
+	claimed, err := store.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, delivery.ID, claimed.ID)
+
+	// Leased deliveries are not claimed twice
+	claimed, err = store.Claim(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, claimed)
+
+	attempt := webhook.Attempt{At: time.Now(), ResponseCode: 500, Error: "boom", Duration: time.Second}
+	require.NoError(t, store.RecordAttempt(ctx, delivery.ID, attempt, webhook.StatusFailed, time.Time{}))
+	require.NoError(t, store.Redeliver(ctx, delivery.ID))
+
+	deliveries, err := store.ListDeliveries(ctx, endpoint.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, webhook.StatusPending, deliveries[0].Status)
+	require.Len(t, deliveries[0].Attempts, 1)
+	assert.Equal(t, 500, deliveries[0].Attempts[0].ResponseCode)
+	assert.Equal(t, time.Second, deliveries[0].Attempts[0].Duration)
+}
@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	newsv1 "news_service/api/news/v1"
+	"news_service/internal/domain"
+)
+
+func toArticle(news *domain.News) *newsv1.Article {
+	article := &newsv1.Article{
+		Id:          news.ID.Hex(),
+		Title:       news.Title,
+		Content:     news.Content,
+		Slug:        news.Slug,
+		Status:      news.Status,
+		Tags:        news.Tags,
+		Language:    news.Language,
+		PublishedAt: timestamp(news.PublishedAt),
+		CreateTime:  timestamppb.New(news.CreatedAt),
+		UpdateTime:  timestamppb.New(news.UpdatedAt),
+	}
+	if news.TranslationOf != nil {
+		article.TranslationOf = news.TranslationOf.Hex()
+	}
+	return article
+}
+
+var eventTypes = map[string]newsv1.ArticleEvent_Type{
+	domain.EventArticleCreated: newsv1.ArticleEvent_TYPE_CREATED,
+	domain.EventArticleUpdated: newsv1.ArticleEvent_TYPE_UPDATED,
+	domain.EventArticleDeleted: newsv1.ArticleEvent_TYPE_DELETED,
+}
+
+func toEvent(event domain.Event) *newsv1.ArticleEvent {
+	pb := &newsv1.ArticleEvent{
+		Id:        event.ID,
+		Type:      eventTypes[event.Type],
+		ArticleId: event.ArticleID,
+		OccurTime: timestamppb.New(event.OccurredAt),
+	}
+	if event.Article != nil {
+		pb.Article = toArticle(event.Article)
+	}
+	return pb
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
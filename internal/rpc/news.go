@@ -0,0 +1,230 @@
+// Package rpc serves articles over gRPC to other services, resolving every
+// call through domain.NewsService.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	newsv1 "news_service/api/news/v1"
+	"news_service/internal/broadcast"
+	"news_service/internal/domain"
+)
+
+const (
+	defaultPageSize = 10
+	// maxPageSize caps the page size of lists and searches
+	maxPageSize = 100
+)
+
+// TotalCountHeader carries the number of articles on all pages of a list
+const TotalCountHeader = "x-total-count"
+
+// updatable are the fields UpdateArticle may change, those a repository
+// Update persists
+var updatable = []string{"title", "content"}
+
+// NewsServer implements newsv1.NewsServiceServer
+type NewsServer struct {
+	newsv1.UnimplementedNewsServiceServer
+
+	service     domain.NewsService
+	broadcaster *broadcast.Broadcaster
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewNewsServer(service domain.NewsService, broadcaster *broadcast.Broadcaster) *NewsServer {
+	return &NewsServer{
+		service:     service,
+		broadcaster: broadcaster,
+		done:        make(chan struct{}),
+	}
+}
+
+// Close ends all open watches, which would otherwise hold up a graceful
+// stop of the server
+func (s *NewsServer) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+func (s *NewsServer) GetArticle(ctx context.Context, req *newsv1.GetArticleRequest) (*newsv1.Article, error) {
+	if err := validID(req.GetId()); err != nil {
+		return nil, err
+	}
+	news, err := s.service.GetNewsByID(ctx, req.GetId())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "news not found")
+	}
+	if err != nil {
+		return nil, internal(ctx, "failed to get news", err)
+	}
+	return toArticle(news), nil
+}
+
+func (s *NewsServer) ListArticles(req *newsv1.ListArticlesRequest, stream newsv1.NewsService_ListArticlesServer) error {
+	ctx := stream.Context()
+	page, limit := pagination(req.GetPage(), req.GetPageSize())
+
+	list, total, err := s.service.GetAllNews(ctx, req.GetLanguage(), page, limit)
+	if err != nil {
+		return internal(ctx, "failed to fetch news", err)
+	}
+	if err := stream.SendHeader(metadata.Pairs(TotalCountHeader, strconv.FormatInt(total, 10))); err != nil {
+		return err
+	}
+	for _, news := range list {
+		if err := stream.Send(toArticle(news)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NewsServer) SearchArticles(ctx context.Context, req *newsv1.SearchArticlesRequest) (*newsv1.SearchArticlesResponse, error) {
+	if req.GetQuery() == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	page, limit := pagination(req.GetPage(), req.GetPageSize())
+
+	list, total, err := s.service.SearchNews(ctx, req.GetLanguage(), req.GetQuery(), page, limit)
+	if err != nil {
+		return nil, internal(ctx, "failed to search news", err)
+	}
+	resp := &newsv1.SearchArticlesResponse{Total: total}
+	for _, news := range list {
+		resp.Articles = append(resp.Articles, toArticle(news))
+	}
+	return resp, nil
+}
+
+func (s *NewsServer) CreateArticle(ctx context.Context, req *newsv1.CreateArticleRequest) (*newsv1.Article, error) {
+	article := req.GetArticle()
+	news := &domain.News{
+		Title:    article.GetTitle(),
+		Content:  article.GetContent(),
+		Slug:     article.GetSlug(),
+		Status:   article.GetStatus(),
+		Tags:     article.GetTags(),
+		Language: article.GetLanguage(),
+	}
+	if err := news.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.service.CreateNews(ctx, news); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, internal(ctx, "failed to create news", err)
+	}
+	return toArticle(news), nil
+}
+
+func (s *NewsServer) UpdateArticle(ctx context.Context, req *newsv1.UpdateArticleRequest) (*newsv1.Article, error) {
+	article := req.GetArticle()
+	if err := validID(article.GetId()); err != nil {
+		return nil, err
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		paths = updatable
+	}
+
+	news, err := s.service.GetNewsByID(ctx, article.GetId())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "news not found")
+	}
+	if err != nil {
+		return nil, internal(ctx, "failed to get news", err)
+	}
+	for _, path := range paths {
+		switch path {
+		case "title":
+			news.Title = article.GetTitle()
+		case "content":
+			news.Content = article.GetContent()
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+	}
+
+	if err := news.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.service.UpdateNews(ctx, news); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+		return nil, internal(ctx, "failed to update news", err)
+	}
+	return toArticle(news), nil
+}
+
+func (s *NewsServer) DeleteArticle(ctx context.Context, req *newsv1.DeleteArticleRequest) (*emptypb.Empty, error) {
+	if err := validID(req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := s.service.DeleteNews(ctx, req.GetId()); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, status.Error(codes.NotFound, "news not found")
+		}
+		return nil, internal(ctx, "failed to delete news", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *NewsServer) WatchArticles(req *newsv1.WatchArticlesRequest, stream newsv1.NewsService_WatchArticlesServer) error {
+	events, cancel := s.broadcaster.Subscribe()
+	defer cancel()
+
+	// Let the client know the watch is in place before the first change
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch fell behind")
+			}
+			if err := stream.Send(toEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// validID returns an InvalidArgument error for IDs that cannot exist
+func validID(id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid id")
+	}
+	return nil
+}
+
+// pagination applies the defaults and bounds to a requested page
+func pagination(page, pageSize int32) (int, int) {
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return max(int(page), 1), min(int(pageSize), maxPageSize)
+}
+
+// internal logs err and returns an Internal error in its place
+func internal(ctx context.Context, msg string, err error) error {
+	slog.ErrorContext(ctx, msg, "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,335 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	newsv1 "news_service/api/news/v1"
+	"news_service/internal/broadcast"
+	"news_service/internal/domain"
+)
+
+// fakeService keeps articles in a map and publishes their changes like the
+// real service, the methods the server does not use panic through the nil
+// embedded interface
+type fakeService struct {
+	domain.NewsService
+	news      map[string]*domain.News
+	publisher domain.Publisher
+	deleteErr error
+}
+
+func (s *fakeService) GetNewsByID(ctx context.Context, id string) (*domain.News, error) {
+	if news, ok := s.news[id]; ok {
+		copied := *news
+		return &copied, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (s *fakeService) GetAllNews(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	var list []*domain.News
+	for _, news := range s.news {
+		if lang == "" || news.Language == lang {
+			list = append(list, news)
+		}
+	}
+	return list, int64(len(list)), nil
+}
+
+func (s *fakeService) SearchNews(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	if query == "fail" {
+		return nil, 0, errors.New("connection refused")
+	}
+	list, total, _ := s.GetAllNews(ctx, lang, page, limit)
+	return list, total, nil
+}
+
+func (s *fakeService) CreateNews(ctx context.Context, news *domain.News) error {
+	for _, existing := range s.news {
+		if news.Slug != "" && existing.Slug == news.Slug {
+			return domain.ErrConflict
+		}
+	}
+	news.ID = primitive.NewObjectID()
+	news.ApplyDefaults()
+	s.news[news.ID.Hex()] = news
+	s.publisher.Publish(domain.NewEvent(domain.EventArticleCreated, news.ID.Hex(), news))
+	return nil
+}
+
+func (s *fakeService) UpdateNews(ctx context.Context, news *domain.News) error {
+	s.news[news.ID.Hex()] = news
+	s.publisher.Publish(domain.NewEvent(domain.EventArticleUpdated, news.ID.Hex(), news))
+	return nil
+}
+
+func (s *fakeService) DeleteNews(ctx context.Context, id string) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	delete(s.news, id)
+	s.publisher.Publish(domain.NewEvent(domain.EventArticleDeleted, id, nil))
+	return nil
+}
+
+// testToken is the admin token of test servers
+const testToken = "admin"
+
+// adminContext carries the admin token to the server
+func adminContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+testToken)
+}
+
+func setupServer(t *testing.T, news ...*domain.News) (newsv1.NewsServiceClient, *grpc.ClientConn, *NewsServer) {
+	t.Helper()
+	return setupServerWithToken(t, testToken, news...)
+}
+
+func setupServerWithToken(t *testing.T, token string, news ...*domain.News) (newsv1.NewsServiceClient, *grpc.ClientConn, *NewsServer) {
+	t.Helper()
+	broadcaster := broadcast.New(broadcast.DefaultBuffer)
+	service := &fakeService{news: make(map[string]*domain.News), publisher: broadcaster}
+	for _, n := range news {
+		service.news[n.ID.Hex()] = n
+	}
+
+	newsServer := NewNewsServer(service, broadcaster)
+	server, _ := NewServer(newsServer, token, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return newsv1.NewNewsServiceClient(conn), conn, newsServer
+}
+
+func testArticle(title, lang string) *domain.News {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &domain.News{
+		ID:        primitive.NewObjectID(),
+		Title:     title,
+		Content:   "Content of " + title,
+		Language:  lang,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+}
+
+func TestNewsServer_GetArticle(t *testing.T) {
+	news := testArticle("Golang News", "en")
+	client, _, _ := setupServer(t, news)
+	ctx := context.Background()
+
+	article, err := client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: news.ID.Hex()})
+	require.NoError(t, err)
+	assert.Equal(t, "Golang News", article.GetTitle())
+	assert.Equal(t, news.CreatedAt, article.GetCreateTime().AsTime())
+	assert.Nil(t, article.GetPublishedAt())
+
+	_, err = client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: primitive.NewObjectID().Hex()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: "x"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestNewsServer_ListArticles(t *testing.T) {
+	client, _, _ := setupServer(t, testArticle("English News", "en"), testArticle("Ukrainian News", "uk"))
+
+	stream, err := client.ListArticles(context.Background(), &newsv1.ListArticlesRequest{Language: "uk"})
+	require.NoError(t, err)
+
+	var titles []string
+	for {
+		article, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		titles = append(titles, article.GetTitle())
+	}
+	assert.Equal(t, []string{"Ukrainian News"}, titles)
+
+	header, err := stream.Header()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, header.Get(TotalCountHeader))
+}
+
+func TestNewsServer_SearchArticles(t *testing.T) {
+	client, _, _ := setupServer(t, testArticle("Golang News", "en"))
+	ctx := context.Background()
+
+	resp, err := client.SearchArticles(ctx, &newsv1.SearchArticlesRequest{Query: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.GetTotal())
+	require.Len(t, resp.GetArticles(), 1)
+
+	_, err = client.SearchArticles(ctx, &newsv1.SearchArticlesRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Failures are not passed on to clients
+	_, err = client.SearchArticles(ctx, &newsv1.SearchArticlesRequest{Query: "fail"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+}
+
+func TestNewsServer_Write(t *testing.T) {
+	client, _, newsServer := setupServer(t)
+	ctx := adminContext(context.Background())
+
+	created, err := client.CreateArticle(ctx, &newsv1.CreateArticleRequest{Article: &newsv1.Article{
+		Title:   "Created",
+		Content: "Created content",
+		Slug:    "created",
+		Tags:    []string{"go"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, created.GetStatus())
+	assert.NotNil(t, created.GetPublishedAt())
+
+	_, err = client.CreateArticle(ctx, &newsv1.CreateArticleRequest{Article: &newsv1.Article{Title: "Created", Content: "Created content", Slug: "created"}})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	_, err = client.CreateArticle(ctx, &newsv1.CreateArticleRequest{Article: &newsv1.Article{Title: "No"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	updated, err := client.UpdateArticle(ctx, &newsv1.UpdateArticleRequest{
+		Article:    &newsv1.Article{Id: created.GetId(), Title: "Updated"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", updated.GetTitle())
+	assert.Equal(t, "Created content", updated.GetContent())
+	assert.Equal(t, []string{"go"}, updated.GetTags())
+
+	// Without a mask the title and content are replaced
+	updated, err = client.UpdateArticle(ctx, &newsv1.UpdateArticleRequest{
+		Article: &newsv1.Article{Id: created.GetId(), Title: "Replaced", Content: "Replaced content", Status: domain.StatusDraft},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Replaced content", updated.GetContent())
+	assert.Equal(t, []string{"go"}, updated.GetTags())
+	assert.Equal(t, domain.StatusPublished, updated.GetStatus())
+	assert.Equal(t, "created", updated.GetSlug())
+
+	// Fields a repository update would not store are refused
+	for _, path := range []string{"slug", "status", "tags", "language"} {
+		_, err = client.UpdateArticle(ctx, &newsv1.UpdateArticleRequest{
+			Article:    &newsv1.Article{Id: created.GetId()},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{path}},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), path)
+	}
+	_, err = client.UpdateArticle(ctx, &newsv1.UpdateArticleRequest{Article: &newsv1.Article{Id: primitive.NewObjectID().Hex()}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteArticle(ctx, &newsv1.DeleteArticleRequest{Id: created.GetId()})
+	require.NoError(t, err)
+	_, err = client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: created.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	newsServer.service.(*fakeService).deleteErr = domain.ErrNotFound
+	_, err = client.DeleteArticle(ctx, &newsv1.DeleteArticleRequest{Id: created.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestNewsServer_RequiresToken(t *testing.T) {
+	news := testArticle("Golang News", "en")
+	client, _, _ := setupServer(t, news)
+	ctx := context.Background()
+
+	// Reading needs no token
+	_, err := client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: news.ID.Hex()})
+	require.NoError(t, err)
+
+	wrong := metadata.AppendToOutgoingContext(ctx, "x-api-token", "wrong")
+	for _, ctx := range []context.Context{ctx, wrong} {
+		_, err = client.CreateArticle(ctx, &newsv1.CreateArticleRequest{Article: &newsv1.Article{Title: "Created", Content: "Created content"}})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, err = client.UpdateArticle(ctx, &newsv1.UpdateArticleRequest{Article: &newsv1.Article{Id: news.ID.Hex(), Title: "Updated"}})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		_, err = client.DeleteArticle(ctx, &newsv1.DeleteArticleRequest{Id: news.ID.Hex()})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+	_, err = client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: news.ID.Hex()})
+	require.NoError(t, err, "nothing was deleted")
+
+	_, err = client.DeleteArticle(metadata.AppendToOutgoingContext(ctx, "x-api-token", testToken), &newsv1.DeleteArticleRequest{Id: news.ID.Hex()})
+	require.NoError(t, err)
+
+	// Without a configured token writes are disabled
+	client, _, _ = setupServerWithToken(t, "")
+	_, err = client.DeleteArticle(adminContext(ctx), &newsv1.DeleteArticleRequest{Id: news.ID.Hex()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestNewsServer_WatchArticles(t *testing.T) {
+	client, _, newsServer := setupServer(t)
+	ctx, cancel := context.WithTimeout(adminContext(context.Background()), 5*time.Second)
+	defer cancel()
+
+	watch, err := client.WatchArticles(ctx, &newsv1.WatchArticlesRequest{})
+	require.NoError(t, err)
+	// The header arrives once the subscription is in place
+	_, err = watch.Header()
+	require.NoError(t, err)
+
+	created, err := client.CreateArticle(ctx, &newsv1.CreateArticleRequest{Article: &newsv1.Article{Title: "Watched", Content: "Watched content"}})
+	require.NoError(t, err)
+	_, err = client.DeleteArticle(ctx, &newsv1.DeleteArticleRequest{Id: created.GetId()})
+	require.NoError(t, err)
+
+	event, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, newsv1.ArticleEvent_TYPE_CREATED, event.GetType())
+	assert.Equal(t, "Watched", event.GetArticle().GetTitle())
+
+	event, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, newsv1.ArticleEvent_TYPE_DELETED, event.GetType())
+	assert.Equal(t, created.GetId(), event.GetArticleId())
+	assert.Nil(t, event.GetArticle())
+
+	newsServer.Close()
+	_, err = watch.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestNewServer_HealthAndRequestID(t *testing.T) {
+	news := testArticle("Golang News", "en")
+	client, conn, _ := setupServer(t, news)
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: newsv1.NewsService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDKey, "req-1")
+	_, err = client.GetArticle(ctx, &newsv1.GetArticleRequest{Id: news.ID.Hex()}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get(requestIDKey))
+}
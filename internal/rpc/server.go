@@ -0,0 +1,165 @@
+package rpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	newsv1 "news_service/api/news/v1"
+	"news_service/internal/logging"
+)
+
+// requestIDKey is the metadata key used to receive and return the request ID
+const requestIDKey = "x-request-id"
+
+// NewServer creates a gRPC server offering news along with the standard
+// health and reflection services. The returned health server reports news
+// as serving until it is shut down. Calls changing articles require
+// adminToken, and are refused while it is empty.
+func NewServer(news *NewsServer, adminToken string, logger *slog.Logger) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryLogger(logger), adminAuth(adminToken)),
+		grpc.ChainStreamInterceptor(streamLogger(logger)),
+	)
+	newsv1.RegisterNewsServiceServer(server, news)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(newsv1.NewsService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+	return server, healthServer
+}
+
+func unaryLogger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		ctx = withRequestID(ctx)
+		start := time.Now()
+		defer func() {
+			err = recovered(ctx, logger, recover(), err)
+			logCall(ctx, logger, info.FullMethod, start, err)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func streamLogger(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		ctx := withRequestID(stream.Context())
+		start := time.Now()
+		defer func() {
+			err = recovered(ctx, logger, recover(), err)
+			logCall(ctx, logger, info.FullMethod, start, err)
+		}()
+		return handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// writeMethods change articles and need the admin token
+var writeMethods = map[string]bool{
+	newsv1.NewsService_CreateArticle_FullMethodName: true,
+	newsv1.NewsService_UpdateArticle_FullMethodName: true,
+	newsv1.NewsService_DeleteArticle_FullMethodName: true,
+}
+
+// adminAuth requires token, as a bearer authorization or x-api-token, for
+// the calls in writeMethods
+func adminAuth(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !writeMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		if token == "" {
+			return nil, status.Error(codes.PermissionDenied, "writes are disabled while no admin token is set")
+		}
+		if subtle.ConstantTimeCompare([]byte(apiToken(ctx)), []byte(token)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// apiToken returns the token the client sent in its metadata
+func apiToken(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, "x-api-token"); len(values) > 0 {
+		return values[0]
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		return strings.TrimPrefix(values[0], "Bearer ")
+	}
+	return ""
+}
+
+// contextStream replaces the context of a stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// withRequestID stores the request ID, reusing a valid incoming one, in the
+// context and returns it to the client
+func withRequestID(ctx context.Context) context.Context {
+	var id string
+	if values := metadata.ValueFromIncomingContext(ctx, requestIDKey); len(values) > 0 && len(values[0]) <= 128 {
+		id = values[0]
+	}
+	if id == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err == nil {
+			id = hex.EncodeToString(b)
+		}
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, id))
+	return logging.WithRequestID(ctx, id)
+}
+
+// recovered turns a panic into an Internal error so it does not take the
+// process down
+func recovered(ctx context.Context, logger *slog.Logger, p any, err error) error {
+	if p == nil {
+		return err
+	}
+	logger.ErrorContext(ctx, "panic recovered", "panic", p, "stack", string(debug.Stack()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// logCall writes one structured access log record per call
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK, codes.Canceled:
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	logger.LogAttrs(ctx, level, "rpc", attrs...)
+}
@@ -1,40 +1,244 @@
 package service
 
 import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"news_service/internal/domain"
+	"news_service/internal/related"
+	"news_service/internal/tracing"
 )
 
 type newsService struct {
-	repo domain.NewsRepository
+	repo      domain.NewsRepository
+	outbox    domain.Outbox
+	tx        domain.Transactor
+	publisher domain.Publisher
+	related   *related.Recommender
+	tracer    trace.Tracer
 }
 
-// NewNewsService creates a new instance of news service
-func NewNewsService(repo domain.NewsRepository) domain.NewsService {
+// NewNewsService creates a new instance of news service. Every change is
+// written to outbox as a domain event within the same transaction, and
+// handed to publisher once committed.
+func NewNewsService(repo domain.NewsRepository, outbox domain.Outbox, tx domain.Transactor, publisher domain.Publisher) domain.NewsService {
 	return &newsService{
-		repo: repo,
+		repo:      repo,
+		outbox:    outbox,
+		tx:        tx,
+		publisher: publisher,
+		related:   related.New(repo, related.DefaultOptions()),
+		tracer:    tracing.Tracer(),
+	}
+}
+
+func (s *newsService) CreateNews(ctx context.Context, news *domain.News) (err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.CreateNews")
+	defer func() { tracing.End(span, err) }()
+
+	news.ApplyDefaults()
+	return s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		if err := s.repo.Create(ctx, news); err != nil {
+			return nil, err
+		}
+		return []domain.Event{domain.NewEvent(domain.EventArticleCreated, news.ID.Hex(), news)}, nil
+	})
+}
+
+func (s *newsService) GetNewsByID(ctx context.Context, id string) (_ *domain.News, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.GetNewsByID", trace.WithAttributes(attribute.String("news.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *newsService) GetAllNews(ctx context.Context, lang string, page, limit int) (_ []*domain.News, _ int64, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.GetAllNews", trace.WithAttributes(attribute.String("lang", lang)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.GetAll(ctx, lang, page, limit)
+}
+
+func (s *newsService) UpdateNews(ctx context.Context, news *domain.News) (err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.UpdateNews", trace.WithAttributes(attribute.String("news.id", news.ID.Hex())))
+	defer func() { tracing.End(span, err) }()
+
+	return s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		if err := s.repo.Update(ctx, news); err != nil {
+			return nil, err
+		}
+		// Callers may pass only the edited fields, publish what was stored
+		stored, err := s.repo.GetByID(ctx, news.ID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Event{domain.NewEvent(domain.EventArticleUpdated, stored.ID.Hex(), stored)}, nil
+	})
+}
+
+func (s *newsService) DeleteNews(ctx context.Context, id string) (err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.DeleteNews", trace.WithAttributes(attribute.String("news.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return nil, err
+		}
+		return []domain.Event{domain.NewEvent(domain.EventArticleDeleted, id, nil)}, nil
+	})
+}
+
+func (s *newsService) SearchNews(ctx context.Context, lang, query string, page, limit int) (_ []*domain.News, _ int64, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.SearchNews", trace.WithAttributes(attribute.String("lang", lang), attribute.String("query", query)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.Search(ctx, lang, query, page, limit)
+}
+
+func (s *newsService) CreateTranslation(ctx context.Context, id string, news *domain.News) (err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.CreateTranslation", trace.WithAttributes(attribute.String("news.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	if !domain.SupportedLanguage(news.Language) {
+		return fmt.Errorf("%w: unsupported language %q", domain.ErrInvalidTranslation, news.Language)
+	}
+	variants, err := s.repo.Translations(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(variants) == 0 {
+		return fmt.Errorf("%w: %s", domain.ErrNotFound, id)
+	}
+	for _, variant := range variants {
+		if variant.Language == news.Language {
+			return fmt.Errorf("%w: a %s variant exists", domain.ErrConflict, news.Language)
+		}
 	}
+
+	group := variants[0].TranslationGroup()
+	news.TranslationOf = &group
+	return s.CreateNews(ctx, news)
 }
 
-func (s *newsService) CreateNews(news *domain.News) error {
-	return s.repo.Create(news)
+func (s *newsService) GetTranslations(ctx context.Context, id string) (_ []*domain.News, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.GetTranslations", trace.WithAttributes(attribute.String("news.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.repo.Translations(ctx, id)
 }
 
-func (s *newsService) GetNewsByID(id string) (*domain.News, error) {
-	return s.repo.GetByID(id)
+func (s *newsService) ImportNews(ctx context.Context, news *domain.News, upsert bool) (created bool, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.ImportNews", trace.WithAttributes(attribute.Bool("upsert", upsert)))
+	defer func() { tracing.End(span, err) }()
+
+	err = s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		if upsert {
+			var err error
+			if created, err = s.repo.Upsert(ctx, news); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := s.repo.Insert(ctx, news); err != nil {
+				return nil, err
+			}
+			created = true
+		}
+
+		eventType := domain.EventArticleUpdated
+		if created {
+			eventType = domain.EventArticleCreated
+		}
+		return []domain.Event{domain.NewEvent(eventType, news.ID.Hex(), news)}, nil
+	})
+	return created, err
 }
 
-func (s *newsService) GetAllNews(page, limit int) ([]*domain.News, int64, error) {
-	return s.repo.GetAll(page, limit)
+func (s *newsService) GetRelatedNews(ctx context.Context, id string, limit int) (_ []*domain.News, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.GetRelatedNews", trace.WithAttributes(attribute.String("news.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.related.Related(ctx, id, limit)
 }
 
-func (s *newsService) UpdateNews(news *domain.News) error {
-	return s.repo.Update(news)
+func (s *newsService) DeleteNewsBatch(ctx context.Context, ids []string) (_ []domain.BatchResult, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.DeleteNewsBatch", trace.WithAttributes(attribute.Int("batch.size", len(ids))))
+	defer func() { tracing.End(span, err) }()
+
+	if err := domain.ValidateBatchIDs(ids); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBatch, err)
+	}
+	return s.batch(ctx, domain.EventArticleDeleted, func(ctx context.Context) ([]domain.BatchResult, error) {
+		return s.repo.DeleteMany(ctx, ids)
+	})
+}
+
+func (s *newsService) UpdateNewsBatch(ctx context.Context, ids []string, update domain.BatchUpdate) (_ []domain.BatchResult, err error) {
+	ctx, span := s.tracer.Start(ctx, "newsService.UpdateNewsBatch", trace.WithAttributes(attribute.Int("batch.size", len(ids))))
+	defer func() { tracing.End(span, err) }()
+
+	update.Tags = domain.NormalizeTags(update.Tags)
+	if err := domain.ValidateBatchIDs(ids); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBatch, err)
+	}
+	if err := update.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidBatch, err)
+	}
+	return s.batch(ctx, domain.EventArticleUpdated, func(ctx context.Context) ([]domain.BatchResult, error) {
+		return s.repo.UpdateMany(ctx, ids, update)
+	})
 }
 
-func (s *newsService) DeleteNews(id string) error {
-	return s.repo.Delete(id)
+// batch runs a batch write and records an event for every article it changed.
+// Updated articles are read back once here, so subscribers need not each
+// load them.
+func (s *newsService) batch(ctx context.Context, eventType string, write func(context.Context) ([]domain.BatchResult, error)) ([]domain.BatchResult, error) {
+	var results []domain.BatchResult
+	err := s.write(ctx, func(ctx context.Context) ([]domain.Event, error) {
+		var err error
+		if results, err = write(ctx); err != nil {
+			return nil, err
+		}
+
+		var events []domain.Event
+		for _, result := range results {
+			if !result.OK {
+				continue
+			}
+			var article *domain.News
+			if eventType == domain.EventArticleUpdated {
+				if article, err = s.repo.GetByID(ctx, result.ID); err != nil {
+					return nil, err
+				}
+			}
+			events = append(events, domain.NewEvent(eventType, result.ID, article))
+		}
+		return events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
 }
 
-func (s *newsService) SearchNews(query string, page, limit int) ([]*domain.News, int64, error) {
-	return s.repo.Search(query, page, limit)
+// write runs fn in a transaction together with appending the events it
+// returns to the outbox, and publishes them after the commit. The related
+// articles catch up with the change before anyone else hears of it.
+func (s *newsService) write(ctx context.Context, fn func(ctx context.Context) ([]domain.Event, error)) error {
+	var events []domain.Event
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if events, err = fn(ctx); err != nil {
+			return err
+		}
+		return s.outbox.Append(ctx, events...)
+	})
+	if err != nil {
+		return err
+	}
+	s.related.Publish(events...)
+	s.publisher.Publish(events...)
+	return nil
 }
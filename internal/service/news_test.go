@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"news_service/internal/domain"
 )
@@ -13,12 +17,12 @@ type MockNewsRepository struct {
 	mock.Mock
 }
 
-func (m *MockNewsRepository) Create(news *domain.News) error {
+func (m *MockNewsRepository) Create(ctx context.Context, news *domain.News) error {
 	args := m.Called(news)
 	return args.Error(0)
 }
 
-func (m *MockNewsRepository) GetByID(id string) (*domain.News, error) {
+func (m *MockNewsRepository) GetByID(ctx context.Context, id string) (*domain.News, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -26,29 +30,104 @@ func (m *MockNewsRepository) GetByID(id string) (*domain.News, error) {
 	return args.Get(0).(*domain.News), args.Error(1)
 }
 
-func (m *MockNewsRepository) GetAll(page, limit int) ([]*domain.News, int64, error) {
-	args := m.Called(page, limit)
+func (m *MockNewsRepository) GetAll(ctx context.Context, lang string, page, limit int) ([]*domain.News, int64, error) {
+	args := m.Called(lang, page, limit)
 	return args.Get(0).([]*domain.News), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockNewsRepository) Update(news *domain.News) error {
+func (m *MockNewsRepository) Update(ctx context.Context, news *domain.News) error {
 	args := m.Called(news)
 	return args.Error(0)
 }
 
-func (m *MockNewsRepository) Delete(id string) error {
+func (m *MockNewsRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockNewsRepository) Search(query string, page, limit int) ([]*domain.News, int64, error) {
-	args := m.Called(query, page, limit)
+func (m *MockNewsRepository) Search(ctx context.Context, lang, query string, page, limit int) ([]*domain.News, int64, error) {
+	args := m.Called(lang, query, page, limit)
 	return args.Get(0).([]*domain.News), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockNewsRepository) Insert(ctx context.Context, news *domain.News) error {
+	args := m.Called(news)
+	return args.Error(0)
+}
+
+func (m *MockNewsRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	args := m.Called(news)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNewsRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	args := m.Called()
+	for _, news := range args.Get(0).([]*domain.News) {
+		if err := fn(news); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockNewsRepository) DeleteMany(ctx context.Context, ids []string) ([]domain.BatchResult, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]domain.BatchResult), args.Error(1)
+}
+
+func (m *MockNewsRepository) UpdateMany(ctx context.Context, ids []string, update domain.BatchUpdate) ([]domain.BatchResult, error) {
+	args := m.Called(ids, update)
+	return args.Get(0).([]domain.BatchResult), args.Error(1)
+}
+
+func (m *MockNewsRepository) Translations(ctx context.Context, id string) ([]*domain.News, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.News), args.Error(1)
+}
+
+// recordingOutbox keeps appended events
+type recordingOutbox struct {
+	events []domain.Event
+}
+
+func (o *recordingOutbox) Append(ctx context.Context, events ...domain.Event) error {
+	o.events = append(o.events, events...)
+	return nil
+}
+
+// recordingPublisher keeps published events
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(events ...domain.Event) {
+	p.events = append(p.events, events...)
+}
+
+// failingTransactor runs fn and then fails to commit
+type failingTransactor struct{}
+
+func (failingTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return errors.New("commit failed")
+}
+
+// directTransactor runs fn without a transaction
+type directTransactor struct{}
+
+func (directTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 func TestNewsService_CreateNews(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
-	service := NewNewsService(mockRepo)
+	outbox := &recordingOutbox{}
+	service := NewNewsService(mockRepo, outbox, directTransactor{}, &recordingPublisher{})
 
 	news := &domain.News{
 		Title:   "Test News",
@@ -57,14 +136,20 @@ func TestNewsService_CreateNews(t *testing.T) {
 
 	mockRepo.On("Create", news).Return(nil)
 
-	err := service.CreateNews(news)
+	err := service.CreateNews(context.Background(), news)
 	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusPublished, news.Status)
+	assert.Contains(t, news.Slug, "test-news-")
+	assert.NotNil(t, news.PublishedAt)
+	require.Len(t, outbox.events, 1)
+	assert.Equal(t, domain.EventArticleCreated, outbox.events[0].Type)
+	assert.Equal(t, news, outbox.events[0].Article)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestNewsService_GetNewsByID(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
-	service := NewNewsService(mockRepo)
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, &recordingPublisher{})
 
 	expectedNews := &domain.News{
 		Title:   "Test News",
@@ -73,7 +158,7 @@ func TestNewsService_GetNewsByID(t *testing.T) {
 
 	mockRepo.On("GetByID", "test-id").Return(expectedNews, nil)
 
-	news, err := service.GetNewsByID("test-id")
+	news, err := service.GetNewsByID(context.Background(), "test-id")
 	assert.NoError(t, err)
 	assert.Equal(t, expectedNews, news)
 	mockRepo.AssertExpectations(t)
@@ -81,16 +166,16 @@ func TestNewsService_GetNewsByID(t *testing.T) {
 
 func TestNewsService_GetAllNews(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
-	service := NewNewsService(mockRepo)
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, &recordingPublisher{})
 
 	expectedNews := []*domain.News{
 		{Title: "News 1", Content: "Content 1"},
 		{Title: "News 2", Content: "Content 2"},
 	}
 
-	mockRepo.On("GetAll", 1, 10).Return(expectedNews, int64(2), nil)
+	mockRepo.On("GetAll", "en", 1, 10).Return(expectedNews, int64(2), nil)
 
-	news, total, err := service.GetAllNews(1, 10)
+	news, total, err := service.GetAllNews(context.Background(), "en", 1, 10)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedNews, news)
 	assert.Equal(t, int64(2), total)
@@ -99,44 +184,215 @@ func TestNewsService_GetAllNews(t *testing.T) {
 
 func TestNewsService_UpdateNews(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
-	service := NewNewsService(mockRepo)
+	outbox := &recordingOutbox{}
+	service := NewNewsService(mockRepo, outbox, directTransactor{}, &recordingPublisher{})
 
 	news := &domain.News{
+		ID:      primitive.NewObjectID(),
 		Title:   "Updated News",
 		Content: "Updated Content",
 	}
+	stored := &domain.News{
+		ID:       news.ID,
+		Title:    news.Title,
+		Content:  news.Content,
+		Status:   domain.StatusPublished,
+		Language: domain.DefaultLanguage,
+	}
 
 	mockRepo.On("Update", news).Return(nil)
+	mockRepo.On("GetByID", news.ID.Hex()).Return(stored, nil)
 
-	err := service.UpdateNews(news)
+	err := service.UpdateNews(context.Background(), news)
 	assert.NoError(t, err)
+	require.Len(t, outbox.events, 1)
+	assert.Equal(t, domain.EventArticleUpdated, outbox.events[0].Type)
+	assert.Same(t, stored, outbox.events[0].Article, "the stored article is published")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewsService_UpdateKeepsRelated(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, &recordingPublisher{})
+	ctx := context.Background()
+
+	article := func(title, content string) *domain.News {
+		return &domain.News{
+			ID:       primitive.NewObjectID(),
+			Title:    title,
+			Content:  content,
+			Status:   domain.StatusPublished,
+			Language: domain.DefaultLanguage,
+		}
+	}
+	source := article("Rocket launch", "The rocket reached orbit.")
+	match := article("Second rocket launch", "Another rocket reached orbit.")
+	mockRepo.On("Each").Return([]*domain.News{source, match}, nil)
+
+	related, err := service.GetRelatedNews(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	require.Len(t, related, 1)
+
+	// An edit through the web form only carries the title and content
+	edit := &domain.News{ID: match.ID, Title: "Second rocket launch", Content: "Another rocket reached orbit today."}
+	stored := *match
+	stored.Content = edit.Content
+	mockRepo.On("Update", edit).Return(nil)
+	mockRepo.On("GetByID", match.ID.Hex()).Return(&stored, nil)
+	require.NoError(t, service.UpdateNews(ctx, edit))
+
+	related, err = service.GetRelatedNews(ctx, source.ID.Hex(), 10)
+	require.NoError(t, err)
+	require.Len(t, related, 1, "the edited article stays related")
+	assert.Equal(t, stored.Content, related[0].Content)
+}
+
+func TestNewsService_ImportNews(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	outbox := &recordingOutbox{}
+	publisher := &recordingPublisher{}
+	service := NewNewsService(mockRepo, outbox, directTransactor{}, publisher)
+
+	inserted := &domain.News{ID: primitive.NewObjectID(), Title: "Imported", Content: "Imported content"}
+	replaced := &domain.News{ID: primitive.NewObjectID(), Title: "Replaced", Content: "Replaced content"}
+	conflict := &domain.News{ID: inserted.ID, Title: "Conflict", Content: "Conflict content"}
+	mockRepo.On("Insert", inserted).Return(nil)
+	mockRepo.On("Upsert", replaced).Return(false, nil)
+	mockRepo.On("Insert", conflict).Return(domain.ErrConflict)
+
+	created, err := service.ImportNews(context.Background(), inserted, false)
+	require.NoError(t, err)
+	assert.True(t, created)
+	created, err = service.ImportNews(context.Background(), replaced, true)
+	require.NoError(t, err)
+	assert.False(t, created)
+	_, err = service.ImportNews(context.Background(), conflict, false)
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	require.Len(t, outbox.events, 2)
+	assert.Equal(t, domain.EventArticleCreated, outbox.events[0].Type)
+	assert.Equal(t, inserted.ID.Hex(), outbox.events[0].ArticleID)
+	assert.Equal(t, domain.EventArticleUpdated, outbox.events[1].Type)
+	assert.Equal(t, replaced, outbox.events[1].Article)
+	assert.Len(t, publisher.events, 2)
 	mockRepo.AssertExpectations(t)
 }
 
 func TestNewsService_DeleteNews(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
-	service := NewNewsService(mockRepo)
+	outbox := &recordingOutbox{}
+	service := NewNewsService(mockRepo, outbox, directTransactor{}, &recordingPublisher{})
 
 	mockRepo.On("Delete", "test-id").Return(nil)
 
-	err := service.DeleteNews("test-id")
+	err := service.DeleteNews(context.Background(), "test-id")
 	assert.NoError(t, err)
+	require.Len(t, outbox.events, 1)
+	assert.Equal(t, domain.EventArticleDeleted, outbox.events[0].Type)
+	assert.Equal(t, "test-id", outbox.events[0].ArticleID)
+	assert.Nil(t, outbox.events[0].Article)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestNewsService_PublishesAfterCommit(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	mockRepo.On("Delete", "test-id").Return(nil)
+
+	publisher := &recordingPublisher{}
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, publisher)
+	require.NoError(t, service.DeleteNews(context.Background(), "test-id"))
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, domain.EventArticleDeleted, publisher.events[0].Type)
+
+	publisher = &recordingPublisher{}
+	service = NewNewsService(mockRepo, &recordingOutbox{}, failingTransactor{}, publisher)
+	assert.Error(t, service.DeleteNews(context.Background(), "test-id"))
+	assert.Empty(t, publisher.events)
+}
+
 func TestNewsService_SearchNews(t *testing.T) {
 	mockRepo := new(MockNewsRepository)
-	service := NewNewsService(mockRepo)
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, &recordingPublisher{})
 
 	expectedNews := []*domain.News{
 		{Title: "Golang News", Content: "Go programming language"},
 	}
 
-	mockRepo.On("Search", "golang", 1, 10).Return(expectedNews, int64(1), nil)
+	mockRepo.On("Search", "", "golang", 1, 10).Return(expectedNews, int64(1), nil)
 
-	news, total, err := service.SearchNews("golang", 1, 10)
+	news, total, err := service.SearchNews(context.Background(), "", "golang", 1, 10)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedNews, news)
 	assert.Equal(t, int64(1), total)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestNewsService_CreateTranslation(t *testing.T) {
+	original := &domain.News{ID: primitive.NewObjectID(), Title: "Hello", Language: "en"}
+	mockRepo := new(MockNewsRepository)
+	mockRepo.On("Translations", original.ID.Hex()).Return([]*domain.News{original}, nil)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.News")).Return(nil)
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, &recordingPublisher{})
+
+	translation := &domain.News{Title: "Привіт", Content: "Переклад статті", Language: "uk"}
+	require.NoError(t, service.CreateTranslation(context.Background(), original.ID.Hex(), translation))
+	require.NotNil(t, translation.TranslationOf)
+	assert.Equal(t, original.ID, *translation.TranslationOf)
+	assert.Equal(t, domain.StatusPublished, translation.Status)
+
+	err := service.CreateTranslation(context.Background(), original.ID.Hex(), &domain.News{Language: "en"})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	err = service.CreateTranslation(context.Background(), original.ID.Hex(), &domain.News{Language: "de"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTranslation)
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestNewsService_UpdateNewsBatch(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	outbox := &recordingOutbox{}
+	service := NewNewsService(mockRepo, outbox, directTransactor{}, &recordingPublisher{})
+
+	ids := []string{"1", "2"}
+	results := []domain.BatchResult{{ID: "1", OK: true}, {ID: "2", Error: "news not found"}}
+	updated := &domain.News{Title: "Tagged", Tags: []string{"go"}}
+	mockRepo.On("UpdateMany", ids, domain.BatchUpdate{Tags: []string{"go"}}).Return(results, nil)
+	mockRepo.On("GetByID", "1").Return(updated, nil).Once()
+
+	got, err := service.UpdateNewsBatch(context.Background(), ids, domain.BatchUpdate{Tags: []string{" Go", "go"}})
+	assert.NoError(t, err)
+	assert.Equal(t, results, got)
+	require.Len(t, outbox.events, 1, "only changed articles get events")
+	assert.Equal(t, "1", outbox.events[0].ArticleID)
+	assert.Same(t, updated, outbox.events[0].Article, "subscribers get the updated article")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewsService_BatchValidation(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	service := NewNewsService(mockRepo, &recordingOutbox{}, directTransactor{}, &recordingPublisher{})
+
+	_, err := service.DeleteNewsBatch(context.Background(), nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+
+	_, err = service.UpdateNewsBatch(context.Background(), []string{"1"}, domain.BatchUpdate{Status: "hidden"})
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+
+	_, err = service.UpdateNewsBatch(context.Background(), []string{"1"}, domain.BatchUpdate{Tags: []string{"  "}})
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+
+	mockRepo.AssertNotCalled(t, "DeleteMany", mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdateMany", mock.Anything, mock.Anything)
+}
+
+func TestNewsService_NoEventOnFailure(t *testing.T) {
+	mockRepo := new(MockNewsRepository)
+	outbox := &recordingOutbox{}
+	service := NewNewsService(mockRepo, outbox, directTransactor{}, &recordingPublisher{})
+
+	mockRepo.On("Delete", "test-id").Return(errors.New("boom"))
+
+	err := service.DeleteNews(context.Background(), "test-id")
+	assert.EqualError(t, err, "boom")
+	assert.Empty(t, outbox.events)
+}
@@ -0,0 +1,78 @@
+package transfer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+// Export writes every article to w in the given format and returns how many
+// were written. Articles are streamed from the repository, not buffered.
+func Export(ctx context.Context, repo domain.NewsRepository, w io.Writer, format Format) (int, error) {
+	var write func(*domain.News) error
+	var flush func() error
+
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return 0, err
+		}
+		write = func(news *domain.News) error { return cw.Write(csvRow(news)) }
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		write = func(news *domain.News) error { return enc.Encode(news) }
+		flush = func() error { return nil }
+	}
+
+	count := 0
+	err := repo.Each(ctx, func(news *domain.News) error {
+		if err := write(news); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, flush()
+}
+
+func csvRow(news *domain.News) []string {
+	publishedAt := ""
+	if news.PublishedAt != nil {
+		publishedAt = formatTime(*news.PublishedAt)
+	}
+	translationOf := ""
+	if news.TranslationOf != nil {
+		translationOf = news.TranslationOf.Hex()
+	}
+	return []string{
+		news.ID.Hex(),
+		news.Title,
+		news.Content,
+		news.Slug,
+		news.Status,
+		strings.Join(news.Tags, ","),
+		news.Language,
+		translationOf,
+		publishedAt,
+		formatTime(news.CreatedAt),
+		formatTime(news.UpdatedAt),
+	}
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
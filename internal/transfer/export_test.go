@@ -0,0 +1,77 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+func exportFixture() *memRepository {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	id, _ := primitive.ObjectIDFromHex("65a000000000000000000001")
+	original, _ := primitive.ObjectIDFromHex("65a000000000000000000002")
+	return &memRepository{news: []*domain.News{{
+		ID:            id,
+		Title:         "Exported <article>",
+		Content:       "Content, with \"quotes\"",
+		Slug:          "exported-article",
+		Status:        domain.StatusDraft,
+		Tags:          []string{"go", "release"},
+		Language:      "uk",
+		TranslationOf: &original,
+		CreatedAt:     created,
+		UpdatedAt:     created,
+	}}}
+}
+
+func TestExport_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	count, err := Export(context.Background(), exportFixture(), &buf, FormatJSONL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, `{"id":"65a000000000000000000001","title":"Exported <article>","content":"Content, with \"quotes\"","slug":"exported-article","status":"draft","tags":["go","release"],"language":"uk","translation_of":"65a000000000000000000002","created_at":"2020-01-02T03:04:05Z","updated_at":"2020-01-02T03:04:05Z"}`+"\n", buf.String())
+}
+
+func TestExport_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Export(context.Background(), exportFixture(), &buf, FormatCSV)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, columns, rows[0])
+	assert.Equal(t, []string{
+		"65a000000000000000000001", "Exported <article>", "Content, with \"quotes\"", "exported-article",
+		"draft", "go,release", "uk", "65a000000000000000000002", "", "2020-01-02T03:04:05Z", "2020-01-02T03:04:05Z",
+	}, rows[1])
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSONL, FormatCSV} {
+		var buf bytes.Buffer
+		source := exportFixture()
+		_, err := Export(context.Background(), source, &buf, format)
+		require.NoError(t, err)
+
+		target := &memRepository{}
+		report, err := Import(context.Background(), target, &buf, ImportOptions{Format: format, PreserveIDs: true})
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Created, format)
+		assert.Equal(t, source.news[0].ID, target.news[0].ID, format)
+		assert.True(t, source.news[0].CreatedAt.Equal(target.news[0].CreatedAt), format)
+		assert.Equal(t, source.news[0].Slug, target.news[0].Slug, format)
+		assert.Equal(t, source.news[0].Tags, target.news[0].Tags, format)
+		assert.Equal(t, "uk", target.news[0].Language, format)
+		assert.Equal(t, source.news[0].TranslationOf, target.news[0].TranslationOf, format)
+	}
+}
@@ -0,0 +1,236 @@
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+// maxLineSize bounds a single JSON Lines record
+const maxLineSize = 16 << 20
+
+// ImportOptions controls an import
+type ImportOptions struct {
+	Format Format
+	Mode   Mode
+	// PreserveIDs keeps the IDs and creation and update times of the records.
+	// Without it every record gets a new ID and the current time.
+	PreserveIDs bool
+}
+
+// LineError is a record that could not be imported
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Skipped int         `json:"skipped"`
+	Failed  int         `json:"failed"`
+	Errors  []LineError `json:"errors,omitempty"`
+}
+
+func (r *ImportReport) fail(line int, err error) {
+	r.Failed++
+	r.Errors = append(r.Errors, LineError{Line: line, Error: err.Error()})
+}
+
+// Store keeps imported articles, see domain.NewsService.ImportNews, which
+// records an event for each of them
+type Store interface {
+	ImportNews(ctx context.Context, news *domain.News, upsert bool) (bool, error)
+}
+
+// Import reads records from r and stores them through store one at a time.
+// Invalid or conflicting records are reported per line in the returned
+// report and do not stop the import; an error is returned only when the input
+// cannot be read at all or ctx is cancelled.
+func Import(ctx context.Context, store Store, r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	report := &ImportReport{}
+
+	add := func(line int, rec *record, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			report.fail(line, err)
+			return nil
+		}
+
+		news, err := rec.news(opts.PreserveIDs)
+		if err != nil {
+			report.fail(line, err)
+			return nil
+		}
+
+		created, err := store.ImportNews(ctx, news, opts.Mode == ModeUpsert)
+		switch {
+		case errors.Is(err, domain.ErrConflict) && opts.Mode != ModeUpsert:
+			report.Skipped++
+		case err != nil:
+			report.fail(line, err)
+		case created:
+			report.Created++
+		default:
+			report.Updated++
+		}
+		return nil
+	}
+
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		err = readCSV(r, add)
+	default:
+		err = readJSONL(r, add)
+	}
+	return report, err
+}
+
+// recordFunc receives each record with its line number, or the error that
+// made the line unreadable
+type recordFunc func(line int, rec *record, err error) error
+
+func readJSONL(r io.Reader, fn recordFunc) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var rec record
+		err := json.Unmarshal([]byte(text), &rec)
+		if err := fn(line, &rec, err); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("line %d: %w", line+1, err)
+	}
+	return nil
+}
+
+func readCSV(r io.Reader, fn recordFunc) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+	index, err := columnIndex(header)
+	if err != nil {
+		return err
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+
+		line, _ := cr.FieldPos(0)
+		var rec *record
+		var parseErr *csv.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			line = parseErr.StartLine
+		case err != nil:
+			return err
+		case len(row) != len(header):
+			err = fmt.Errorf("expected %d fields, got %d", len(header), len(row))
+		default:
+			rec, err = csvRecord(row, index)
+		}
+
+		if err := fn(line, rec, err); err != nil {
+			return err
+		}
+	}
+}
+
+// columnIndex maps known column names to their position in header
+func columnIndex(header []string) (map[string]int, error) {
+	known := make(map[string]bool, len(columns))
+	for _, name := range columns {
+		known[name] = true
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		index[name] = i
+	}
+	for _, name := range []string{"title", "content"} {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+	return index, nil
+}
+
+func csvRecord(row []string, index map[string]int) (*record, error) {
+	field := func(name string) string {
+		if i, ok := index[name]; ok {
+			return row[i]
+		}
+		return ""
+	}
+	timeField := func(name string) (*time.Time, error) {
+		value := field(name)
+		if value == "" {
+			return nil, nil
+		}
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, value)
+		}
+		return &t, nil
+	}
+
+	rec := &record{
+		ID:            field("id"),
+		Title:         field("title"),
+		Content:       field("content"),
+		Slug:          field("slug"),
+		Status:        field("status"),
+		Language:      field("language"),
+		TranslationOf: field("translation_of"),
+	}
+	if tags := field("tags"); tags != "" {
+		rec.Tags = strings.Split(tags, ",")
+	}
+	var err error
+	if rec.PublishedAt, err = timeField("published_at"); err != nil {
+		return nil, err
+	}
+	if rec.CreatedAt, err = timeField("created_at"); err != nil {
+		return nil, err
+	}
+	if rec.UpdatedAt, err = timeField("updated_at"); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
@@ -0,0 +1,113 @@
+package transfer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+func TestImport_JSONL(t *testing.T) {
+	repo := &memRepository{}
+	input := strings.Join([]string{
+		`{"id":"65a000000000000000000001","title":"First article","content":"First article content","created_at":"2020-01-02T03:04:05Z"}`,
+		``,
+		`{"title":"No","content":"Too short"}`,
+		`not json`,
+		`{"title":"Draft article","content":"Draft article content","status":"draft","slug":"draft"}`,
+	}, "\n")
+
+	report, err := Import(context.Background(), repo, strings.NewReader(input), ImportOptions{
+		Format:      FormatJSONL,
+		Mode:        ModeSkip,
+		PreserveIDs: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Contains(t, report.Errors[0].Error, "title: min=3")
+	assert.Equal(t, 4, report.Errors[1].Line)
+
+	require.Len(t, repo.news, 2)
+	assert.Equal(t, "65a000000000000000000001", repo.news[0].ID.Hex())
+	assert.Equal(t, 2020, repo.news[0].CreatedAt.Year())
+	assert.Equal(t, domain.StatusPublished, repo.news[0].Status)
+	assert.NotEmpty(t, repo.news[0].Slug)
+	assert.Nil(t, repo.news[1].PublishedAt)
+}
+
+func TestImport_WithoutPreserve(t *testing.T) {
+	repo := &memRepository{}
+	input := `{"id":"65a000000000000000000001","title":"First article","content":"First article content","language":"uk","translation_of":"65a000000000000000000002","created_at":"2020-01-02T03:04:05Z"}`
+
+	_, err := Import(context.Background(), repo, strings.NewReader(input), ImportOptions{Format: FormatJSONL})
+	require.NoError(t, err)
+
+	require.Len(t, repo.news, 1)
+	assert.NotEqual(t, "65a000000000000000000001", repo.news[0].ID.Hex())
+	assert.Equal(t, "uk", repo.news[0].Language)
+	assert.Nil(t, repo.news[0].TranslationOf, "would point at the old ID")
+	assert.True(t, repo.news[0].CreatedAt.IsZero(), "left for the repository to set")
+}
+
+func TestImport_Modes(t *testing.T) {
+	input := `{"title":"Same article","content":"Original content here","slug":"same"}` + "\n" +
+		`{"title":"Same article","content":"Changed content here","slug":"same"}`
+
+	repo := &memRepository{}
+	report, err := Import(context.Background(), repo, strings.NewReader(input), ImportOptions{Mode: ModeSkip})
+	require.NoError(t, err)
+	assert.Equal(t, ImportReport{Created: 1, Skipped: 1}, *report)
+	assert.Equal(t, "Original content here", repo.news[0].Content)
+
+	repo = &memRepository{}
+	report, err = Import(context.Background(), repo, strings.NewReader(input), ImportOptions{Mode: ModeUpsert})
+	require.NoError(t, err)
+	assert.Equal(t, ImportReport{Created: 1, Updated: 1}, *report)
+	assert.Equal(t, "Changed content here", repo.news[0].Content)
+}
+
+func TestImport_CSV(t *testing.T) {
+	repo := &memRepository{}
+	input := "title,content,status,published_at\n" +
+		"CSV article,\"Content, with a comma\",published,2021-05-06T07:08:09Z\n" +
+		"Bad time,Some longer content,published,yesterday\n" +
+		"Too,many,fields,here,extra\n"
+
+	report, err := Import(context.Background(), repo, strings.NewReader(input), ImportOptions{Format: FormatCSV})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, report.Created)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Contains(t, report.Errors[0].Error, "published_at")
+	assert.Equal(t, 4, report.Errors[1].Line)
+
+	require.Len(t, repo.news, 1)
+	assert.Equal(t, "Content, with a comma", repo.news[0].Content)
+	assert.Equal(t, 2021, repo.news[0].PublishedAt.Year())
+}
+
+func TestImport_CSVHeader(t *testing.T) {
+	_, err := Import(context.Background(), &memRepository{}, strings.NewReader("title,body\n"), ImportOptions{Format: FormatCSV})
+	assert.ErrorContains(t, err, `unknown column "body"`)
+
+	_, err = Import(context.Background(), &memRepository{}, strings.NewReader("title\n"), ImportOptions{Format: FormatCSV})
+	assert.ErrorContains(t, err, `missing column "content"`)
+}
+
+func TestImport_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := `{"title":"First article","content":"First article content"}`
+	_, err := Import(ctx, &memRepository{}, strings.NewReader(input), ImportOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,132 @@
+// Package transfer imports and exports articles as JSON Lines or CSV
+package transfer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// Format is a serialization of articles, one record per line
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// ParseFormat parses a format name. An empty name means JSON Lines.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "jsonl", "ndjson":
+		return FormatJSONL, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("unknown format %q, want jsonl or csv", s)
+}
+
+// FormatFromPath guesses the format from a file extension, defaulting to
+// JSON Lines
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+	return FormatJSONL
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/x-ndjson"
+}
+
+// Mode decides what happens when an imported article already exists
+type Mode string
+
+const (
+	// ModeSkip leaves existing articles untouched
+	ModeSkip Mode = "skip"
+	// ModeUpsert replaces existing articles
+	ModeUpsert Mode = "upsert"
+)
+
+// ParseMode parses a mode name. An empty name means ModeSkip.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(s)) {
+	case "", ModeSkip:
+		return ModeSkip, nil
+	case ModeUpsert:
+		return ModeUpsert, nil
+	}
+	return "", fmt.Errorf("unknown mode %q, want skip or upsert", s)
+}
+
+// columns are the CSV columns, also the fields of a JSON record
+var columns = []string{"id", "title", "content", "slug", "status", "tags", "language", "translation_of", "published_at", "created_at", "updated_at"}
+
+// record is an article as read from a file, before validation
+type record struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Content       string     `json:"content"`
+	Slug          string     `json:"slug"`
+	Status        string     `json:"status"`
+	Tags          []string   `json:"tags"`
+	Language      string     `json:"language"`
+	TranslationOf string     `json:"translation_of"`
+	PublishedAt   *time.Time `json:"published_at"`
+	CreatedAt     *time.Time `json:"created_at"`
+	UpdatedAt     *time.Time `json:"updated_at"`
+}
+
+// news converts the record to a validated article. IDs, translation links
+// and creation and update times are kept only with preserve, as a link to
+// an article under its old ID would lead nowhere.
+func (r *record) news(preserve bool) (*domain.News, error) {
+	news := &domain.News{
+		Title:       r.Title,
+		Content:     r.Content,
+		Slug:        r.Slug,
+		Status:      r.Status,
+		Tags:        domain.NormalizeTags(r.Tags),
+		Language:    r.Language,
+		PublishedAt: r.PublishedAt,
+	}
+
+	if preserve {
+		if r.ID != "" {
+			id, err := primitive.ObjectIDFromHex(r.ID)
+			if err != nil {
+				return nil, fmt.Errorf("invalid id %q", r.ID)
+			}
+			news.ID = id
+		}
+		if r.TranslationOf != "" {
+			original, err := primitive.ObjectIDFromHex(r.TranslationOf)
+			if err != nil {
+				return nil, fmt.Errorf("invalid translation_of %q", r.TranslationOf)
+			}
+			news.TranslationOf = &original
+		}
+		if r.CreatedAt != nil {
+			news.CreatedAt = *r.CreatedAt
+		}
+		if r.UpdatedAt != nil {
+			news.UpdatedAt = *r.UpdatedAt
+		}
+	}
+
+	if err := news.Validate(); err != nil {
+		return nil, err
+	}
+	news.ApplyDefaults()
+	return news, nil
+}
@@ -0,0 +1,95 @@
+package transfer
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+// memRepository keeps articles in memory, enforcing unique IDs and slugs
+type memRepository struct {
+	domain.NewsRepository
+	news []*domain.News
+}
+
+func (m *memRepository) find(news *domain.News) int {
+	for i, existing := range m.news {
+		if existing.ID == news.ID || existing.Slug == news.Slug {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *memRepository) Insert(ctx context.Context, news *domain.News) error {
+	if m.find(news) >= 0 {
+		return domain.ErrConflict
+	}
+	if news.ID.IsZero() {
+		news.ID = primitive.NewObjectID()
+	}
+	m.news = append(m.news, news)
+	return nil
+}
+
+func (m *memRepository) Upsert(ctx context.Context, news *domain.News) (bool, error) {
+	if i := m.find(news); i >= 0 {
+		news.ID = m.news[i].ID
+		m.news[i] = news
+		return false, nil
+	}
+	return true, m.Insert(ctx, news)
+}
+
+// ImportNews stands in for the service
+func (m *memRepository) ImportNews(ctx context.Context, news *domain.News, upsert bool) (bool, error) {
+	if upsert {
+		return m.Upsert(ctx, news)
+	}
+	return true, m.Insert(ctx, news)
+}
+
+func (m *memRepository) Each(ctx context.Context, fn func(*domain.News) error) error {
+	sorted := append([]*domain.News(nil), m.news...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID.Hex() < sorted[j].ID.Hex() })
+	for _, news := range sorted {
+		if err := fn(news); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatJSONL, format)
+
+	_, err = ParseFormat("xml")
+	assert.Error(t, err)
+
+	assert.Equal(t, FormatCSV, FormatFromPath("dump/news.CSV"))
+	assert.Equal(t, FormatJSONL, FormatFromPath("news.jsonl"))
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := ParseMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeSkip, mode)
+
+	mode, err = ParseMode("upsert")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeUpsert, mode)
+
+	_, err = ParseMode("replace")
+	assert.Error(t, err)
+}
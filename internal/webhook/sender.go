@@ -0,0 +1,273 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+	"news_service/internal/events"
+)
+
+// Options tunes a sender
+type Options struct {
+	// Timeout bounds a single request to an endpoint
+	Timeout time.Duration
+	// MaxAttempts is how often a delivery is tried before it is marked failed
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential delay between attempts
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// PollInterval is how often due deliveries are looked for
+	PollInterval time.Duration
+	// AllowPrivate permits endpoints on loopback and private networks
+	AllowPrivate bool
+}
+
+// DefaultOptions returns the options used for zero fields
+func DefaultOptions() Options {
+	return Options{
+		Timeout:      10 * time.Second,
+		MaxAttempts:  10,
+		MinBackoff:   10 * time.Second,
+		MaxBackoff:   time.Hour,
+		PollInterval: time.Second,
+	}
+}
+
+// Sender turns events into deliveries and sends them
+type Sender struct {
+	store  Store
+	client *http.Client
+	opts   Options
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewSender creates a sender over store
+func NewSender(store Store, opts Options, logger *slog.Logger) *Sender {
+	defaults := DefaultOptions()
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaults.Timeout
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaults.MinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaults.MaxBackoff, opts.MinBackoff)
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+
+	return &Sender{
+		store:  store,
+		client: newClient(opts),
+		opts:   opts,
+		logger: logger.With("component", "webhooks"),
+		now:    time.Now,
+	}
+}
+
+// Handle queues a delivery of event to every endpoint that wants it. It is
+// meant to be subscribed to the events dispatcher.
+func (s *Sender) Handle(ctx context.Context, event domain.Event) error {
+	endpoints, err := s.store.ListEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("list webhook endpoints: %w", err)
+	}
+
+	var payload []byte
+	var deliveries []*Delivery
+	now := s.now()
+	for _, endpoint := range endpoints {
+		if !endpoint.Wants(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, &Delivery{
+			ID:            primitive.NewObjectID().Hex(),
+			EndpointID:    endpoint.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        StatusPending,
+			Attempts:      []Attempt{},
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return s.store.Enqueue(ctx, deliveries...)
+}
+
+// Subscribe registers the sender on dispatcher
+func (s *Sender) Subscribe(dispatcher *events.Dispatcher) {
+	dispatcher.Subscribe("webhooks", s.Handle)
+}
+
+// Run sends due deliveries until ctx is done
+func (s *Sender) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SendDue(ctx); err != nil && ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "failed to send webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends every delivery that is due now
+func (s *Sender) SendDue(ctx context.Context) error {
+	for {
+		// The lease covers the request and recording its outcome
+		delivery, err := s.store.Claim(ctx, 2*s.opts.Timeout)
+		if err != nil {
+			return fmt.Errorf("claim delivery: %w", err)
+		}
+		if delivery == nil {
+			return nil
+		}
+		if err := s.deliver(ctx, delivery); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, delivery *Delivery) error {
+	endpoint, err := s.store.GetEndpoint(ctx, delivery.EndpointID)
+	if errors.Is(err, ErrNotFound) {
+		return s.store.RecordAttempt(ctx, delivery.ID,
+			Attempt{At: s.now(), Error: "endpoint removed"}, StatusFailed, time.Time{})
+	}
+	if err != nil {
+		return fmt.Errorf("get endpoint %s: %w", delivery.EndpointID, err)
+	}
+
+	attempt := s.send(ctx, endpoint, delivery)
+	if ctx.Err() != nil {
+		// Shutting down; the lease expires and the delivery is retried
+		return ctx.Err()
+	}
+
+	status, next := StatusSucceeded, time.Time{}
+	attempts := len(delivery.Attempts) + 1
+	if attempt.Error != "" {
+		status = StatusPending
+		next = attempt.At.Add(events.Backoff(attempts, s.opts.MinBackoff, s.opts.MaxBackoff))
+		if attempts >= s.opts.MaxAttempts {
+			status = StatusFailed
+		}
+		s.logger.WarnContext(ctx, "webhook delivery failed",
+			"delivery_id", delivery.ID,
+			"endpoint_id", endpoint.ID,
+			"event_id", delivery.EventID,
+			"attempts", attempts,
+			"status", status,
+			"error", attempt.Error,
+		)
+	}
+
+	if err := s.store.RecordAttempt(ctx, delivery.ID, attempt, status, next); err != nil {
+		return fmt.Errorf("record attempt of %s: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+// send makes one signed request, reporting any failure in the attempt
+func (s *Sender) send(ctx context.Context, endpoint *Endpoint, delivery *Delivery) Attempt {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	attempt := Attempt{At: s.now()}
+	defer func() { attempt.Duration = s.now().Sub(attempt.At) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := attempt.At.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "news-service-webhooks")
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.ResponseCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = "unexpected status " + resp.Status
+	}
+	return attempt
+}
+
+// errPrivateAddress is returned when dialing a blocked address
+var errPrivateAddress = errors.New("webhook endpoint resolves to a private address")
+
+// newClient returns a client that does not follow redirects and, unless
+// opts.AllowPrivate is set, refuses to connect to internal addresses, so
+// endpoints cannot be used to reach services behind the firewall
+func newClient(opts Options) *http.Client {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast()
+}
@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+// receiver records the webhook requests it gets
+type receiver struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	status   int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+func newTestSender(store Store, opts Options) *Sender {
+	opts.AllowPrivate = true
+	return NewSender(store, opts, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func addEndpoint(t *testing.T, store *memStore, url string, eventTypes ...string) *Endpoint {
+	t.Helper()
+	endpoint := &Endpoint{URL: url, Secret: "secret", Events: eventTypes, Active: true}
+	require.NoError(t, store.CreateEndpoint(context.Background(), endpoint))
+	return endpoint
+}
+
+func TestSender_HandleFiltersEndpoints(t *testing.T) {
+	store := newMemStore()
+	all := addEndpoint(t, store, "https://a.example.com")
+	created := addEndpoint(t, store, "https://b.example.com", domain.EventArticleCreated)
+	inactive := &Endpoint{URL: "https://c.example.com"}
+	require.NoError(t, store.CreateEndpoint(context.Background(), inactive))
+
+	sender := newTestSender(store, Options{})
+	event := domain.NewEvent(domain.EventArticleUpdated, "a1", nil)
+	require.NoError(t, sender.Handle(context.Background(), event))
+	// Handling the event again does not queue duplicates
+	require.NoError(t, sender.Handle(context.Background(), event))
+
+	deliveries, err := store.ListDeliveries(context.Background(), all.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, event.ID, deliveries[0].EventID)
+	assert.Equal(t, StatusPending, deliveries[0].Status)
+
+	for _, id := range []string{created.ID, inactive.ID} {
+		deliveries, err := store.ListDeliveries(context.Background(), id, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+	}
+}
+
+func TestSender_SendsSignedRequest(t *testing.T) {
+	recv := &receiver{status: http.StatusNoContent}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	store := newMemStore()
+	endpoint := addEndpoint(t, store, server.URL)
+	sender := newTestSender(store, Options{})
+
+	event := domain.NewEvent(domain.EventArticleCreated, "a1", &domain.News{Title: "Hello"})
+	require.NoError(t, sender.Handle(context.Background(), event))
+	require.NoError(t, sender.SendDue(context.Background()))
+
+	require.Len(t, recv.requests, 1)
+	req, body := recv.requests[0], recv.bodies[0]
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, domain.EventArticleCreated, req.Header.Get(HeaderEvent))
+	assert.JSONEq(t, string(body), string(mustDelivery(t, store, endpoint.ID).Payload))
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.True(t, Verify("secret", timestamp, body, req.Header.Get(HeaderSignature)))
+
+	delivery := mustDelivery(t, store, endpoint.ID)
+	assert.Equal(t, StatusSucceeded, delivery.Status)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusNoContent, delivery.Attempts[0].ResponseCode)
+	assert.Empty(t, delivery.Attempts[0].Error)
+}
+
+func TestSender_RetriesWithBackoffThenFails(t *testing.T) {
+	recv := &receiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	store := newMemStore()
+	endpoint := addEndpoint(t, store, server.URL)
+	sender := newTestSender(store, Options{MaxAttempts: 2, MinBackoff: time.Minute})
+
+	require.NoError(t, sender.Handle(context.Background(), domain.NewEvent(domain.EventArticleDeleted, "a1", nil)))
+	require.NoError(t, sender.SendDue(context.Background()))
+
+	delivery := mustDelivery(t, store, endpoint.ID)
+	assert.Equal(t, StatusPending, delivery.Status)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Equal(t, http.StatusInternalServerError, delivery.Attempts[0].ResponseCode)
+	assert.Contains(t, delivery.Attempts[0].Error, "500")
+	assert.WithinDuration(t, time.Now().Add(time.Minute), delivery.NextAttemptAt, 5*time.Second)
+
+	// Not due yet
+	require.NoError(t, sender.SendDue(context.Background()))
+	assert.Len(t, recv.requests, 1)
+
+	store.deliveries[delivery.ID].NextAttemptAt = time.Now()
+	require.NoError(t, sender.SendDue(context.Background()))
+
+	delivery = mustDelivery(t, store, endpoint.ID)
+	assert.Equal(t, StatusFailed, delivery.Status)
+	assert.Len(t, delivery.Attempts, 2)
+
+	// A manual redelivery sends it once more
+	recv.status = http.StatusOK
+	require.NoError(t, store.Redeliver(context.Background(), delivery.ID))
+	require.NoError(t, sender.SendDue(context.Background()))
+	assert.Equal(t, StatusSucceeded, mustDelivery(t, store, endpoint.ID).Status)
+}
+
+func TestSender_BlocksPrivateAddresses(t *testing.T) {
+	recv := &receiver{status: http.StatusOK}
+	server := httptest.NewServer(recv)
+	defer server.Close()
+
+	store := newMemStore()
+	endpoint := addEndpoint(t, store, server.URL)
+	sender := NewSender(store, Options{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	require.NoError(t, sender.Handle(context.Background(), domain.NewEvent(domain.EventArticleCreated, "a1", nil)))
+	require.NoError(t, sender.SendDue(context.Background()))
+
+	assert.Empty(t, recv.requests)
+	delivery := mustDelivery(t, store, endpoint.ID)
+	require.Len(t, delivery.Attempts, 1)
+	assert.Contains(t, delivery.Attempts[0].Error, "private address")
+}
+
+func mustDelivery(t *testing.T, store *memStore, endpointID string) *Delivery {
+	t.Helper()
+	deliveries, err := store.ListDeliveries(context.Background(), endpointID, 1)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	return deliveries[0]
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	sig := Sign("secret", 1700000000, []byte(`{"a":1}`))
+	assert.Equal(t, "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686", sig)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	sig := Sign("secret", 1700000000, body)
+
+	assert.True(t, Verify("secret", 1700000000, body, sig))
+	assert.False(t, Verify("other", 1700000000, body, sig))
+	assert.False(t, Verify("secret", 1700000001, body, sig))
+	assert.False(t, Verify("secret", 1700000000, []byte(`{"a":2}`), sig))
+	assert.False(t, Verify("secret", 1700000000, body, sig[len("sha256="):]))
+}
@@ -0,0 +1,202 @@
+// Package webhook notifies registered HTTP endpoints about article events
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"news_service/internal/domain"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+var (
+	// ErrNotFound is returned for unknown endpoints and deliveries
+	ErrNotFound = errors.New("not found")
+	// ErrInvalid is returned for endpoints that cannot be registered
+	ErrInvalid = errors.New("invalid webhook")
+)
+
+// Endpoint is a registered webhook receiver
+type Endpoint struct {
+	ID          string `json:"id"`
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	// Events lists the event types sent to the endpoint, all when empty
+	Events []string `json:"events,omitempty"`
+	// Secret signs every payload, see Sign
+	Secret    string    `json:"secret,omitempty"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Wants reports whether the endpoint subscribed to events of eventType
+func (e *Endpoint) Wants(eventType string) bool {
+	if !e.Active {
+		return false
+	}
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the URL and event filter
+func (e *Endpoint) Validate() error {
+	u, err := url.Parse(e.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalid)
+	}
+	for _, t := range e.Events {
+		switch t {
+		case domain.EventArticleCreated, domain.EventArticleUpdated, domain.EventArticleDeleted:
+		default:
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalid, t)
+		}
+	}
+	return nil
+}
+
+// Attempt is one try at sending a delivery
+type Attempt struct {
+	At           time.Time     `json:"at" bson:"at"`
+	ResponseCode int           `json:"response_code,omitempty" bson:"response_code,omitempty"`
+	Error        string        `json:"error,omitempty" bson:"error,omitempty"`
+	Duration     time.Duration `json:"duration" bson:"duration"`
+}
+
+// Delivery is an event sent, or to be sent, to one endpoint
+type Delivery struct {
+	ID         string `json:"id"`
+	EndpointID string `json:"endpoint_id"`
+	EventID    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	// Payload is the request body, kept so redeliveries send the same
+	Payload       []byte    `json:"-"`
+	Status        string    `json:"status"`
+	Attempts      []Attempt `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Store persists endpoints and their deliveries
+type Store interface {
+	CreateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	GetEndpoint(ctx context.Context, id string) (*Endpoint, error)
+	ListEndpoints(ctx context.Context) ([]*Endpoint, error)
+	UpdateEndpoint(ctx context.Context, endpoint *Endpoint) error
+	// DeleteEndpoint removes the endpoint and its deliveries
+	DeleteEndpoint(ctx context.Context, id string) error
+
+	// Enqueue stores new deliveries, ignoring any that already exist for the
+	// same endpoint and event
+	Enqueue(ctx context.Context, deliveries ...*Delivery) error
+	// Claim takes the oldest due pending delivery for lease, so that no
+	// other sender picks it up meanwhile, or returns nil when there is none
+	Claim(ctx context.Context, lease time.Duration) (*Delivery, error)
+	// RecordAttempt appends attempt and sets the status and next attempt time
+	RecordAttempt(ctx context.Context, id string, attempt Attempt, status string, next time.Time) error
+	GetDelivery(ctx context.Context, id string) (*Delivery, error)
+	// ListDeliveries returns the newest deliveries of an endpoint first
+	ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*Delivery, error)
+	// Redeliver makes a delivery pending and due now
+	Redeliver(ctx context.Context, id string) error
+}
+
+// Service manages endpoints on behalf of administrators
+type Service struct {
+	store Store
+}
+
+// NewService creates a service over store
+func NewService(store Store) *Service {
+	return &Service{store: store}
+}
+
+// Create registers an endpoint, generating a secret when none is given
+func (s *Service) Create(ctx context.Context, endpoint *Endpoint) error {
+	if err := endpoint.Validate(); err != nil {
+		return err
+	}
+	if endpoint.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return err
+		}
+		endpoint.Secret = secret
+	}
+	return s.store.CreateEndpoint(ctx, endpoint)
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Endpoint, error) {
+	return s.store.GetEndpoint(ctx, id)
+}
+
+func (s *Service) List(ctx context.Context) ([]*Endpoint, error) {
+	return s.store.ListEndpoints(ctx)
+}
+
+// Update replaces the settings of an endpoint, keeping its secret when
+// endpoint.Secret is empty
+func (s *Service) Update(ctx context.Context, endpoint *Endpoint) error {
+	if err := endpoint.Validate(); err != nil {
+		return err
+	}
+	existing, err := s.store.GetEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		return err
+	}
+	if endpoint.Secret == "" {
+		endpoint.Secret = existing.Secret
+	}
+	endpoint.CreatedAt = existing.CreatedAt
+	return s.store.UpdateEndpoint(ctx, endpoint)
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	return s.store.DeleteEndpoint(ctx, id)
+}
+
+func (s *Service) Deliveries(ctx context.Context, endpointID string, limit int) ([]*Delivery, error) {
+	if _, err := s.store.GetEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.store.ListDeliveries(ctx, endpointID, limit)
+}
+
+// Redeliver queues a delivery of the endpoint to be sent again
+func (s *Service) Redeliver(ctx context.Context, endpointID, deliveryID string) error {
+	delivery, err := s.store.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return err
+	}
+	if delivery.EndpointID != endpointID {
+		return ErrNotFound
+	}
+	return s.store.Redeliver(ctx, deliveryID)
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
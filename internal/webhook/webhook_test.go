@@ -0,0 +1,251 @@
+package webhook
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"news_service/internal/domain"
+)
+
+// memStore is an in-memory Store
+type memStore struct {
+	mu         sync.Mutex
+	endpoints  map[string]*Endpoint
+	deliveries map[string]*Delivery
+	nextID     int
+}
+
+func newMemStore() *memStore {
+	return &memStore{endpoints: make(map[string]*Endpoint), deliveries: make(map[string]*Delivery)}
+}
+
+func (s *memStore) CreateEndpoint(ctx context.Context, endpoint *Endpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	endpoint.ID = "ep" + strconv.Itoa(s.nextID)
+	copied := *endpoint
+	s.endpoints[endpoint.ID] = &copied
+	return nil
+}
+
+func (s *memStore) GetEndpoint(ctx context.Context, id string) (*Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	endpoint, ok := s.endpoints[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *endpoint
+	return &copied, nil
+}
+
+func (s *memStore) ListEndpoints(ctx context.Context) ([]*Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var endpoints []*Endpoint
+	for _, endpoint := range s.endpoints {
+		copied := *endpoint
+		endpoints = append(endpoints, &copied)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].ID < endpoints[j].ID })
+	return endpoints, nil
+}
+
+func (s *memStore) UpdateEndpoint(ctx context.Context, endpoint *Endpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.endpoints[endpoint.ID]; !ok {
+		return ErrNotFound
+	}
+	copied := *endpoint
+	s.endpoints[endpoint.ID] = &copied
+	return nil
+}
+
+func (s *memStore) DeleteEndpoint(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.endpoints[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.endpoints, id)
+	return nil
+}
+
+func (s *memStore) Enqueue(ctx context.Context, deliveries ...*Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, delivery := range deliveries {
+		duplicate := false
+		for _, existing := range s.deliveries {
+			if existing.EndpointID == delivery.EndpointID && existing.EventID == delivery.EventID {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			copied := *delivery
+			s.deliveries[delivery.ID] = &copied
+		}
+	}
+	return nil
+}
+
+func (s *memStore) Claim(ctx context.Context, lease time.Duration) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var due *Delivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status != StatusPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if due == nil || delivery.ID < due.ID {
+			due = delivery
+		}
+	}
+	if due == nil {
+		return nil, nil
+	}
+	due.NextAttemptAt = now.Add(lease)
+	copied := *due
+	return &copied, nil
+}
+
+func (s *memStore) RecordAttempt(ctx context.Context, id string, attempt Attempt, status string, next time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery := s.deliveries[id]
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.Status = status
+	delivery.NextAttemptAt = next
+	return nil
+}
+
+func (s *memStore) GetDelivery(ctx context.Context, id string) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	copied := *delivery
+	return &copied, nil
+}
+
+func (s *memStore) ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var deliveries []*Delivery
+	for _, delivery := range s.deliveries {
+		if delivery.EndpointID == endpointID {
+			copied := *delivery
+			deliveries = append(deliveries, &copied)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (s *memStore) Redeliver(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delivery.Status = StatusPending
+	delivery.NextAttemptAt = time.Now()
+	return nil
+}
+
+func TestEndpoint_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint Endpoint
+		wantErr  bool
+	}{
+		{"https", Endpoint{URL: "https://example.com/hook"}, false},
+		{"http with filter", Endpoint{URL: "http://example.com", Events: []string{domain.EventArticleCreated}}, false},
+		{"relative", Endpoint{URL: "/hook"}, true},
+		{"other scheme", Endpoint{URL: "ftp://example.com"}, true},
+		{"unknown event", Endpoint{URL: "https://example.com", Events: []string{"ArticleRead"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.endpoint.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalid)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEndpoint_Wants(t *testing.T) {
+	all := Endpoint{Active: true}
+	assert.True(t, all.Wants(domain.EventArticleDeleted))
+
+	filtered := Endpoint{Active: true, Events: []string{domain.EventArticleCreated}}
+	assert.True(t, filtered.Wants(domain.EventArticleCreated))
+	assert.False(t, filtered.Wants(domain.EventArticleUpdated))
+
+	inactive := Endpoint{}
+	assert.False(t, inactive.Wants(domain.EventArticleCreated))
+}
+
+func TestService_CreateGeneratesSecret(t *testing.T) {
+	store := newMemStore()
+	service := NewService(store)
+
+	endpoint := &Endpoint{URL: "https://example.com/hook", Active: true}
+	require.NoError(t, service.Create(context.Background(), endpoint))
+	assert.NotEmpty(t, endpoint.ID)
+	assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, endpoint.Secret)
+
+	err := service.Create(context.Background(), &Endpoint{URL: "not a url"})
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestService_UpdateKeepsSecret(t *testing.T) {
+	store := newMemStore()
+	service := NewService(store)
+	ctx := context.Background()
+
+	endpoint := &Endpoint{URL: "https://example.com/hook", Secret: "s3cret", Active: true}
+	require.NoError(t, service.Create(ctx, endpoint))
+
+	update := &Endpoint{ID: endpoint.ID, URL: "https://example.com/other"}
+	require.NoError(t, service.Update(ctx, update))
+
+	stored, err := service.Get(ctx, endpoint.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/other", stored.URL)
+	assert.Equal(t, "s3cret", stored.Secret)
+	assert.False(t, stored.Active)
+}
+
+func TestService_RedeliverChecksEndpoint(t *testing.T) {
+	store := newMemStore()
+	service := NewService(store)
+	ctx := context.Background()
+	require.NoError(t, store.Enqueue(ctx, &Delivery{ID: "d1", EndpointID: "ep1", Status: StatusFailed}))
+
+	assert.ErrorIs(t, service.Redeliver(ctx, "ep2", "d1"), ErrNotFound)
+	require.NoError(t, service.Redeliver(ctx, "ep1", "d1"))
+
+	delivery, err := store.GetDelivery(ctx, "d1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, delivery.Status)
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Func is a long running background task. It must return once ctx is done.
+type Func func(ctx context.Context) error
+
+// Group runs background workers and stops them together
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	failed map[string]error
+}
+
+// NewGroup creates an empty worker group
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+		failed: make(map[string]error),
+	}
+}
+
+// Go starts fn in its own goroutine. A worker that returns before the group
+// is stopped is recorded as failed and makes Check report an error.
+func (g *Group) Go(name string, fn Func) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		err := fn(g.ctx)
+		if g.ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = fmt.Errorf("worker exited")
+		}
+
+		slog.Error("background worker stopped", "worker", name, "error", err)
+		g.mu.Lock()
+		g.failed[name] = err
+		g.mu.Unlock()
+	}()
+}
+
+// Check reports an error if any worker has stopped unexpectedly
+func (g *Group) Check(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for name, err := range g.failed {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if g.ctx.Err() != nil {
+		return fmt.Errorf("workers stopped")
+	}
+	return nil
+}
+
+// Stop cancels all workers and waits for them to return or for ctx to expire
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for workers: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_Stop(t *testing.T) {
+	group := NewGroup()
+
+	stopped := make(chan struct{})
+	group.Go("ticker", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	})
+	assert.NoError(t, group.Check(context.Background()))
+
+	require.NoError(t, group.Stop(context.Background()))
+	<-stopped
+	assert.Error(t, group.Check(context.Background()))
+}
+
+func TestGroup_StopDeadline(t *testing.T) {
+	group := NewGroup()
+
+	release := make(chan struct{})
+	defer close(release)
+	group.Go("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, group.Stop(ctx), context.DeadlineExceeded)
+}
+
+func TestGroup_Failure(t *testing.T) {
+	group := NewGroup()
+	defer group.Stop(context.Background())
+
+	group.Go("broken", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+
+	assert.Eventually(t, func() bool {
+		return group.Check(context.Background()) != nil
+	}, time.Second, time.Millisecond)
+	assert.EqualError(t, group.Check(context.Background()), "broken: boom")
+}
@@ -13,6 +13,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"news_service/internal/broadcast"
 	"news_service/internal/domain"
 	"news_service/internal/handler"
 	"news_service/internal/repository/mongodb"
@@ -34,7 +35,9 @@ func setupTestEnvironment(t *testing.T) (*gin.Engine, func()) {
 
 	// Initialize dependencies
 	repo := mongodb.NewNewsRepository(client, "test_news_service")
-	newsService := service.NewNewsService(repo)
+	transactor, _, err := mongodb.NewTransactor(ctx, client)
+	require.NoError(t, err)
+	newsService := service.NewNewsService(repo, mongodb.NewOutbox(client, "test_news_service"), transactor, broadcast.New(broadcast.DefaultBuffer))
 	newsHandler := handler.NewNewsHandler(newsService)
 
 	// Setup router
@@ -0,0 +1,23 @@
+// Behaviour kept out of the markup, which the Content Security Policy keeps
+// free of inline scripts
+
+// <input type="checkbox" data-select-all="selector"> checks or unchecks every
+// checkbox matching the selector
+document.addEventListener("change", (event) => {
+    const selector = event.target.dataset && event.target.dataset.selectAll;
+    if (selector) {
+        document.querySelectorAll(selector).forEach((box) => {
+            box.checked = event.target.checked;
+        });
+    }
+});
+
+// <a data-history-back> goes back a page, following its href when there is
+// no page to go back to
+document.addEventListener("click", (event) => {
+    const link = event.target.closest("[data-history-back]");
+    if (link && window.history.length > 1) {
+        event.preventDefault();
+        window.history.back();
+    }
+});
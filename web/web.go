@@ -0,0 +1,113 @@
+// Package web embeds the HTML templates and static assets so the binary runs
+// from any directory.
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed templates static
+var files embed.FS
+
+// TemplatePatterns match every template under the templates root
+var TemplatePatterns = []string{"*.html", "*/*.html"}
+
+// Templates returns the embedded templates directory
+func Templates() fs.FS {
+	return sub("templates")
+}
+
+// Static returns the embedded static assets directory
+func Static() fs.FS {
+	return sub("static")
+}
+
+// assetRef matches a reference to a static file in a template
+var assetRef = regexp.MustCompile(`\{\{-?\s*asset\s+"([^"]+)"`)
+
+// Checksums lists the SHA-256 of every vendored library, in sha256sum format
+const Checksums = "vendor/SHA256SUMS"
+
+// CheckAssets returns an error naming every static file the templates refer
+// to with asset that static does not have, or a vendored library that does
+// not match its pinned checksum. The vendored libraries are committed, make
+// assets fetches and verifies them.
+func CheckAssets(templates, static fs.FS) error {
+	if err := checkSums(static); err != nil {
+		return err
+	}
+
+	missing := make(map[string]bool)
+	for _, pattern := range TemplatePatterns {
+		names, err := fs.Glob(templates, pattern)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			data, err := fs.ReadFile(templates, name)
+			if err != nil {
+				return err
+			}
+			for _, match := range assetRef.FindAllStringSubmatch(string(data), -1) {
+				if _, err := fs.Stat(static, strings.TrimPrefix(match[1], "/")); err != nil {
+					missing[match[1]] = true
+				}
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("missing static assets %s, run make assets", strings.Join(names, ", "))
+}
+
+// checkSums verifies the files listed in Checksums, if static has it
+func checkSums(static fs.FS) error {
+	sums, err := fs.ReadFile(static, Checksums)
+	if err != nil {
+		return nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if !ok {
+			continue
+		}
+		name = "vendor/" + strings.TrimLeft(name, " *")
+		data, err := fs.ReadFile(static, name)
+		if err != nil {
+			// Reported as missing when a template refers to it
+			continue
+		}
+		got := sha256.Sum256(data)
+		if hex.EncodeToString(got[:]) != sum {
+			return fmt.Errorf("static asset %s does not match its checksum in %s", name, Checksums)
+		}
+	}
+	return scanner.Err()
+}
+
+func sub(dir string) fs.FS {
+	fsys, err := fs.Sub(files, dir)
+	if err != nil {
+		// Only fails for invalid paths, and dir is a constant
+		panic(err)
+	}
+	return fsys
+}
@@ -0,0 +1,130 @@
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"io/fs"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"news_service/internal/domain"
+)
+
+func parseTemplates(t *testing.T) *template.Template {
+	t.Helper()
+	funcs := template.FuncMap{
+		"subtract": func(a, b int) int { return a - b },
+		"add":      func(a, b int) int { return a + b },
+		"multiply": func(a, b int) int { return a * b },
+		"asset":    func(name string) string { return "/static/" + name },
+		"cspNonce": func() string { return "nonce" },
+	}
+	templates, err := template.New("").Funcs(funcs).ParseFS(Templates(), TemplatePatterns...)
+	require.NoError(t, err)
+	return templates
+}
+
+func TestTemplates(t *testing.T) {
+	templates := parseTemplates(t)
+	for _, name := range []string{"error.html", "csrf.html", "news/list.html", "news/view.html", "docs/index.html"} {
+		assert.NotNil(t, templates.Lookup(name), name)
+	}
+}
+
+func TestTemplates_Layout(t *testing.T) {
+	templates := parseTemplates(t)
+	news := &domain.News{ID: primitive.NewObjectID(), Title: "Title", Content: "Content", Language: "en"}
+	pages := map[string]any{
+		"error.html":          map[string]any{"error": "Failed"},
+		"csrf.html":           map[string]any{"error": "Expired"},
+		"news/list.html":      map[string]any{"News": []*domain.News{news}, "Total": 1, "Page": 1, "Limit": 10},
+		"news/view.html":      map[string]any{"News": news, "Translations": []*domain.News{news}},
+		"news/create.html":    nil,
+		"news/edit.html":      map[string]any{"News": news},
+		"news/translate.html": map[string]any{"ID": news.ID.Hex()},
+	}
+	for name, data := range pages {
+		var buf strings.Builder
+		require.NoError(t, templates.ExecuteTemplate(&buf, name, data), name)
+		page := buf.String()
+		assert.True(t, strings.HasPrefix(page, "<!DOCTYPE html>"), name)
+		assert.Contains(t, page, `<script src="/static/vendor/htmx.min.js"></script>`, name)
+		assert.Contains(t, page, `<script src="/static/vendor/sse.js"></script>`, name)
+		assert.Contains(t, page, `<script nonce="nonce">`, name)
+		assert.Contains(t, page, `<script src="/static/js/main.js" defer></script>`, name)
+		assert.True(t, strings.HasSuffix(strings.TrimSpace(page), "</html>"), name)
+	}
+}
+
+func TestTemplates_Alternates(t *testing.T) {
+	templates := parseTemplates(t)
+	news := &domain.News{ID: primitive.NewObjectID(), Title: "Title", Content: "Content", Language: "en"}
+	translation := &domain.News{ID: primitive.NewObjectID(), Title: "Заголовок", Content: "Зміст", Language: "uk"}
+
+	var buf strings.Builder
+	require.NoError(t, templates.ExecuteTemplate(&buf, "news/view.html", map[string]any{
+		"News":         news,
+		"Translations": []*domain.News{news, translation},
+		"Alternates":   []*domain.News{translation},
+	}))
+	head, _, found := strings.Cut(buf.String(), "</head>")
+	require.True(t, found)
+	assert.Contains(t, head, `<link rel="alternate" hreflang="uk" href="/uk/news/`+translation.ID.Hex()+`">`)
+	assert.Equal(t, 1, strings.Count(buf.String(), `rel="alternate"`))
+}
+
+func TestTemplates_NoExternalAssets(t *testing.T) {
+	// The CSP only allows this origin, and vendored files are pinned
+	external := regexp.MustCompile(`(?:src|href)="(?:https?:)?//`)
+	for _, pattern := range TemplatePatterns {
+		names, err := fs.Glob(Templates(), pattern)
+		require.NoError(t, err)
+		for _, name := range names {
+			data, err := fs.ReadFile(Templates(), name)
+			require.NoError(t, err)
+			assert.Empty(t, external.FindAllString(string(data), -1), name)
+		}
+	}
+}
+
+func TestStatic(t *testing.T) {
+	_, err := fs.Stat(Static(), "css/main.css")
+	assert.NoError(t, err)
+}
+
+func TestCheckAssets(t *testing.T) {
+	// Every file the templates link to is embedded
+	assert.NoError(t, CheckAssets(Templates(), Static()))
+
+	templates := fstest.MapFS{
+		"layout.html":    {Data: []byte(`<script src="{{asset "js/app.js"}}"></script>{{- asset "css/app.css"}}`)},
+		"news/list.html": {Data: []byte(`<script src="{{ asset "/js/app.js" }}"></script>`)},
+	}
+	static := fstest.MapFS{"js/app.js": {}}
+	assert.NoError(t, CheckAssets(templates, fstest.MapFS{"js/app.js": {}, "css/app.css": {}}))
+	assert.EqualError(t, CheckAssets(templates, static), "missing static assets css/app.css, run make assets")
+}
+
+func TestCheckAssets_Checksums(t *testing.T) {
+	templates := fstest.MapFS{
+		"layout.html": {Data: []byte(`<script src="{{asset "vendor/lib.js"}}"></script>`)},
+	}
+	sum := sha256.Sum256([]byte("lib"))
+	sums := &fstest.MapFile{Data: []byte(hex.EncodeToString(sum[:]) + "  lib.js\n")}
+
+	assert.NoError(t, CheckAssets(templates, fstest.MapFS{
+		"vendor/lib.js":     {Data: []byte("lib")},
+		"vendor/SHA256SUMS": sums,
+	}))
+	assert.EqualError(t, CheckAssets(templates, fstest.MapFS{
+		"vendor/lib.js":     {Data: []byte("tampered")},
+		"vendor/SHA256SUMS": sums,
+	}), "static asset vendor/lib.js does not match its checksum in vendor/SHA256SUMS")
+}